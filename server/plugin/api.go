@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"runtime/debug"
 	"strconv"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/google/go-github/v41/github"
 	"github.com/gorilla/mux"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 
@@ -160,6 +162,13 @@ func (p *Plugin) initializeAPI() {
 	apiRouter.HandleFunc("/pr", p.checkAuth(p.attachUserContext(p.getPrByNumber), ResponseTypePlain)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/lhs-content", p.checkAuth(p.attachUserContext(p.getSidebarContent), ResponseTypePlain)).Methods(http.MethodGet)
 
+	apiRouter.HandleFunc("/admin/subscriptions", p.checkAuth(p.checkSysAdmin(p.attachContext(p.getAdminSubscriptions)), ResponseTypeJSON)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/admin/subscriptions", p.checkAuth(p.checkSysAdmin(p.attachContext(p.deleteAdminSubscriptions)), ResponseTypeJSON)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/admin/subscriptions/export", p.checkAuth(p.checkSysAdmin(p.attachContext(p.exportSubscriptionsConfig)), ResponseTypeJSON)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/admin/subscriptions/import", p.checkAuth(p.checkSysAdmin(p.attachContext(p.importSubscriptionsConfig)), ResponseTypeJSON)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/admin/subscriptions/import/dialog", p.checkAuth(p.checkSysAdmin(p.attachContext(p.submitImportSubscriptionsDialog)), ResponseTypeJSON)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/admin/usermap/import", p.checkAuth(p.checkSysAdmin(p.attachContext(p.importUserMappings)), ResponseTypeJSON)).Methods(http.MethodPost)
+
 	apiRouter.HandleFunc("/config", checkPluginRequest(p.getConfig)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/token", checkPluginRequest(p.getToken)).Methods(http.MethodGet)
 }
@@ -211,6 +220,27 @@ func (p *Plugin) checkAuth(handler http.HandlerFunc, responseType ResponseType)
 	}
 }
 
+// checkSysAdmin only lets requests of System Admins through. It must be wrapped by checkAuth.
+func (p *Plugin) checkSysAdmin(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Header.Get("Mattermost-User-ID")
+
+		isSysAdmin, err := p.isAuthorizedSysAdmin(userID)
+		if err != nil {
+			p.client.Log.Warn("Failed to check if user is System Admin", "error", err.Error())
+			p.writeAPIError(w, &APIErrorResponse{ID: "", Message: "Error checking user's permissions.", StatusCode: http.StatusInternalServerError})
+			return
+		}
+
+		if !isSysAdmin {
+			p.writeAPIError(w, &APIErrorResponse{ID: "", Message: "Only System Admins are allowed to use this endpoint.", StatusCode: http.StatusForbidden})
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
 func (p *Plugin) createContext(_ http.ResponseWriter, r *http.Request) (*Context, context.CancelFunc) {
 	userID := r.Header.Get("Mattermost-User-ID")
 
@@ -435,7 +465,7 @@ func (p *Plugin) completeConnectUserToGitHub(c *Context, w http.ResponseWriter,
 		c.Log.WithError(err).Warnf("Failed to store GitHub user info mapping")
 	}
 
-	flow := p.flowManager.setupFlow.ForUser(c.UserID)
+	flow := p.flowManager.getFlows(c.UserID).setupFlow.ForUser(c.UserID)
 
 	stepName, err := flow.GetCurrentStep()
 	if err != nil {
@@ -450,31 +480,35 @@ func (p *Plugin) completeConnectUserToGitHub(c *Context, w http.ResponseWriter,
 	} else {
 		// Only post introduction message if no setup wizard is running
 
+		locale := p.getUserLocale(state.UserID)
+
 		var commandHelp string
-		commandHelp, err = renderTemplate("helpText", p.getConfiguration())
+		commandHelp, err = renderLocalizedTemplate(locale, "helpText", p.getConfiguration())
 		if err != nil {
 			c.Log.WithError(err).Warnf("Failed to render help template")
 		}
 
-		message := fmt.Sprintf("#### Welcome to the Mattermost GitHub Plugin!\n"+
-			"You've connected your Mattermost account to [%s](%s) on GitHub. Read about the features of this plugin below:\n\n"+
-			"##### Daily Reminders\n"+
-			"The first time you log in each day, you'll get a post right here letting you know what messages you need to read and what pull requests are awaiting your review.\n"+
-			"Turn off reminders with `/github settings reminders off`.\n\n"+
-			"##### Notifications\n"+
-			"When someone mentions you, requests your review, comments on or modifies one of your pull requests/issues, or assigns you, you'll get a post here about it.\n"+
-			"Turn off notifications with `/github settings notifications off`.\n\n"+
-			"##### Sidebar Buttons\n"+
-			"Check out the buttons in the left-hand sidebar of Mattermost.\n"+
-			"It shows your Open PRs, PRs that are awaiting your review, issues assigned to you, and all your unread messages you have in GitHub. \n"+
-			"* The first button tells you how many pull requests you have submitted.\n"+
-			"* The second shows the number of PR that are awaiting your review.\n"+
-			"* The third shows the number of PR and issues your are assiged to.\n"+
-			"* The fourth tracks the number of unread messages you have.\n"+
-			"* The fifth will refresh the numbers.\n\n"+
-			"Click on them!\n\n"+
-			"##### Slash Commands\n"+
-			commandHelp, gitUser.GetLogin(), gitUser.GetHTMLURL())
+		message := p.localizeWithData(p.getLocalizer(locale), &i18n.Message{
+			ID: "welcome",
+			Other: "#### Welcome to the Mattermost GitHub Plugin!\n" +
+				"You've connected your Mattermost account to [{{.Username}}]({{.URL}}) on GitHub. Read about the features of this plugin below:\n\n" +
+				"##### Daily Reminders\n" +
+				"The first time you log in each day, you'll get a post right here letting you know what messages you need to read and what pull requests are awaiting your review.\n" +
+				"Turn off reminders with `/github settings reminders off`.\n\n" +
+				"##### Notifications\n" +
+				"When someone mentions you, requests your review, comments on or modifies one of your pull requests/issues, or assigns you, you'll get a post here about it.\n" +
+				"Turn off notifications with `/github settings notifications off`.\n\n" +
+				"##### Sidebar Buttons\n" +
+				"Check out the buttons in the left-hand sidebar of Mattermost.\n" +
+				"It shows your Open PRs, PRs that are awaiting your review, issues assigned to you, and all your unread messages you have in GitHub. \n" +
+				"* The first button tells you how many pull requests you have submitted.\n" +
+				"* The second shows the number of PR that are awaiting your review.\n" +
+				"* The third shows the number of PR and issues your are assiged to.\n" +
+				"* The fourth tracks the number of unread messages you have.\n" +
+				"* The fifth will refresh the numbers.\n\n" +
+				"Click on them!\n\n" +
+				"##### Slash Commands\n",
+		}, map[string]interface{}{"Username": gitUser.GetLogin(), "URL": gitUser.GetHTMLURL()}) + commandHelp
 
 		p.CreateBotDMPost(state.UserID, message, "custom_git_welcome")
 	}
@@ -1006,6 +1040,13 @@ func (p *Plugin) updateSettings(c *UserContext, w http.ResponseWriter, r *http.R
 		return
 	}
 
+	for _, notificationType := range settings.DisabledNotifications {
+		if !containsValue(notificationTypes, notificationType) {
+			http.Error(w, "Unknown notification type "+notificationType, http.StatusBadRequest)
+			return
+		}
+	}
+
 	info := c.GHInfo
 	info.Settings = settings
 
@@ -1418,6 +1459,203 @@ func (p *Plugin) createIssue(c *UserContext, w http.ResponseWriter, r *http.Requ
 	p.writeJSON(w, result)
 }
 
+func (p *Plugin) getAdminSubscriptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	filter := SubscriptionFilter{
+		Repository: r.URL.Query().Get("repo"),
+		Team:       r.URL.Query().Get("team"),
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		p.writeAPIError(w, &APIErrorResponse{Message: "Invalid param 'format'.", StatusCode: http.StatusBadRequest})
+		return
+	}
+
+	infos, err := p.GetSubscriptionInfos(filter)
+	if err != nil {
+		c.Log.WithError(err).Warnf("Failed to get subscriptions")
+		p.writeAPIError(w, &APIErrorResponse{Message: "Failed to get subscriptions", StatusCode: http.StatusInternalServerError})
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="subscriptions.csv"`)
+		if err := writeSubscriptionInfosCSV(w, infos); err != nil {
+			c.Log.WithError(err).Warnf("Failed to write subscriptions as CSV")
+		}
+		return
+	}
+
+	p.writeJSON(w, infos)
+}
+
+func (p *Plugin) deleteAdminSubscriptions(c *Context, w http.ResponseWriter, r *http.Request) {
+	filter := SubscriptionFilter{
+		Repository: r.URL.Query().Get("repo"),
+		Team:       r.URL.Query().Get("team"),
+	}
+
+	if filter.IsEmpty() {
+		p.writeAPIError(w, &APIErrorResponse{Message: "Please provide at least one of the params 'repo' or 'team'.", StatusCode: http.StatusBadRequest})
+		return
+	}
+
+	removed, err := p.RemoveFilteredSubscriptions(filter)
+	if err != nil {
+		c.Log.WithError(err).Warnf("Failed to delete subscriptions")
+		p.writeAPIError(w, &APIErrorResponse{Message: "Failed to delete subscriptions", StatusCode: http.StatusInternalServerError})
+		return
+	}
+
+	resp := struct {
+		Removed int `json:"removed"`
+	}{removed}
+
+	p.writeJSON(w, resp)
+}
+
+func (p *Plugin) exportSubscriptionsConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	filter := SubscriptionFilter{
+		Repository: r.URL.Query().Get("repo"),
+		Team:       r.URL.Query().Get("team"),
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = subscriptionsConfigFormatYAML
+	}
+	if format != subscriptionsConfigFormatYAML && format != subscriptionsConfigFormatJSON {
+		p.writeAPIError(w, &APIErrorResponse{Message: "Invalid param 'format'.", StatusCode: http.StatusBadRequest})
+		return
+	}
+
+	config, err := p.ExportSubscriptionsConfig(filter)
+	if err != nil {
+		c.Log.WithError(err).Warnf("Failed to export subscriptions")
+		p.writeAPIError(w, &APIErrorResponse{Message: "Failed to export subscriptions", StatusCode: http.StatusInternalServerError})
+		return
+	}
+
+	data, err := marshalSubscriptionsConfig(config, format)
+	if err != nil {
+		c.Log.WithError(err).Warnf("Failed to marshal subscriptions")
+		p.writeAPIError(w, &APIErrorResponse{Message: "Failed to export subscriptions", StatusCode: http.StatusInternalServerError})
+		return
+	}
+
+	if format == subscriptionsConfigFormatYAML {
+		w.Header().Set("Content-Type", "application/yaml")
+	}
+
+	if _, err := w.Write(data); err != nil {
+		c.Log.WithError(err).Warnf("Failed to write subscriptions")
+	}
+}
+
+func (p *Plugin) importSubscriptionsConfig(c *Context, w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	prune, _ := strconv.ParseBool(r.URL.Query().Get("prune"))
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, subscriptionsConfigMaxLength+1))
+	if err != nil {
+		p.writeAPIError(w, &APIErrorResponse{Message: "Could not read request body.", StatusCode: http.StatusBadRequest})
+		return
+	}
+	if len(body) > subscriptionsConfigMaxLength {
+		p.writeAPIError(w, &APIErrorResponse{Message: "Subscription configuration is too large.", StatusCode: http.StatusRequestEntityTooLarge})
+		return
+	}
+
+	config, err := parseSubscriptionsConfig(body)
+	if err != nil {
+		p.writeAPIError(w, &APIErrorResponse{Message: err.Error(), StatusCode: http.StatusBadRequest})
+		return
+	}
+
+	changes, err := p.ImportSubscriptionsConfig(config, c.UserID, dryRun, prune)
+	if err != nil {
+		c.Log.WithError(err).Debugf("Failed to import subscriptions")
+		p.writeAPIError(w, &APIErrorResponse{Message: err.Error(), StatusCode: http.StatusBadRequest})
+		return
+	}
+
+	resp := struct {
+		DryRun  bool                  `json:"dry_run"`
+		Changes []*SubscriptionChange `json:"changes"`
+	}{dryRun, changes}
+
+	p.writeJSON(w, resp)
+}
+
+func (p *Plugin) importUserMappings(c *Context, w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, userMappingsImportMaxLength+1))
+	if err != nil {
+		p.writeAPIError(w, &APIErrorResponse{Message: "Could not read request body.", StatusCode: http.StatusBadRequest})
+		return
+	}
+	if len(body) > userMappingsImportMaxLength {
+		p.writeAPIError(w, &APIErrorResponse{Message: "User mapping is too large.", StatusCode: http.StatusRequestEntityTooLarge})
+		return
+	}
+
+	rows, err := parseUserMappingRows(body)
+	if err != nil {
+		p.writeAPIError(w, &APIErrorResponse{Message: err.Error(), StatusCode: http.StatusBadRequest})
+		return
+	}
+
+	result, err := p.ImportUserMappings(rows)
+	if err != nil {
+		c.Log.WithError(err).Warnf("Failed to import user mappings")
+		p.writeAPIError(w, &APIErrorResponse{Message: "Failed to import user mappings.", StatusCode: http.StatusInternalServerError})
+		return
+	}
+
+	p.writeJSON(w, result)
+}
+
+func (p *Plugin) submitImportSubscriptionsDialog(c *Context, w http.ResponseWriter, r *http.Request) {
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		c.Log.WithError(err).Warnf("Error decoding SubmitDialogRequest")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		return
+	}
+
+	rawConfig, _ := request.Submission["config"].(string)
+	dryRun, _ := request.Submission["dry_run"].(bool)
+	prune, _ := request.Submission["prune"].(bool)
+
+	config, err := parseSubscriptionsConfig([]byte(rawConfig))
+	if err != nil {
+		p.writeJSON(w, &model.SubmitDialogResponse{Errors: map[string]string{"config": err.Error()}})
+		return
+	}
+
+	changes, err := p.ImportSubscriptionsConfig(config, c.UserID, dryRun, prune)
+	if err != nil {
+		p.writeJSON(w, &model.SubmitDialogResponse{Errors: map[string]string{"config": err.Error()}})
+		return
+	}
+
+	message := "##### Imported subscription configuration\n"
+	if dryRun {
+		message = "##### Dry run of subscription import\nThe following changes would be made:\n"
+	}
+	message += formatSubscriptionChanges(changes)
+
+	p.client.Post.SendEphemeralPost(c.UserID, &model.Post{
+		UserId:    p.BotUserID,
+		ChannelId: request.ChannelId,
+		Message:   message,
+	})
+}
+
 func (p *Plugin) getConfig(w http.ResponseWriter, r *http.Request) {
 	config := p.getConfiguration()
 
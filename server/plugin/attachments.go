@@ -0,0 +1,300 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const renderStyleAttachment = "attachment"
+
+const (
+	attachmentColorNeutral = "#6e7781"
+	// maxAttachmentTextLength is the maximum length of a pull request, issue or comment body
+	// shown in an attachment.
+	maxAttachmentTextLength = 1000
+)
+
+var (
+	labelsFieldMessage    = &i18n.Message{ID: "attachment.field.labels", Other: "Labels"}
+	assigneesFieldMessage = &i18n.Message{ID: "attachment.field.assignees", Other: "Assignees"}
+)
+
+// getAttachmentPost returns a copy of post with its message replaced by an attachment
+// describing the event. The first line of the original message is kept as the fallback of the
+// attachment. The texts of the attachment are localized with l.
+func (p *Plugin) getAttachmentPost(l *i18n.Localizer, post *model.Post, event interface{}) *model.Post {
+	attachment := p.getEventAttachment(l, event, post.Message)
+	if attachment == nil {
+		return post
+	}
+
+	attachmentPost := post.Clone()
+	attachmentPost.Message = ""
+	attachmentPost.DelProp("attachments")
+	model.ParseSlackAttachment(attachmentPost, []*model.SlackAttachment{attachment})
+
+	return attachmentPost
+}
+
+// getEventAttachment builds the attachment for a subscription event. Events without a dedicated
+// layout are shown as an attachment containing message.
+func (p *Plugin) getEventAttachment(l *i18n.Localizer, event interface{}, message string) *model.SlackAttachment {
+	var attachment *model.SlackAttachment
+	switch event := event.(type) {
+	case *github.PullRequestEvent:
+		attachment = p.getPullRequestEventAttachment(l, event)
+	case *github.IssuesEvent:
+		attachment = p.getIssuesEventAttachment(l, event)
+	case *github.IssueCommentEvent:
+		attachment = p.getIssueCommentEventAttachment(l, event)
+	case *github.PullRequestReviewEvent:
+		attachment = p.getPullRequestReviewEventAttachment(l, event)
+	case *github.PullRequestReviewCommentEvent:
+		attachment = p.getPullRequestReviewCommentEventAttachment(l, event)
+	default:
+		message = strings.TrimSpace(message)
+		if message == "" {
+			return nil
+		}
+
+		attachment = &model.SlackAttachment{
+			Color: attachmentColorNeutral,
+			Text:  message,
+		}
+	}
+
+	if sender, ok := event.(interface{ GetSender() *github.User }); ok && sender.GetSender() != nil {
+		attachment.AuthorName = sender.GetSender().GetLogin()
+		attachment.AuthorIcon = sender.GetSender().GetAvatarURL()
+		attachment.AuthorLink = sender.GetSender().GetHTMLURL()
+	}
+
+	attachment.Fallback = summarizePost(&model.Post{Message: message})
+
+	return attachment
+}
+
+func (p *Plugin) getPullRequestEventAttachment(l *i18n.Localizer, event *github.PullRequestEvent) *model.SlackAttachment {
+	pr := event.GetPullRequest()
+	data := map[string]interface{}{
+		"Repository": getRepositoryLink(event.GetRepo()),
+		"Action":     event.GetAction(),
+		"Label":      event.GetLabel().GetName(),
+	}
+
+	var pretext *i18n.Message
+	color := linkPreviewColorOpen
+	showBody := false
+	switch event.GetAction() {
+	case actionOpened:
+		pretext = &i18n.Message{ID: "attachment.pullRequest.opened", Other: "New pull request in {{.Repository}}"}
+		if pr.GetDraft() {
+			pretext = &i18n.Message{ID: "attachment.pullRequest.openedDraft", Other: "New draft pull request in {{.Repository}}"}
+			color = linkPreviewColorDraft
+		}
+		showBody = true
+	case actionMarkedReadyForReview:
+		pretext = &i18n.Message{ID: "attachment.pullRequest.readyForReview", Other: "Pull request marked ready for review in {{.Repository}}"}
+		showBody = true
+	case actionClosed:
+		pretext = &i18n.Message{ID: "attachment.pullRequest.closed", Other: "Pull request closed in {{.Repository}}"}
+		color = linkPreviewColorClosed
+		if pr.GetMerged() {
+			pretext = &i18n.Message{ID: "attachment.pullRequest.merged", Other: "Pull request merged in {{.Repository}}"}
+			color = linkPreviewColorMerged
+		}
+	case actionLabeled:
+		pretext = &i18n.Message{ID: "attachment.pullRequest.labeled", Other: "Pull request labeled `{{.Label}}` in {{.Repository}}"}
+		if pr.GetState() == "closed" {
+			color = linkPreviewColorClosed
+		}
+	default:
+		pretext = &i18n.Message{ID: "attachment.pullRequest.other", Other: "Pull request {{.Action}} in {{.Repository}}"}
+	}
+
+	attachment := &model.SlackAttachment{
+		Pretext:   p.localizeWithData(l, pretext, data),
+		Color:     color,
+		Title:     fmt.Sprintf("#%d %s", pr.GetNumber(), pr.GetTitle()),
+		TitleLink: pr.GetHTMLURL(),
+	}
+	if showBody {
+		attachment.Text = getAttachmentText(sanitizeDescription(pr.GetBody()))
+	}
+
+	addLabelsField(attachment, p.localize(l, labelsFieldMessage), pr.Labels)
+	addUsersField(attachment, p.localize(l, assigneesFieldMessage), pr.Assignees)
+
+	reviewers := make([]string, 0, len(pr.RequestedReviewers)+len(pr.RequestedTeams))
+	for _, reviewer := range pr.RequestedReviewers {
+		reviewers = append(reviewers, getUserMention(reviewer))
+	}
+	for _, team := range pr.RequestedTeams {
+		reviewers = append(reviewers, team.GetName())
+	}
+	if len(reviewers) > 0 {
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{
+			Title: p.localize(l, &i18n.Message{ID: "attachment.field.reviewers", Other: "Reviewers"}),
+			Value: strings.Join(reviewers, ", "),
+			Short: true,
+		})
+	}
+
+	return attachment
+}
+
+func (p *Plugin) getIssuesEventAttachment(l *i18n.Localizer, event *github.IssuesEvent) *model.SlackAttachment {
+	issue := event.GetIssue()
+	data := map[string]interface{}{
+		"Repository": getRepositoryLink(event.GetRepo()),
+		"Action":     event.GetAction(),
+		"Label":      event.GetLabel().GetName(),
+	}
+
+	var pretext *i18n.Message
+	color := linkPreviewColorOpen
+	showBody := false
+	switch event.GetAction() {
+	case actionOpened:
+		pretext = &i18n.Message{ID: "attachment.issue.opened", Other: "New issue in {{.Repository}}"}
+		showBody = true
+	case actionReopened:
+		pretext = &i18n.Message{ID: "attachment.issue.reopened", Other: "Issue reopened in {{.Repository}}"}
+	case actionClosed:
+		pretext = &i18n.Message{ID: "attachment.issue.closed", Other: "Issue closed in {{.Repository}}"}
+		color = linkPreviewColorClosed
+	case actionLabeled:
+		pretext = &i18n.Message{ID: "attachment.issue.labeled", Other: "Issue labeled `{{.Label}}` in {{.Repository}}"}
+		if issue.GetState() == "closed" {
+			color = linkPreviewColorClosed
+		}
+	default:
+		pretext = &i18n.Message{ID: "attachment.issue.other", Other: "Issue {{.Action}} in {{.Repository}}"}
+	}
+
+	attachment := &model.SlackAttachment{
+		Pretext:   p.localizeWithData(l, pretext, data),
+		Color:     color,
+		Title:     fmt.Sprintf("#%d %s", issue.GetNumber(), issue.GetTitle()),
+		TitleLink: issue.GetHTMLURL(),
+	}
+	if showBody {
+		attachment.Text = getAttachmentText(sanitizeDescription(issue.GetBody()))
+	}
+
+	addLabelsField(attachment, p.localize(l, labelsFieldMessage), issue.Labels)
+	addUsersField(attachment, p.localize(l, assigneesFieldMessage), issue.Assignees)
+
+	return attachment
+}
+
+func (p *Plugin) getIssueCommentEventAttachment(l *i18n.Localizer, event *github.IssueCommentEvent) *model.SlackAttachment {
+	issue := event.GetIssue()
+
+	pretext := &i18n.Message{ID: "attachment.issueComment.issue", Other: "New comment on issue in {{.Repository}}"}
+	color := linkPreviewColorOpen
+	if issue.IsPullRequest() {
+		pretext = &i18n.Message{ID: "attachment.issueComment.pullRequest", Other: "New comment on pull request in {{.Repository}}"}
+	}
+	if issue.GetState() == "closed" {
+		color = linkPreviewColorClosed
+	}
+
+	return &model.SlackAttachment{
+		Pretext:   p.localizeWithData(l, pretext, map[string]interface{}{"Repository": getRepositoryLink(event.GetRepo())}),
+		Color:     color,
+		Title:     fmt.Sprintf("#%d %s", issue.GetNumber(), issue.GetTitle()),
+		TitleLink: event.GetComment().GetHTMLURL(),
+		Text:      getAttachmentText(event.GetComment().GetBody()),
+	}
+}
+
+func (p *Plugin) getPullRequestReviewEventAttachment(l *i18n.Localizer, event *github.PullRequestReviewEvent) *model.SlackAttachment {
+	pr := event.GetPullRequest()
+	review := event.GetReview()
+
+	pretext := &i18n.Message{ID: "attachment.review.other", Other: "Pull request reviewed in {{.Repository}}"}
+	color := attachmentColorNeutral
+	switch strings.ToLower(review.GetState()) {
+	case "approved":
+		pretext = &i18n.Message{ID: "attachment.review.approved", Other: "Pull request approved in {{.Repository}}"}
+		color = linkPreviewColorOpen
+	case "changes_requested":
+		pretext = &i18n.Message{ID: "attachment.review.changesRequested", Other: "Changes requested on pull request in {{.Repository}}"}
+		color = linkPreviewColorClosed
+	case "commented":
+		pretext = &i18n.Message{ID: "attachment.review.commented", Other: "Review comments on pull request in {{.Repository}}"}
+	}
+
+	return &model.SlackAttachment{
+		Pretext:   p.localizeWithData(l, pretext, map[string]interface{}{"Repository": getRepositoryLink(event.GetRepo())}),
+		Color:     color,
+		Title:     fmt.Sprintf("#%d %s", pr.GetNumber(), pr.GetTitle()),
+		TitleLink: review.GetHTMLURL(),
+		Text:      getAttachmentText(review.GetBody()),
+	}
+}
+
+func (p *Plugin) getPullRequestReviewCommentEventAttachment(l *i18n.Localizer, event *github.PullRequestReviewCommentEvent) *model.SlackAttachment {
+	pr := event.GetPullRequest()
+	comment := event.GetComment()
+
+	text := getAttachmentText(comment.GetBody())
+	if comment.GetPath() != "" {
+		text = fmt.Sprintf("`%s`\n%s", comment.GetPath(), text)
+	}
+
+	return &model.SlackAttachment{
+		Pretext: p.localizeWithData(l, &i18n.Message{
+			ID:    "attachment.reviewComment",
+			Other: "New review comment on pull request in {{.Repository}}",
+		}, map[string]interface{}{"Repository": getRepositoryLink(event.GetRepo())}),
+		Color:     attachmentColorNeutral,
+		Title:     fmt.Sprintf("#%d %s", pr.GetNumber(), pr.GetTitle()),
+		TitleLink: comment.GetHTMLURL(),
+		Text:      text,
+	}
+}
+
+func getRepositoryLink(repo *github.Repository) string {
+	return fmt.Sprintf("[%s](%s)", repo.GetFullName(), repo.GetHTMLURL())
+}
+
+// getUserMention returns an at-mention of the Mattermost user linked to user, or a link to the
+// GitHub profile otherwise.
+func getUserMention(user *github.User) string {
+	if username := lookupMattermostUsername(user.GetLogin()); username != "" {
+		return "@" + username
+	}
+
+	return fmt.Sprintf("[%s](%s)", user.GetLogin(), user.GetHTMLURL())
+}
+
+func addUsersField(attachment *model.SlackAttachment, title string, users []*github.User) {
+	if len(users) == 0 {
+		return
+	}
+
+	mentions := make([]string, len(users))
+	for i, user := range users {
+		mentions[i] = getUserMention(user)
+	}
+
+	attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: title, Value: strings.Join(mentions, ", "), Short: true})
+}
+
+// getAttachmentText prepares a pull request, issue or comment body to be shown in an attachment.
+func getAttachmentText(body string) string {
+	text := strings.TrimSpace(replaceAllGitHubUsernames(removeComments(body)))
+
+	runes := []rune(text)
+	if len(runes) > maxAttachmentTextLength {
+		text = string(runes[:maxAttachmentTextLength]) + "…"
+	}
+
+	return text
+}
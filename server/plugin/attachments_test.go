@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEventAttachment(t *testing.T) {
+	p := NewPlugin()
+	l := p.getLocalizer(defaultLocale)
+	sender := &github.User{
+		Login:     sToP("panda"),
+		AvatarURL: sToP("https://avatars.githubusercontent.com/u/1"),
+		HTMLURL:   sToP("https://github.com/panda"),
+	}
+	newPullRequestEvent := func(action string, pr *github.PullRequest) *github.PullRequestEvent {
+		return &github.PullRequestEvent{
+			Action:      sToP(action),
+			Repo:        &repo,
+			Sender:      sender,
+			PullRequest: pr,
+		}
+	}
+
+	t.Run("opened pull request", func(t *testing.T) {
+		pr := pullRequestWithLabelAndAssignee
+		pr.RequestedReviewers = []*github.User{{Login: sToP("marianunez"), HTMLURL: sToP("https://github.com/marianunez")}}
+		pr.RequestedTeams = []*github.Team{{Name: sToP("Core")}}
+
+		attachment := p.getEventAttachment(l, newPullRequestEvent(actionOpened, &pr), "\n#### Leverage git-get-head\n")
+
+		assert.Equal(t, "Leverage git-get-head", attachment.Fallback)
+		assert.Equal(t, linkPreviewColorOpen, attachment.Color)
+		assert.Equal(t, "New pull request in [mattermost-plugin-github](https://github.com/mattermost/mattermost-plugin-github)", attachment.Pretext)
+		assert.Equal(t, "#42 Leverage git-get-head", attachment.Title)
+		assert.Equal(t, "https://github.com/mattermost/mattermost-plugin-github/pull/42", attachment.TitleLink)
+		assert.Equal(t, "panda", attachment.AuthorName)
+		assert.Equal(t, "https://avatars.githubusercontent.com/u/1", attachment.AuthorIcon)
+		assert.Equal(t, "https://github.com/panda", attachment.AuthorLink)
+		assert.Equal(t, "git-get-head gets the non-sent upstream heads inside the stashed non-cleaned applied areas, and after pruning bases to many archives, you can initialize the origin of the bases.", attachment.Text)
+		assert.Equal(t, []*model.SlackAttachmentField{
+			{Title: "Labels", Value: "`Help Wanted`"},
+			{Title: "Assignees", Value: "[panda](https://github.com/panda)", Short: true},
+			{Title: "Reviewers", Value: "[marianunez](https://github.com/marianunez), Core", Short: true},
+		}, attachment.Fields)
+	})
+
+	t.Run("merged pull request", func(t *testing.T) {
+		pr := pullRequest
+		pr.Merged = bToP(true)
+
+		attachment := p.getEventAttachment(l, newPullRequestEvent(actionClosed, &pr), "merged")
+
+		assert.Equal(t, linkPreviewColorMerged, attachment.Color)
+		assert.Equal(t, "Pull request merged in [mattermost-plugin-github](https://github.com/mattermost/mattermost-plugin-github)", attachment.Pretext)
+		assert.Empty(t, attachment.Text)
+	})
+
+	t.Run("closed pull request", func(t *testing.T) {
+		pr := pullRequest
+
+		attachment := p.getEventAttachment(l, newPullRequestEvent(actionClosed, &pr), "closed")
+
+		assert.Equal(t, linkPreviewColorClosed, attachment.Color)
+		assert.Equal(t, "Pull request closed in [mattermost-plugin-github](https://github.com/mattermost/mattermost-plugin-github)", attachment.Pretext)
+	})
+
+	t.Run("closed issue", func(t *testing.T) {
+		attachment := p.getEventAttachment(l, &github.IssuesEvent{
+			Action: sToP(actionClosed),
+			Repo:   &repo,
+			Sender: sender,
+			Issue:  &issue,
+		}, "closed")
+
+		assert.Equal(t, linkPreviewColorClosed, attachment.Color)
+		assert.Equal(t, "Issue closed in [mattermost-plugin-github](https://github.com/mattermost/mattermost-plugin-github)", attachment.Pretext)
+		assert.Equal(t, "#1 Implement git-get-head", attachment.Title)
+	})
+
+	t.Run("other events", func(t *testing.T) {
+		attachment := p.getEventAttachment(l, &github.CreateEvent{Sender: sender}, "\nbranch created\n")
+
+		assert.Equal(t, attachmentColorNeutral, attachment.Color)
+		assert.Equal(t, "branch created", attachment.Text)
+		assert.Equal(t, "branch created", attachment.Fallback)
+		assert.Equal(t, "panda", attachment.AuthorName)
+
+		assert.Nil(t, p.getEventAttachment(l, &github.CreateEvent{}, " "))
+	})
+
+	t.Run("localized pull request", func(t *testing.T) {
+		p := NewPlugin()
+		p.bundle.MustParseMessageFileBytes([]byte(`{
+			"attachment.pullRequest.opened": "Neuer Pull Request in {{.Repository}}",
+			"attachment.field.reviewers": "Reviewer"
+		}`), "active.de.json")
+
+		pr := pullRequest
+		pr.RequestedTeams = []*github.Team{{Name: sToP("Core")}}
+
+		attachment := p.getEventAttachment(p.getLocalizer("de"), newPullRequestEvent(actionOpened, &pr), "opened")
+
+		assert.Equal(t, "Neuer Pull Request in [mattermost-plugin-github](https://github.com/mattermost/mattermost-plugin-github)", attachment.Pretext)
+		assert.Equal(t, []*model.SlackAttachmentField{{Title: "Reviewer", Value: "Core", Short: true}}, attachment.Fields)
+	})
+}
+
+func TestGetAttachmentPost(t *testing.T) {
+	p := NewPlugin()
+	post := &model.Post{
+		UserId:    "bot",
+		ChannelId: "channel",
+		Type:      "custom_git_create",
+		Message:   "branch created",
+	}
+
+	attachmentPost := p.getAttachmentPost(p.getLocalizer(defaultLocale), post, &github.CreateEvent{})
+
+	assert.Equal(t, "branch created", post.Message, "the original post is not modified")
+	assert.Empty(t, attachmentPost.Message)
+	assert.Equal(t, "custom_git_create", attachmentPost.Type)
+	assert.Equal(t, "channel", attachmentPost.ChannelId)
+
+	attachments := attachmentPost.Attachments()
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "branch created", attachments[0].Text)
+}
@@ -0,0 +1,312 @@
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/pkg/errors"
+)
+
+const (
+	ciStateSuccess = "success"
+	ciStateFailure = "failure"
+	ciStatePending = "pending"
+
+	ciPullRequestKeyPrefix = "_ci_"
+	// ciPullRequestTTL is how long the head commit of an open pull request is tracked without
+	// being updated.
+	ciPullRequestTTL = 14 * 24 * time.Hour
+
+	ciPullRequestUpdateRetries = 5
+	ciStatusTimeout            = 30 * time.Second
+)
+
+// ciPullRequest is the open pull request with a given head commit, as tracked from pull request
+// events. Status, check run and workflow run events only reference the commit.
+type ciPullRequest struct {
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+	Author     string
+	Labels     []string
+	// NotifiedState is the last CI state the author was notified about.
+	NotifiedState string
+	// ReadyToMergeNotified is set once the author was told the pull request is ready to merge.
+	ReadyToMergeNotified bool
+}
+
+// CICheck is a failed commit status, check run or workflow run.
+type CICheck struct {
+	Name string
+	URL  string
+}
+
+// CIStatusNotification is the data of the notification sent to a pull request author about the
+// CI result of the head commit.
+type CIStatusNotification struct {
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+	SHA        string
+	// State is either ciStateSuccess or ciStateFailure.
+	State string
+	// FailedCheck is the check that triggered a failure notification, if it failed itself.
+	FailedCheck *CICheck
+}
+
+func ciPullRequestKey(repository, sha string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(repository) + "@" + sha))
+	return ciPullRequestKeyPrefix + hex.EncodeToString(hash[:16])
+}
+
+// trackPullRequestHead keeps track of the head commit of open pull requests, so that the results
+// of CI runs can be mapped back to the pull request and its author.
+func (p *Plugin) trackPullRequestHead(event *github.PullRequestEvent) {
+	repository := event.GetRepo().GetFullName()
+	pr := event.GetPullRequest()
+	sha := pr.GetHead().GetSHA()
+	if sha == "" {
+		return
+	}
+
+	if event.GetAction() == actionSynchronize && event.GetBefore() != "" {
+		if err := p.client.KV.Delete(ciPullRequestKey(repository, event.GetBefore())); err != nil {
+			p.client.Log.Warn("Failed to delete tracked pull request commit", "repository", repository, "error", err.Error())
+		}
+	}
+
+	if event.GetAction() == actionClosed {
+		if err := p.client.KV.Delete(ciPullRequestKey(repository, sha)); err != nil {
+			p.client.Log.Warn("Failed to delete tracked pull request commit", "repository", repository, "error", err.Error())
+		}
+		return
+	}
+
+	p.trackPullRequest(repository, pr)
+}
+
+// trackPullRequest stores the pull request under its head commit.
+func (p *Plugin) trackPullRequest(repository string, pr *github.PullRequest) {
+	var labels []string
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	_, err := p.updateCIPullRequest(ciPullRequestKey(repository, pr.GetHead().GetSHA()), true, func(tracked *ciPullRequest) bool {
+		tracked.Repository = repository
+		tracked.Number = pr.GetNumber()
+		tracked.Title = pr.GetTitle()
+		tracked.URL = pr.GetHTMLURL()
+		tracked.Author = pr.GetUser().GetLogin()
+		tracked.Labels = labels
+		return true
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to track pull request commit", "repository", repository, "error", err.Error())
+	}
+}
+
+// updateCIPullRequest atomically applies update to the pull request tracked at key. Nothing is
+// stored unless the pull request is tracked already or create is set, and update returns true.
+// It reports whether the pull request was stored.
+func (p *Plugin) updateCIPullRequest(key string, create bool, update func(tracked *ciPullRequest) bool) (bool, error) {
+	for i := 0; i < ciPullRequestUpdateRetries; i++ {
+		var oldValue []byte
+		if err := p.client.KV.Get(key, &oldValue); err != nil {
+			return false, errors.Wrap(err, "failed to get tracked pull request")
+		}
+
+		if oldValue == nil && !create {
+			return false, nil
+		}
+
+		tracked := &ciPullRequest{}
+		if oldValue != nil {
+			if err := json.Unmarshal(oldValue, tracked); err != nil {
+				return false, errors.Wrap(err, "failed to decode tracked pull request")
+			}
+		}
+
+		if !update(tracked) {
+			return false, nil
+		}
+
+		saved, err := p.client.KV.Set(key, tracked, pluginapi.SetAtomic(oldValue), pluginapi.SetExpiry(ciPullRequestTTL))
+		if err != nil {
+			return false, errors.Wrap(err, "failed to store tracked pull request")
+		}
+		if saved {
+			return true, nil
+		}
+	}
+
+	return false, errors.Errorf("failed to store tracked pull request after %d retries", ciPullRequestUpdateRetries)
+}
+
+func (p *Plugin) handleStatusEvent(event *github.StatusEvent) {
+	var failedCheck *CICheck
+	switch event.GetState() {
+	case ciStatePending:
+		return
+	case ciStateFailure, "error":
+		failedCheck = &CICheck{Name: event.GetContext(), URL: event.GetTargetURL()}
+	}
+
+	p.handleCIResult(event.GetRepo(), event.GetSHA(), failedCheck)
+}
+
+func (p *Plugin) handleCheckRunEvent(event *github.CheckRunEvent) {
+	run := event.GetCheckRun()
+	if event.GetAction() != actionCompleted {
+		return
+	}
+
+	var failedCheck *CICheck
+	if !isPassingConclusion(run.GetConclusion()) {
+		failedCheck = &CICheck{Name: run.GetName(), URL: run.GetHTMLURL()}
+	}
+
+	p.handleCIResult(event.GetRepo(), run.GetHeadSHA(), failedCheck)
+}
+
+func (p *Plugin) handleWorkflowRunEvent(event *github.WorkflowRunEvent) {
+	run := event.GetWorkflowRun()
+	if event.GetAction() != actionCompleted {
+		return
+	}
+
+	var failedCheck *CICheck
+	if !isPassingConclusion(run.GetConclusion()) {
+		failedCheck = &CICheck{Name: run.GetName(), URL: run.GetHTMLURL()}
+	}
+
+	p.handleCIResult(event.GetRepo(), run.GetHeadSHA(), failedCheck)
+}
+
+// handleCIResult notifies the author of the pull request with the head commit sha once all
+// checks of the commit have passed, or as soon as one of them failed. failedCheck is the check
+// that just completed, if it failed. Each result is only notified once per commit. Once all
+// checks passed, the author is also told if the pull request is ready to merge.
+func (p *Plugin) handleCIResult(repo *github.Repository, sha string, failedCheck *CICheck) {
+	if sha == "" {
+		return
+	}
+
+	var tracked *ciPullRequest
+	if err := p.client.KV.Get(ciPullRequestKey(repo.GetFullName(), sha), &tracked); err != nil {
+		p.client.Log.Warn("Failed to get tracked pull request", "repository", repo.GetFullName(), "error", err.Error())
+		return
+	}
+	if tracked == nil {
+		return
+	}
+
+	var labels []*github.Label
+	for _, label := range tracked.Labels {
+		labels = append(labels, &github.Label{Name: github.String(label)})
+	}
+
+	authorUserID, info := p.getPullRequestAuthorToNotify(tracked.Author, repo, labels)
+	if authorUserID == "" {
+		return
+	}
+
+	notifyCIResult := info.Settings.IsNotificationEnabled(notificationTypeCIFailures)
+	notifyReadyToMerge := info.Settings.IsNotificationEnabled(notificationTypeReadyToMerge)
+	if !notifyCIResult && !notifyReadyToMerge {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ciStatusTimeout)
+	defer cancel()
+
+	state := p.getCIState(ctx, repo.GetOwner().GetLogin(), repo.GetName(), sha, p.githubConnectUser(ctx, info))
+	if state != ciStateSuccess && state != ciStateFailure {
+		return
+	}
+
+	if notifyCIResult {
+		p.notifyCIResult(authorUserID, tracked, sha, state, failedCheck)
+	}
+
+	if notifyReadyToMerge && state == ciStateSuccess {
+		p.notifyReadyToMerge(ctx, authorUserID, info, repo, tracked.Number, sha)
+	}
+}
+
+// getPullRequestAuthorToNotify returns the Mattermost user ID and GitHub account of the author
+// of a pull request, or an empty user ID if the author isn't connected, can't access the
+// repository or muted notifications about the repository or labels.
+func (p *Plugin) getPullRequestAuthorToNotify(author string, repo *github.Repository, labels []*github.Label) (string, *GitHubUserInfo) {
+	authorUserID := p.getGitHubToUserIDMapping(author)
+	if authorUserID == "" {
+		return "", nil
+	}
+
+	if repo.GetPrivate() && !p.permissionToRepo(authorUserID, repo.GetFullName()) {
+		return "", nil
+	}
+
+	if p.notificationMutedByReceiver(authorUserID, repo.GetFullName(), labels) {
+		return "", nil
+	}
+
+	info, apiErr := p.getGitHubUserInfo(authorUserID)
+	if apiErr != nil {
+		p.client.Log.Debug("Failed to get GitHub account of pull request author", "userID", authorUserID, "error", apiErr.Message)
+		return "", nil
+	}
+
+	if info.Settings == nil {
+		info.Settings = &UserSettings{}
+	}
+
+	return authorUserID, info
+}
+
+// notifyCIResult sends the CI state of the tracked pull request to its author, unless the author
+// was already notified about it.
+func (p *Plugin) notifyCIResult(authorUserID string, tracked *ciPullRequest, sha, state string, failedCheck *CICheck) {
+	notify, err := p.updateCIPullRequest(ciPullRequestKey(tracked.Repository, sha), false, func(tracked *ciPullRequest) bool {
+		if tracked.NotifiedState == state {
+			return false
+		}
+		tracked.NotifiedState = state
+		return true
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to update tracked pull request", "repository", tracked.Repository, "error", err.Error())
+		return
+	}
+	if !notify {
+		return
+	}
+
+	notification := &CIStatusNotification{
+		Repository: tracked.Repository,
+		Number:     tracked.Number,
+		Title:      tracked.Title,
+		URL:        tracked.URL,
+		SHA:        sha,
+		State:      state,
+	}
+	if state == ciStateFailure {
+		notification.FailedCheck = failedCheck
+	}
+
+	message, err := p.renderUserTemplate(authorUserID, "ciStatusNotification", notification)
+	if err != nil {
+		p.client.Log.Warn("Failed to render template", "error", err.Error())
+		return
+	}
+
+	p.CreateBotDMPost(authorUserID, message, "custom_git_ci")
+}
@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIPullRequestKey(t *testing.T) {
+	key := ciPullRequestKey("mattermost/mattermost-server", "a10867b14bb761a232cd80139fbd4c0d33264240")
+
+	assert.Equal(t, key, ciPullRequestKey("Mattermost/mattermost-server", "a10867b14bb761a232cd80139fbd4c0d33264240"))
+	assert.NotEqual(t, key, ciPullRequestKey("mattermost/mattermost-server", "b10867b14bb761a232cd80139fbd4c0d33264240"))
+	assert.LessOrEqual(t, len(key), model.KeyValueKeyMaxRunes)
+}
+
+func TestTrackPullRequestHead(t *testing.T) {
+	setupPlugin := func(api *plugintest.API) *Plugin {
+		p := NewPlugin()
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p
+	}
+
+	pullRequestEvent := func(action string) *github.PullRequestEvent {
+		return &github.PullRequestEvent{
+			Action: github.String(action),
+			Before: github.String("before"),
+			Repo:   &github.Repository{FullName: github.String("mattermost/mattermost-server")},
+			PullRequest: &github.PullRequest{
+				Number:  github.Int(42),
+				Title:   github.String("Leverage git-get-head"),
+				HTMLURL: github.String("https://github.com/mattermost/mattermost-server/pull/42"),
+				User:    &github.User{Login: github.String("octocat")},
+				Labels:  []*github.Label{{Name: github.String("bug")}},
+				Head:    &github.PullRequestBranch{SHA: github.String("head")},
+			},
+		}
+	}
+	key := ciPullRequestKey("mattermost/mattermost-server", "head")
+	setOptions := model.PluginKVSetOptions{Atomic: true, ExpireInSeconds: int64(ciPullRequestTTL.Seconds())}
+
+	t.Run("opened pull request is tracked", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil)
+		api.On("KVSetWithOptions", key, mock.Anything, setOptions).Return(true, nil)
+		p := setupPlugin(api)
+
+		p.trackPullRequestHead(pullRequestEvent(actionOpened))
+
+		api.AssertCalled(t, "KVSetWithOptions", key, mock.MatchedBy(func(data []byte) bool {
+			var tracked ciPullRequest
+			return json.Unmarshal(data, &tracked) == nil && assert.ObjectsAreEqual(ciPullRequest{
+				Repository: "mattermost/mattermost-server",
+				Number:     42,
+				Title:      "Leverage git-get-head",
+				URL:        "https://github.com/mattermost/mattermost-server/pull/42",
+				Author:     "octocat",
+				Labels:     []string{"bug"},
+			}, tracked)
+		}), setOptions)
+	})
+
+	t.Run("new commits replace the previous head", func(t *testing.T) {
+		previous, err := json.Marshal(&ciPullRequest{Number: 42, NotifiedState: ciStateFailure})
+		require.NoError(t, err)
+
+		beforeKey := ciPullRequestKey("mattermost/mattermost-server", "before")
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", beforeKey, []byte(nil), model.PluginKVSetOptions{}).Return(true, nil)
+		api.On("KVGet", key).Return(previous, nil)
+		api.On("KVSetWithOptions", key, mock.Anything, mock.Anything).Return(true, nil)
+		p := setupPlugin(api)
+
+		p.trackPullRequestHead(pullRequestEvent(actionSynchronize))
+
+		api.AssertCalled(t, "KVSetWithOptions", beforeKey, []byte(nil), model.PluginKVSetOptions{})
+		api.AssertCalled(t, "KVSetWithOptions", key, mock.MatchedBy(func(data []byte) bool {
+			var tracked ciPullRequest
+			return json.Unmarshal(data, &tracked) == nil && tracked.NotifiedState == ciStateFailure && tracked.Author == "octocat"
+		}), model.PluginKVSetOptions{Atomic: true, OldValue: previous, ExpireInSeconds: int64(ciPullRequestTTL.Seconds())})
+	})
+
+	t.Run("closed pull request is no longer tracked", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", key, []byte(nil), model.PluginKVSetOptions{}).Return(true, nil)
+		p := setupPlugin(api)
+
+		p.trackPullRequestHead(pullRequestEvent(actionClosed))
+
+		api.AssertExpectations(t)
+	})
+}
+
+func TestUpdateCIPullRequest(t *testing.T) {
+	key := ciPullRequestKey("mattermost/mattermost-server", "head")
+	notifyFailure := func(tracked *ciPullRequest) bool {
+		if tracked.NotifiedState == ciStateFailure {
+			return false
+		}
+		tracked.NotifiedState = ciStateFailure
+		return true
+	}
+
+	t.Run("untracked commit", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil)
+		p := NewPlugin()
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+
+		updated, err := p.updateCIPullRequest(key, false, notifyFailure)
+		require.NoError(t, err)
+		assert.False(t, updated)
+		api.AssertNotCalled(t, "KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("result is notified once", func(t *testing.T) {
+		notified, err := json.Marshal(&ciPullRequest{Number: 42, NotifiedState: ciStateFailure})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(notified, nil)
+		p := NewPlugin()
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+
+		updated, err := p.updateCIPullRequest(key, false, notifyFailure)
+		require.NoError(t, err)
+		assert.False(t, updated)
+		api.AssertNotCalled(t, "KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent update is retried", func(t *testing.T) {
+		passed, err := json.Marshal(&ciPullRequest{Number: 42, NotifiedState: ciStateSuccess})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(passed, nil)
+		api.On("KVSetWithOptions", key, mock.Anything, mock.Anything).Return(false, nil).Once()
+		api.On("KVSetWithOptions", key, mock.Anything, mock.Anything).Return(true, nil).Once()
+		p := NewPlugin()
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+
+		updated, err := p.updateCIPullRequest(key, false, notifyFailure)
+		require.NoError(t, err)
+		assert.True(t, updated)
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 2)
+	})
+}
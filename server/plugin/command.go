@@ -1,14 +1,19 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"unicode"
 
+	"github.com/google/go-github/v41/github"
 	"github.com/mattermost/mattermost-plugin-api/experimental/command"
 	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/mattermost/mattermost-server/v6/plugin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/pkg/errors"
 )
 
@@ -23,6 +28,12 @@ const (
 	featureIssueComments = "issue_comments"
 	featurePullReviews   = "pull_reviews"
 	featureStars         = "stars"
+	featureDiscussions   = "discussions"
+	featureSecurity      = "security"
+	featureDeployments   = "deployments"
+	featureMilestones    = "milestones"
+	featureProjects      = "projects"
+	featureRepoAdmin     = "repo_admin"
 )
 
 var validFeatures = map[string]bool{
@@ -36,8 +47,25 @@ var validFeatures = map[string]bool{
 	featureIssueComments: true,
 	featurePullReviews:   true,
 	featureStars:         true,
+	featureDiscussions:   true,
+	featureSecurity:      true,
+	featureDeployments:   true,
+	featureMilestones:    true,
+	featureProjects:      true,
+	featureRepoAdmin:     true,
 }
 
+// Messages shared by several commands.
+var (
+	unknownSubcommandMessage        = &i18n.Message{ID: "command.unknownSubcommand", Other: "Unknown subcommand {{.Command}}"}
+	unsupportedFlagMessage          = &i18n.Message{ID: "command.unsupportedFlag", Other: "Unsupported flag {{.Flag}}"}
+	invalidFlagFormatMessage        = &i18n.Message{ID: "command.invalidFlagFormat", Other: "Please use the correct format for flags: --<name> <value>"}
+	permissionsCheckErrorMessage    = &i18n.Message{ID: "command.permissionsCheckError", Other: "Error checking user's permissions"}
+	missingRepositoryMessage        = &i18n.Message{ID: "command.missingRepository", Other: "Please specify a repository."}
+	getSubscriptionsErrorMessage    = &i18n.Message{ID: "command.getSubscriptionsError", Other: "Encountered an error getting subscriptions."}
+	exportSubscriptionsErrorMessage = &i18n.Message{ID: "command.exportSubscriptionsError", Other: "Encountered an error exporting subscriptions."}
+)
+
 // validateFeatures returns false when 1 or more given features
 // are invalid along with a list of the invalid features.
 func validateFeatures(features []string) (bool, []string) {
@@ -67,6 +95,27 @@ func validateFeatures(features []string) (bool, []string) {
 	return valid, invalidFeatures
 }
 
+// validateFeatureList checks a comma-delimited list of subscription features
+// and returns a user facing error if the list is not valid.
+func validateFeatureList(features string) error {
+	fs := strings.Split(features, ",")
+	if SliceContainsString(fs, featureIssues) && SliceContainsString(fs, featureIssueCreation) {
+		return errors.New("Feature list cannot contain both issue and issue_creations")
+	}
+	if SliceContainsString(fs, featurePulls) && SliceContainsString(fs, featurePullsMerged) {
+		return errors.New("Feature list cannot contain both pulls and pulls_merged")
+	}
+	ok, ifs := validateFeatures(fs)
+	if !ok {
+		if len(ifs) == 0 {
+			return errors.New("Feature list must have \"pulls\" or \"issues\" when using a label.")
+		}
+		return errors.Errorf("Invalid feature(s) provided: %s", strings.Join(ifs, ","))
+	}
+
+	return nil
+}
+
 func (p *Plugin) getCommand(config *Configuration) (*model.Command, error) {
 	iconData, err := command.GetIconData(&p.client.System, "assets/icon-bg.svg")
 	if err != nil {
@@ -93,136 +142,226 @@ func (p *Plugin) postCommandResponse(args *model.CommandArgs, text string) {
 	p.client.Post.SendEphemeralPost(args.UserId, post)
 }
 
-func (p *Plugin) getMutedUsernames(userInfo *GitHubUserInfo) []string {
-	var mutedUsernameBytes []byte
-	err := p.client.KV.Get(userInfo.UserID+"-muted-users", &mutedUsernameBytes)
-	if err != nil {
-		return nil
-	}
-	mutedUsernames := string(mutedUsernameBytes)
-	var mutedUsers []string
-	if len(mutedUsernames) == 0 {
-		return mutedUsers
-	}
-	mutedUsers = strings.Split(mutedUsernames, ",")
-	return mutedUsers
-}
+// muteErrorMessage is the response to a failure to load or store the mutes of a user.
+var muteErrorMessage = &i18n.Message{ID: "command.mute.add.error", Other: "Error occurred saving list of muted users"}
+
+// muteRepoInvalidMessage is the response to a repository argument not of the form owner/name.
+var muteRepoInvalidMessage = &i18n.Message{ID: "command.mute.repo.invalid", Other: "Please specify a repository as owner/name."}
 
 func (p *Plugin) handleMuteList(args *model.CommandArgs, userInfo *GitHubUserInfo) string {
-	mutedUsernames := p.getMutedUsernames(userInfo)
-	var mutedUsers string
-	for _, user := range mutedUsernames {
-		mutedUsers += fmt.Sprintf("- %v\n", user)
+	l := p.getUserLocalizer(args.UserId)
+	mutes, err := p.getMutes(userInfo.UserID)
+	if err != nil {
+		p.client.Log.Warn("Failed to get mutes", "error", err.Error())
+		return p.localize(l, muteErrorMessage)
 	}
-	if len(mutedUsers) == 0 {
-		return "You have no muted users"
+
+	if mutes.IsEmpty() {
+		return p.localize(l, &i18n.Message{ID: "command.mute.list.empty", Other: "You have no muted users"})
 	}
-	return "Your muted users:\n" + mutedUsers
-}
 
-func contains(s []string, e string) bool {
-	for _, a := range s {
-		if a == e {
-			return true
+	var txt string
+	sections := []struct {
+		message *i18n.Message
+		values  []string
+	}{
+		{&i18n.Message{ID: "command.mute.list", Other: "Your muted users:"}, mutes.Users},
+		{&i18n.Message{ID: "command.mute.list.repositories", Other: "Your muted repositories:"}, mutes.Repositories},
+		{&i18n.Message{ID: "command.mute.list.labels", Other: "Your muted labels:"}, mutes.Labels},
+	}
+	for _, section := range sections {
+		if len(section.values) == 0 {
+			continue
+		}
+		txt += p.localize(l, section.message) + "\n"
+		for _, value := range section.values {
+			txt += fmt.Sprintf("- %v\n", value)
 		}
 	}
-	return false
+
+	return txt
 }
 
 func (p *Plugin) handleMuteAdd(args *model.CommandArgs, username string, userInfo *GitHubUserInfo) string {
-	mutedUsernames := p.getMutedUsernames(userInfo)
-	if contains(mutedUsernames, username) {
-		return username + " is already muted"
-	}
-
+	l := p.getUserLocalizer(args.UserId)
 	if strings.Contains(username, ",") {
-		return "Invalid username provided"
+		return p.localize(l, &i18n.Message{ID: "command.mute.add.invalidUsername", Other: "Invalid username provided"})
 	}
 
-	var mutedUsers string
-	if len(mutedUsernames) > 0 {
-		// , is a character not allowed in github usernames so we can split on them
-		mutedUsers = strings.Join(mutedUsernames, ",") + "," + username
-	} else {
-		mutedUsers = username
-	}
+	return p.updateMutes(l, userInfo, func(mutes *Mutes) string {
+		if containsFold(mutes.Users, username) {
+			return p.localizeWithData(l, &i18n.Message{ID: "command.mute.add.alreadyMuted", Other: "{{.Username}} is already muted"}, map[string]interface{}{"Username": username})
+		}
 
-	_, err := p.client.KV.Set(userInfo.UserID+"-muted-users", []byte(mutedUsers))
-	if err != nil {
-		return "Error occurred saving list of muted users"
+		mutes.Users = append(mutes.Users, username)
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.mute.add.success",
+			Other: "`{{.Username}}` is now muted. You'll no longer receive notifications for comments in your PRs and issues.",
+		}, map[string]interface{}{"Username": username})
+	})
+}
+
+func (p *Plugin) handleMuteRepository(args *model.CommandArgs, repository string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
+	owner, repo := parseOwnerAndRepo(repository, p.getConfiguration().getBaseURL())
+	if owner == "" || repo == "" {
+		return p.localize(l, muteRepoInvalidMessage)
 	}
+	repository = owner + "/" + repo
+
+	return p.updateMutes(l, userInfo, func(mutes *Mutes) string {
+		data := map[string]interface{}{"Repository": repository}
+		if mutes.MutesRepository(repository) {
+			return p.localizeWithData(l, &i18n.Message{ID: "command.mute.repo.alreadyMuted", Other: "{{.Repository}} is already muted"}, data)
+		}
+
+		mutes.Repositories = append(mutes.Repositories, repository)
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.mute.repo.success",
+			Other: "`{{.Repository}}` is now muted. You'll no longer receive notifications about it.",
+		}, data)
+	})
+}
+
+func (p *Plugin) handleMuteLabel(args *model.CommandArgs, label string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
 
-	return fmt.Sprintf("`%v`", username) + " is now muted. You'll no longer receive notifications for comments in your PRs and issues."
+	return p.updateMutes(l, userInfo, func(mutes *Mutes) string {
+		data := map[string]interface{}{"Label": label}
+		if containsFold(mutes.Labels, label) {
+			return p.localizeWithData(l, &i18n.Message{ID: "command.mute.label.alreadyMuted", Other: "{{.Label}} is already muted"}, data)
+		}
+
+		mutes.Labels = append(mutes.Labels, label)
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.mute.label.success",
+			Other: "`{{.Label}}` is now muted. You'll no longer receive notifications about pull requests and issues with this label.",
+		}, data)
+	})
 }
 
 func (p *Plugin) handleUnmute(args *model.CommandArgs, username string, userInfo *GitHubUserInfo) string {
-	mutedUsernames := p.getMutedUsernames(userInfo)
-	userToMute := []string{username}
-	newMutedList := arrayDifference(mutedUsernames, userToMute)
+	l := p.getUserLocalizer(args.UserId)
 
-	_, err := p.client.KV.Set(userInfo.UserID+"-muted-users", []byte(strings.Join(newMutedList, ",")))
-	if err != nil {
-		return "Error occurred unmuting users"
+	return p.updateMutes(l, userInfo, func(mutes *Mutes) string {
+		mutes.Users = removeFold(mutes.Users, username)
+		return p.localizeWithData(l, &i18n.Message{ID: "command.mute.delete.success", Other: "`{{.Username}}` is no longer muted"}, map[string]interface{}{"Username": username})
+	})
+}
+
+func (p *Plugin) handleUnmuteRepository(args *model.CommandArgs, repository string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
+	owner, repo := parseOwnerAndRepo(repository, p.getConfiguration().getBaseURL())
+	if owner == "" || repo == "" {
+		return p.localize(l, muteRepoInvalidMessage)
 	}
+	repository = owner + "/" + repo
 
-	return fmt.Sprintf("`%v`", username) + " is no longer muted"
+	return p.updateMutes(l, userInfo, func(mutes *Mutes) string {
+		mutes.Repositories = removeFold(mutes.Repositories, repository)
+		return p.localizeWithData(l, &i18n.Message{ID: "command.mute.deleteRepo.success", Other: "`{{.Repository}}` is no longer muted"}, map[string]interface{}{"Repository": repository})
+	})
+}
+
+func (p *Plugin) handleUnmuteLabel(args *model.CommandArgs, label string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
+
+	return p.updateMutes(l, userInfo, func(mutes *Mutes) string {
+		mutes.Labels = removeFold(mutes.Labels, label)
+		return p.localizeWithData(l, &i18n.Message{ID: "command.mute.deleteLabel.success", Other: "`{{.Label}}` is no longer muted"}, map[string]interface{}{"Label": label})
+	})
 }
 
 func (p *Plugin) handleUnmuteAll(args *model.CommandArgs, userInfo *GitHubUserInfo) string {
-	_, err := p.client.KV.Set(userInfo.UserID+"-muted-users", []byte(""))
+	l := p.getUserLocalizer(args.UserId)
+	if err := p.storeMutes(userInfo.UserID, &Mutes{}); err != nil {
+		p.client.Log.Warn("Failed to store mutes", "error", err.Error())
+		return p.localize(l, &i18n.Message{ID: "command.mute.delete.error", Other: "Error occurred unmuting users"})
+	}
+
+	return p.localize(l, &i18n.Message{ID: "command.mute.deleteAll.success", Other: "Unmuted all users, repositories and labels"})
+}
+
+// updateMutes applies update to the mutes of the user and stores them. update returns the response
+// to the command.
+func (p *Plugin) updateMutes(l *i18n.Localizer, userInfo *GitHubUserInfo, update func(mutes *Mutes) string) string {
+	mutes, err := p.getMutes(userInfo.UserID)
 	if err != nil {
-		return "Error occurred unmuting users"
+		p.client.Log.Warn("Failed to get mutes", "error", err.Error())
+		return p.localize(l, muteErrorMessage)
 	}
 
-	return "Unmuted all users"
+	response := update(mutes)
+
+	if err := p.storeMutes(userInfo.UserID, mutes); err != nil {
+		p.client.Log.Warn("Failed to store mutes", "error", err.Error())
+		return p.localize(l, muteErrorMessage)
+	}
+
+	return response
 }
 
 func (p *Plugin) handleMuteCommand(_ *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
 	if len(parameters) == 0 {
-		return "Invalid mute command. Available commands are 'list', 'add' and 'delete'."
+		return p.localize(l, &i18n.Message{
+			ID:    "command.mute.invalid",
+			Other: "Invalid mute command. Available commands are 'list', 'add', 'repo', 'label', 'delete', 'delete-repo', 'delete-label' and 'delete-all'.",
+		})
 	}
 
 	command := parameters[0]
+	invalidParameters := &i18n.Message{ID: "command.mute.invalidParameters", Other: "Invalid number of parameters supplied to {{.Command}}"}
 
 	switch {
 	case command == "list":
 		return p.handleMuteList(args, userInfo)
 	case command == "add":
 		if len(parameters) != 2 {
-			return "Invalid number of parameters supplied to " + command
+			return p.localizeWithData(l, invalidParameters, map[string]interface{}{"Command": command})
 		}
 		return p.handleMuteAdd(args, parameters[1], userInfo)
+	case command == "repo":
+		if len(parameters) != 2 {
+			return p.localizeWithData(l, invalidParameters, map[string]interface{}{"Command": command})
+		}
+		return p.handleMuteRepository(args, parameters[1], userInfo)
+	case command == "label":
+		if len(parameters) < 2 {
+			return p.localizeWithData(l, invalidParameters, map[string]interface{}{"Command": command})
+		}
+		return p.handleMuteLabel(args, parseLabelArgument(parameters[1:]), userInfo)
 	case command == "delete":
 		if len(parameters) != 2 {
-			return "Invalid number of parameters supplied to " + command
+			return p.localizeWithData(l, invalidParameters, map[string]interface{}{"Command": command})
 		}
 		return p.handleUnmute(args, parameters[1], userInfo)
+	case command == "delete-repo":
+		if len(parameters) != 2 {
+			return p.localizeWithData(l, invalidParameters, map[string]interface{}{"Command": command})
+		}
+		return p.handleUnmuteRepository(args, parameters[1], userInfo)
+	case command == "delete-label":
+		if len(parameters) < 2 {
+			return p.localizeWithData(l, invalidParameters, map[string]interface{}{"Command": command})
+		}
+		return p.handleUnmuteLabel(args, parseLabelArgument(parameters[1:]), userInfo)
 	case command == "delete-all":
 		return p.handleUnmuteAll(args, userInfo)
 	default:
-		return fmt.Sprintf("Unknown subcommand %v", command)
+		return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
 	}
 }
 
-// Returns the elements in a, that are not in b
-func arrayDifference(a, b []string) []string {
-	mb := make(map[string]struct{}, len(b))
-	for _, x := range b {
-		mb[x] = struct{}{}
-	}
-	var diff []string
-	for _, x := range a {
-		if _, found := mb[x]; !found {
-			diff = append(diff, x)
-		}
-	}
-	return diff
+// parseLabelArgument joins the parameters making up a label, which may be quoted.
+func parseLabelArgument(parameters []string) string {
+	return strings.Trim(strings.Join(parameters, " "), `"`)
 }
 
 func (p *Plugin) handleSubscribe(c *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
 	switch {
 	case len(parameters) == 0:
-		return "Please specify a repository or 'list' command."
+		return p.localize(p.getUserLocalizer(args.UserId), &i18n.Message{ID: "command.subscribe.missingRepository", Other: "Please specify a repository or 'list' command."})
 	case len(parameters) == 1 && parameters[0] == "list":
 		return p.handleSubscriptionsList(c, args, parameters[1:], userInfo)
 	default:
@@ -232,7 +371,10 @@ func (p *Plugin) handleSubscribe(c *plugin.Context, args *model.CommandArgs, par
 
 func (p *Plugin) handleSubscriptions(c *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
 	if len(parameters) == 0 {
-		return "Invalid subscribe command. Available commands are 'list', 'add' and 'delete'."
+		return p.localize(p.getUserLocalizer(args.UserId), &i18n.Message{
+			ID:    "command.subscriptions.invalid",
+			Other: "Invalid subscribe command. Available commands are 'list', 'add', 'delete', 'export' and 'import'.",
+		})
 	}
 
 	command := parameters[0]
@@ -245,22 +387,137 @@ func (p *Plugin) handleSubscriptions(c *plugin.Context, args *model.CommandArgs,
 		return p.handleSubscribesAdd(c, args, parameters, userInfo)
 	case command == "delete":
 		return p.handleUnsubscribe(c, args, parameters, userInfo)
+	case command == "export":
+		return p.handleSubscriptionsExport(c, args, parameters, userInfo)
+	case command == "import":
+		return p.handleSubscriptionsImport(c, args, parameters, userInfo)
 	default:
-		return fmt.Sprintf("Unknown subcommand %v", command)
+		return p.localizeWithData(p.getUserLocalizer(args.UserId), unknownSubcommandMessage, map[string]interface{}{"Command": command})
+	}
+}
+
+func (p *Plugin) handleSubscriptionsExport(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
+	isSysAdmin, err := p.isAuthorizedSysAdmin(args.UserId)
+	if err != nil {
+		p.client.Log.Warn("Failed to check if user is System Admin", "error", err.Error())
+		return p.localize(l, permissionsCheckErrorMessage)
+	}
+	if !isSysAdmin {
+		return p.localize(l, &i18n.Message{ID: "command.subscriptions.export.notAdmin", Other: "Only System Admins are allowed to export subscriptions."})
+	}
+
+	filter := SubscriptionFilter{}
+	format := subscriptionsConfigFormatYAML
+
+	if len(parameters)%2 != 0 {
+		return p.localize(l, invalidFlagFormatMessage)
+	}
+	for i := 0; i < len(parameters); i += 2 {
+		flag := parameters[i]
+		value := parameters[i+1]
+
+		if !isFlag(flag) {
+			return p.localize(l, invalidFlagFormatMessage)
+		}
+
+		switch parseFlag(flag) {
+		case "repo":
+			filter.Repository = value
+		case "team":
+			filter.Team = value
+		case "format":
+			if value != subscriptionsConfigFormatYAML && value != subscriptionsConfigFormatJSON {
+				return p.localize(l, &i18n.Message{ID: "command.subscriptions.export.invalidFormat", Other: "Invalid format. Accepted values are: \"yaml\" or \"json\"."})
+			}
+			format = value
+		default:
+			return p.localizeWithData(l, unsupportedFlagMessage, map[string]interface{}{"Flag": flag})
+		}
+	}
+
+	config, err := p.ExportSubscriptionsConfig(filter)
+	if err != nil {
+		p.client.Log.Warn("Failed to export subscriptions", "error", err.Error())
+		return p.localize(l, exportSubscriptionsErrorMessage)
+	}
+
+	data, err := marshalSubscriptionsConfig(config, format)
+	if err != nil {
+		p.client.Log.Warn("Failed to marshal subscriptions", "error", err.Error())
+		return p.localize(l, exportSubscriptionsErrorMessage)
+	}
+
+	return "```" + format + "\n" + strings.TrimSuffix(string(data), "\n") + "\n```"
+}
+
+func (p *Plugin) handleSubscriptionsImport(_ *plugin.Context, args *model.CommandArgs, _ []string, _ *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
+	isSysAdmin, err := p.isAuthorizedSysAdmin(args.UserId)
+	if err != nil {
+		p.client.Log.Warn("Failed to check if user is System Admin", "error", err.Error())
+		return p.localize(l, permissionsCheckErrorMessage)
+	}
+	if !isSysAdmin {
+		return p.localize(l, &i18n.Message{ID: "command.subscriptions.import.notAdmin", Other: "Only System Admins are allowed to import subscriptions."})
+	}
+
+	err = p.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       "/plugins/" + Manifest.Id + "/api/v1/admin/subscriptions/import/dialog",
+		Dialog: model.Dialog{
+			CallbackId:       "import_subscriptions",
+			Title:            p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.title", Other: "Import Subscriptions"}),
+			IntroductionText: p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.introduction", Other: "Paste a subscription configuration in YAML or JSON format, as produced by `/github subscriptions export`."}),
+			SubmitLabel:      p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.submit", Other: "Import"}),
+			Elements: []model.DialogElement{
+				{
+					DisplayName: p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.config", Other: "Configuration"}),
+					Name:        "config",
+					Type:        "textarea",
+					MaxLength:   subscriptionsConfigMaxLength,
+				},
+				{
+					DisplayName: p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.dryRun", Other: "Dry run"}),
+					Name:        "dry_run",
+					Type:        "bool",
+					Default:     "true",
+					Placeholder: p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.dryRun.help", Other: "Only show the changes without applying them"}),
+					Optional:    true,
+				},
+				{
+					DisplayName: p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.prune", Other: "Prune"}),
+					Name:        "prune",
+					Type:        "bool",
+					Placeholder: p.localize(l, &i18n.Message{ID: "command.subscriptions.import.dialog.prune.help", Other: "Remove subscriptions that are missing from the configuration"}),
+					Optional:    true,
+				},
+			},
+		},
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to open import dialog", "error", err.Error())
+		return p.localize(l, &i18n.Message{ID: "command.subscriptions.import.error", Other: "Encountered an error opening the import dialog."})
 	}
+
+	return ""
 }
 
-func (p *Plugin) handleSubscriptionsList(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ *GitHubUserInfo) string {
+func (p *Plugin) handleSubscriptionsList(_ *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
 	txt := ""
 	subs, err := p.GetSubscriptionsByChannel(args.ChannelId)
 	if err != nil {
 		return err.Error()
 	}
 
+	ctx := context.Background()
+	var githubClient *github.Client
+
+	l := p.getUserLocalizer(args.UserId)
 	if len(subs) == 0 {
-		txt = "Currently there are no subscriptions in this channel"
+		txt = p.localize(l, &i18n.Message{ID: "command.subscriptions.list.empty", Other: "Currently there are no subscriptions in this channel"})
 	} else {
-		txt = "### Subscriptions in this channel\n"
+		txt = "### " + p.localize(l, &i18n.Message{ID: "command.subscriptions.list.title", Other: "Subscriptions in this channel"}) + "\n"
 	}
 	for _, sub := range subs {
 		subFlags := sub.Flags.String()
@@ -269,33 +526,62 @@ func (p *Plugin) handleSubscriptionsList(_ *plugin.Context, args *model.CommandA
 			txt += fmt.Sprintf(" %s", subFlags)
 		}
 		txt += "\n"
+
+		owner, repo := parseOwnerAndRepo(sub.Repository, "")
+		if !isRepositoryPattern(repo) {
+			continue
+		}
+
+		if githubClient == nil {
+			githubClient = p.githubConnectUser(ctx, userInfo)
+		}
+		matches, err := p.getRepositoriesMatchingPattern(ctx, githubClient, owner, repo)
+		if err != nil {
+			p.client.Log.Warn("Failed to list repositories matching pattern", "pattern", sub.Repository, "error", err.Error())
+			txt += "  * " + p.localize(l, &i18n.Message{ID: "command.subscriptions.list.patternError", Other: "Unable to list the repositories currently matching this pattern"}) + "\n"
+			continue
+		}
+
+		names := make([]string, len(matches))
+		for i, match := range matches {
+			names[i] = fmt.Sprintf("`%s`", match.GetName())
+		}
+		if len(names) == 0 {
+			txt += "  * " + p.localize(l, &i18n.Message{ID: "command.subscriptions.list.noMatches", Other: "Currently matches no repositories"}) + "\n"
+		} else {
+			txt += "  * " + p.localizeWithData(l, &i18n.Message{
+				ID:    "command.subscriptions.list.matches",
+				Other: "Currently matches {{.Repositories}}",
+			}, map[string]interface{}{"Repositories": strings.Join(names, ", ")}) + "\n"
+		}
 	}
 
 	return txt
 }
 
 func (p *Plugin) handleSubscribesAdd(_ *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
 	if len(parameters) == 0 {
-		return "Please specify a repository."
+		return p.localize(l, missingRepositoryMessage)
 	}
 
 	config := p.getConfiguration()
 
-	features := "pulls,issues,creates,deletes"
+	features := defaultFeatures
 	flags := SubscriptionFlags{}
 
 	if len(parameters) > 1 {
 		flagParams := parameters[1:]
 
 		if len(flagParams)%2 != 0 {
-			return "Please use the correct format for flags: --<name> <value>"
+			return p.localize(l, invalidFlagFormatMessage)
 		}
 		for i := 0; i < len(flagParams); i += 2 {
 			flag := flagParams[i]
 			value := flagParams[i+1]
 
 			if !isFlag(flag) {
-				return "Please use the correct format for flags: --<name> <value>"
+				return p.localize(l, invalidFlagFormatMessage)
 			}
 			parsedFlag := parseFlag(flag)
 
@@ -304,24 +590,12 @@ func (p *Plugin) handleSubscribesAdd(_ *plugin.Context, args *model.CommandArgs,
 				continue
 			}
 			if err := flags.AddFlag(parsedFlag, value); err != nil {
-				return fmt.Sprintf("Unsupported value for flag %s", flag)
+				return p.localizeWithData(l, &i18n.Message{ID: "command.subscriptions.add.unsupportedValue", Other: "Unsupported value for flag {{.Flag}}"}, map[string]interface{}{"Flag": flag})
 			}
 		}
 
-		fs := strings.Split(features, ",")
-		if SliceContainsString(fs, featureIssues) && SliceContainsString(fs, featureIssueCreation) {
-			return "Feature list cannot contain both issue and issue_creations"
-		}
-		if SliceContainsString(fs, featurePulls) && SliceContainsString(fs, featurePullsMerged) {
-			return "Feature list cannot contain both pulls and pulls_merged"
-		}
-		ok, ifs := validateFeatures(fs)
-		if !ok {
-			msg := fmt.Sprintf("Invalid feature(s) provided: %s", strings.Join(ifs, ","))
-			if len(ifs) == 0 {
-				msg = "Feature list must have \"pulls\" or \"issues\" when using a label."
-			}
-			return msg
+		if err := validateFeatureList(features); err != nil {
+			return err.Error()
 		}
 	}
 
@@ -334,87 +608,147 @@ func (p *Plugin) handleSubscribesAdd(_ *plugin.Context, args *model.CommandArgs,
 			return err.Error()
 		}
 
-		return fmt.Sprintf("Successfully subscribed to organization %s.", owner)
+		if flags.Topic != "" {
+			return p.localizeWithData(l, &i18n.Message{
+				ID:    "command.subscriptions.add.organizationTopic",
+				Other: "Successfully subscribed to repositories of organization {{.Owner}} with the topic `{{.Topic}}`.",
+			}, map[string]interface{}{"Owner": owner, "Topic": flags.Topic})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.subscriptions.add.organization",
+			Other: "Successfully subscribed to organization {{.Owner}}.",
+		}, map[string]interface{}{"Owner": owner})
 	}
 
 	if err := p.Subscribe(ctx, githubClient, args.UserId, owner, repo, args.ChannelId, features, flags); err != nil {
 		return err.Error()
 	}
+
+	if isRepositoryPattern(repo) {
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.subscriptions.add.pattern",
+			Other: "Successfully subscribed to repositories matching `{{.Pattern}}`. Use `/github subscriptions list` to see which repositories it currently covers.",
+		}, map[string]interface{}{"Pattern": fullNameFromOwnerAndRepo(owner, repo)})
+	}
+
 	repoLink := config.getBaseURL() + owner + "/" + repo
 
-	msg := fmt.Sprintf("Successfully subscribed to [%s](%s).", repo, repoLink)
+	msg := p.localizeWithData(l, &i18n.Message{
+		ID:    "command.subscriptions.add.repository",
+		Other: "Successfully subscribed to [{{.Repository}}]({{.Link}}).",
+	}, map[string]interface{}{"Repository": repo, "Link": repoLink})
 
 	ghRepo, _, err := githubClient.Repositories.Get(ctx, owner, repo)
 	if err != nil {
 		p.client.Log.Warn("Failed to fetch repository", "error", err.Error())
 	} else if ghRepo != nil && ghRepo.GetPrivate() {
-		msg += "\n\n**Warning:** You subscribed to a private repository. Anyone with access to this channel will be able to read the events getting posted here."
+		msg += "\n\n" + p.localize(l, &i18n.Message{
+			ID:    "command.subscriptions.add.privateWarning",
+			Other: "**Warning:** You subscribed to a private repository. Anyone with access to this channel will be able to read the events getting posted here.",
+		})
 	}
 
 	return msg
 }
 
 func (p *Plugin) handleUnsubscribe(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
 	if len(parameters) == 0 {
-		return "Please specify a repository."
+		return p.localize(l, missingRepositoryMessage)
 	}
 
 	repo := parameters[0]
 
 	if err := p.Unsubscribe(args.ChannelId, repo); err != nil {
 		p.client.Log.Warn("Failed to unsubscribe", "repo", repo, "error", err.Error())
-		return "Encountered an error trying to unsubscribe. Please try again."
+		return p.localize(l, &i18n.Message{ID: "command.subscriptions.delete.error", Other: "Encountered an error trying to unsubscribe. Please try again."})
 	}
 
-	return fmt.Sprintf("Successfully unsubscribed from %s.", repo)
+	return p.localizeWithData(l, &i18n.Message{ID: "command.subscriptions.delete.success", Other: "Successfully unsubscribed from {{.Repository}}."}, map[string]interface{}{"Repository": repo})
 }
 
 func (p *Plugin) handleDisconnect(_ *plugin.Context, args *model.CommandArgs, _ []string, _ *GitHubUserInfo) string {
 	p.disconnectGitHubAccount(args.UserId)
-	return "Disconnected your GitHub account."
+	return p.localize(p.getUserLocalizer(args.UserId), &i18n.Message{ID: "command.disconnect.success", Other: "Disconnected your GitHub account."})
 }
 
-func (p *Plugin) handleTodo(_ *plugin.Context, _ *model.CommandArgs, _ []string, userInfo *GitHubUserInfo) string {
+func (p *Plugin) handleTodo(_ *plugin.Context, args *model.CommandArgs, _ []string, userInfo *GitHubUserInfo) string {
 	githubClient := p.githubConnectUser(context.Background(), userInfo)
 
 	text, err := p.GetToDo(context.Background(), userInfo.GitHubUsername, githubClient)
 	if err != nil {
 		p.client.Log.Warn("Failed get get Todos", "error", err.Error())
-		return "Encountered an error getting your to do items."
+		return p.localize(p.getUserLocalizer(args.UserId), &i18n.Message{ID: "command.todo.error", Other: "Encountered an error getting your to do items."})
 	}
 
 	return text
 }
 
-func (p *Plugin) handleMe(_ *plugin.Context, _ *model.CommandArgs, _ []string, userInfo *GitHubUserInfo) string {
+func (p *Plugin) handleMe(_ *plugin.Context, args *model.CommandArgs, _ []string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
 	githubClient := p.githubConnectUser(context.Background(), userInfo)
 	gitUser, _, err := githubClient.Users.Get(context.Background(), "")
 	if err != nil {
-		return "Encountered an error getting your GitHub profile."
+		return p.localize(l, &i18n.Message{ID: "command.me.error", Other: "Encountered an error getting your GitHub profile."})
 	}
 
-	text := fmt.Sprintf("You are connected to GitHub as:\n# [![image](%s =40x40)](%s) [%s](%s)", gitUser.GetAvatarURL(), gitUser.GetHTMLURL(), gitUser.GetLogin(), gitUser.GetHTMLURL())
+	text := p.localize(l, &i18n.Message{ID: "command.me.connected", Other: "You are connected to GitHub as:"}) +
+		fmt.Sprintf("\n# [![image](%s =40x40)](%s) [%s](%s)", gitUser.GetAvatarURL(), gitUser.GetHTMLURL(), gitUser.GetLogin(), gitUser.GetHTMLURL())
 	return text
 }
 
-func (p *Plugin) handleHelp(_ *plugin.Context, _ *model.CommandArgs, _ []string, _ *GitHubUserInfo) string {
-	message, err := renderTemplate("helpText", p.getConfiguration())
+func (p *Plugin) handleHelp(_ *plugin.Context, args *model.CommandArgs, _ []string, _ *GitHubUserInfo) string {
+	locale := p.getUserLocale(args.UserId)
+	l := p.getLocalizer(locale)
+	message, err := renderLocalizedTemplate(locale, "helpText", p.getConfiguration())
 	if err != nil {
 		p.client.Log.Warn("Failed to render help template", "error", err.Error())
-		return "Encountered an error posting help text."
+		return p.localize(l, &i18n.Message{ID: "command.help.error", Other: "Encountered an error posting help text."})
+	}
+
+	return "###### " + p.localize(l, &i18n.Message{ID: "command.help.title", Other: "Mattermost GitHub Plugin - Slash Command Help"}) + "\n" + message
+}
+
+// handleNotificationTypeSetting turns the notifications of a single type on or off.
+func (p *Plugin) handleNotificationTypeSetting(l *i18n.Localizer, userInfo *GitHubUserInfo, notificationType, value string) string {
+	if !containsValue(notificationTypes, notificationType) {
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.settings.notifications.unknownType",
+			Other: "Unknown notification type {{.Type}}. Available types are: {{.Types}}.",
+		}, map[string]interface{}{"Type": notificationType, "Types": strings.Join(notificationTypes, ", ")})
+	}
+
+	switch value {
+	case settingOn:
+		userInfo.Settings.SetNotificationEnabled(notificationType, true)
+	case settingOff:
+		userInfo.Settings.SetNotificationEnabled(notificationType, false)
+	default:
+		return p.localize(l, &i18n.Message{ID: "command.settings.notifications.invalid", Other: "Invalid value. Accepted values are: \"on\" or \"off\"."})
+	}
+
+	if err := p.storeGitHubUserInfo(userInfo); err != nil {
+		p.client.Log.Warn("Failed to store github user info", "error", err.Error())
+		return p.localize(l, &i18n.Message{ID: "command.settings.error", Other: "Failed to store settings"})
 	}
 
-	return "###### Mattermost GitHub Plugin - Slash Command Help\n" + message
+	return p.localize(l, &i18n.Message{ID: "command.settings.success", Other: "Settings updated."})
 }
 
-func (p *Plugin) handleSettings(_ *plugin.Context, _ *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
+func (p *Plugin) handleSettings(_ *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
 	if len(parameters) < 2 {
-		return "Please specify both a setting and value. Use `/github help` for more usage information."
+		return p.localize(l, &i18n.Message{ID: "command.settings.missingParameters", Other: "Please specify both a setting and value. Use `/github help` for more usage information."})
 	}
 
 	setting := parameters[0]
 	settingValue := parameters[1]
 
+	if setting == settingNotifications && len(parameters) > 2 {
+		return p.handleNotificationTypeSetting(l, userInfo, parameters[1], parameters[2])
+	}
+
 	switch setting {
 	case settingNotifications:
 		switch settingValue {
@@ -423,7 +757,7 @@ func (p *Plugin) handleSettings(_ *plugin.Context, _ *model.CommandArgs, paramet
 		case settingOff:
 			userInfo.Settings.Notifications = false
 		default:
-			return "Invalid value. Accepted values are: \"on\" or \"off\"."
+			return p.localize(l, &i18n.Message{ID: "command.settings.notifications.invalid", Other: "Invalid value. Accepted values are: \"on\" or \"off\"."})
 		}
 	case settingReminders:
 		switch settingValue {
@@ -437,10 +771,10 @@ func (p *Plugin) handleSettings(_ *plugin.Context, _ *model.CommandArgs, paramet
 			userInfo.Settings.DailyReminder = true
 			userInfo.Settings.DailyReminderOnChange = true
 		default:
-			return "Invalid value. Accepted values are: \"on\" or \"off\" or \"on-change\" ."
+			return p.localize(l, &i18n.Message{ID: "command.settings.reminders.invalid", Other: "Invalid value. Accepted values are: \"on\" or \"off\" or \"on-change\" ."})
 		}
 	default:
-		return "Unknown setting " + setting
+		return p.localizeWithData(l, &i18n.Message{ID: "command.settings.unknown", Other: "Unknown setting {{.Setting}}"}, map[string]interface{}{"Setting": setting})
 	}
 
 	if setting == settingNotifications {
@@ -466,15 +800,16 @@ func (p *Plugin) handleSettings(_ *plugin.Context, _ *model.CommandArgs, paramet
 	err := p.storeGitHubUserInfo(userInfo)
 	if err != nil {
 		p.client.Log.Warn("Failed to store github user info", "error", err.Error())
-		return "Failed to store settings"
+		return p.localize(l, &i18n.Message{ID: "command.settings.error", Other: "Failed to store settings"})
 	}
 
-	return "Settings updated."
+	return p.localize(l, &i18n.Message{ID: "command.settings.success", Other: "Settings updated."})
 }
 
 func (p *Plugin) handleIssue(_ *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string {
+	l := p.getUserLocalizer(args.UserId)
 	if len(parameters) == 0 {
-		return "Invalid issue command. Available command is 'create'."
+		return p.localize(l, &i18n.Message{ID: "command.issue.invalid", Other: "Invalid issue command. Available command is 'create'."})
 	}
 
 	command := parameters[0]
@@ -485,21 +820,22 @@ func (p *Plugin) handleIssue(_ *plugin.Context, args *model.CommandArgs, paramet
 		p.openIssueCreateModal(args.UserId, args.ChannelId, strings.Join(parameters, " "))
 		return ""
 	default:
-		return fmt.Sprintf("Unknown subcommand %v", command)
+		return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
 	}
 }
 
 func (p *Plugin) handleSetup(c *plugin.Context, args *model.CommandArgs, parameters []string) string {
 	userID := args.UserId
+	l := p.getUserLocalizer(userID)
 	isSysAdmin, err := p.isAuthorizedSysAdmin(userID)
 	if err != nil {
 		p.client.Log.Warn("Failed to check if user is System Admin", "error", err.Error())
 
-		return "Error checking user's permissions"
+		return p.localize(l, permissionsCheckErrorMessage)
 	}
 
 	if !isSysAdmin {
-		return "Only System Admins are allowed to set up the plugin."
+		return p.localize(l, &i18n.Message{ID: "command.setup.notAdmin", Other: "Only System Admins are allowed to set up the plugin."})
 	}
 
 	if len(parameters) == 0 {
@@ -515,7 +851,7 @@ func (p *Plugin) handleSetup(c *plugin.Context, args *model.CommandArgs, paramet
 		case command == "announcement":
 			err = p.flowManager.StartAnnouncementWizard(userID)
 		default:
-			return fmt.Sprintf("Unknown subcommand %v", command)
+			return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
 		}
 	}
 
@@ -526,6 +862,533 @@ func (p *Plugin) handleSetup(c *plugin.Context, args *model.CommandArgs, paramet
 	return ""
 }
 
+func (p *Plugin) handleAdmin(c *plugin.Context, args *model.CommandArgs, parameters []string) string {
+	l := p.getUserLocalizer(args.UserId)
+	isSysAdmin, err := p.isAuthorizedSysAdmin(args.UserId)
+	if err != nil {
+		p.client.Log.Warn("Failed to check if user is System Admin", "error", err.Error())
+
+		return p.localize(l, permissionsCheckErrorMessage)
+	}
+
+	if !isSysAdmin {
+		return p.localize(l, &i18n.Message{ID: "command.admin.notAdmin", Other: "Only System Admins are allowed to use admin commands."})
+	}
+
+	if len(parameters) == 0 {
+		return p.localize(l, &i18n.Message{ID: "command.admin.invalid", Other: "Invalid admin command. Available commands are 'subscriptions', 'template', 'teammap' and 'usermap'."})
+	}
+
+	command := parameters[0]
+	parameters = parameters[1:]
+
+	switch {
+	case command == "subscriptions":
+		return p.handleAdminSubscriptions(c, args, parameters)
+	case command == "template":
+		return p.handleAdminTemplate(c, args, parameters)
+	case command == "teammap":
+		return p.handleAdminTeamMap(c, args, parameters)
+	case command == "usermap":
+		return p.handleAdminUserMap(c, args, parameters)
+	default:
+		return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
+	}
+}
+
+func (p *Plugin) handleAdminSubscriptions(_ *plugin.Context, args *model.CommandArgs, parameters []string) string {
+	l := p.getUserLocalizer(args.UserId)
+	if len(parameters) == 0 {
+		return p.localize(l, &i18n.Message{ID: "command.admin.subscriptions.invalid", Other: "Invalid admin subscriptions command. Available commands are 'list', 'delete' and 'export'."})
+	}
+
+	command := parameters[0]
+
+	filter := SubscriptionFilter{}
+	format := "json"
+
+	flagParams := parameters[1:]
+	if len(flagParams)%2 != 0 {
+		return p.localize(l, invalidFlagFormatMessage)
+	}
+	for i := 0; i < len(flagParams); i += 2 {
+		flag := flagParams[i]
+		value := flagParams[i+1]
+
+		if !isFlag(flag) {
+			return p.localize(l, invalidFlagFormatMessage)
+		}
+
+		switch parseFlag(flag) {
+		case "repo":
+			filter.Repository = value
+		case "team":
+			filter.Team = value
+		case "format":
+			if value != "json" && value != "csv" {
+				return p.localize(l, &i18n.Message{ID: "command.admin.subscriptions.invalidFormat", Other: "Invalid format. Accepted values are: \"json\" or \"csv\"."})
+			}
+			format = value
+		default:
+			return p.localizeWithData(l, unsupportedFlagMessage, map[string]interface{}{"Flag": flag})
+		}
+	}
+
+	switch command {
+	case "list":
+		infos, err := p.GetSubscriptionInfos(filter)
+		if err != nil {
+			p.client.Log.Warn("Failed to get subscriptions", "error", err.Error())
+			return p.localize(l, getSubscriptionsErrorMessage)
+		}
+
+		if len(infos) == 0 {
+			return p.localize(l, &i18n.Message{ID: "command.admin.subscriptions.list.empty", Other: "There are no matching subscriptions."})
+		}
+
+		txt := "### " + p.localize(l, &i18n.Message{ID: "command.admin.subscriptions.list.title", Other: "Subscriptions"}) + "\n"
+		txt += "| " + p.localize(l, &i18n.Message{
+			ID:    "command.admin.subscriptions.list.header",
+			Other: "Repository | Team | Channel | Creator | Features | Flags",
+		}) + " |\n"
+		txt += "|:-----------|:-----|:--------|:--------|:---------|:------|\n"
+		for _, info := range infos {
+			channel := info.ChannelID
+			if info.Channel != "" {
+				channel = "~" + info.Channel
+			}
+			creator := info.CreatorID
+			if info.Creator != "" {
+				creator = "@" + info.Creator
+			}
+			txt += fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s |\n", info.Repository, info.Team, channel, creator, info.Features, info.Flags)
+		}
+
+		return txt
+	case "delete":
+		if filter.IsEmpty() {
+			return p.localize(l, &i18n.Message{ID: "command.admin.subscriptions.delete.missingFilter", Other: "Please specify at least one of --repo or --team to select the subscriptions to delete."})
+		}
+
+		removed, err := p.RemoveFilteredSubscriptions(filter)
+		if err != nil {
+			p.client.Log.Warn("Failed to delete subscriptions", "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.subscriptions.delete.error", Other: "Encountered an error deleting subscriptions."})
+		}
+
+		return p.localizePlural(l, &i18n.Message{
+			ID:    "command.admin.subscriptions.delete.success",
+			One:   "Successfully deleted {{.Count}} subscription.",
+			Other: "Successfully deleted {{.Count}} subscriptions.",
+		}, removed, nil)
+	case "export":
+		infos, err := p.GetSubscriptionInfos(filter)
+		if err != nil {
+			p.client.Log.Warn("Failed to get subscriptions", "error", err.Error())
+			return p.localize(l, getSubscriptionsErrorMessage)
+		}
+
+		var buf bytes.Buffer
+		if format == "csv" {
+			err = writeSubscriptionInfosCSV(&buf, infos)
+		} else {
+			encoder := json.NewEncoder(&buf)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(infos)
+		}
+		if err != nil {
+			p.client.Log.Warn("Failed to export subscriptions", "error", err.Error())
+			return p.localize(l, exportSubscriptionsErrorMessage)
+		}
+
+		return "```" + format + "\n" + strings.TrimSuffix(buf.String(), "\n") + "\n```"
+	default:
+		return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
+	}
+}
+
+func (p *Plugin) handleAdminTemplate(_ *plugin.Context, args *model.CommandArgs, parameters []string) string {
+	l := p.getUserLocalizer(args.UserId)
+	if len(parameters) == 0 {
+		return p.localize(l, &i18n.Message{ID: "command.admin.template.invalid", Other: "Invalid admin template command. Available commands are 'list', 'get', 'set' and 'reset'."})
+	}
+
+	command := parameters[0]
+	if command == "list" {
+		customized := getCustomTemplateSources()
+
+		txt := "### " + p.localize(l, &i18n.Message{ID: "command.admin.template.list.title", Other: "Notification templates"}) + "\n"
+		for _, name := range getCustomizableTemplateNames() {
+			if _, ok := customized[name]; ok {
+				txt += fmt.Sprintf("* `%s` (%s)\n", name, p.localize(l, &i18n.Message{ID: "command.admin.template.list.customized", Other: "customized"}))
+			} else {
+				txt += fmt.Sprintf("* `%s`\n", name)
+			}
+		}
+
+		return txt
+	}
+
+	if len(parameters) < 2 {
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.admin.template.missingName",
+			Other: "Please specify the name of the template, e.g. `/github admin template {{.Command}} newPR`.",
+		}, map[string]interface{}{"Command": command})
+	}
+
+	name := parameters[1]
+	data := map[string]interface{}{"Name": name}
+	if !isCustomizableTemplate(name) {
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.admin.template.unknown",
+			Other: "Unknown template `{{.Name}}`. Use `/github admin template list` to see the available templates.",
+		}, data)
+	}
+
+	switch command {
+	case "get":
+		if source, ok := getCustomTemplateSources()[name]; ok {
+			return p.localizeWithData(l, &i18n.Message{ID: "command.admin.template.get.customized", Other: "Template `{{.Name}}` is customized:"}, data) +
+				fmt.Sprintf("\n```\n%s\n```", source)
+		}
+
+		source, err := getBuiltInTemplateSource(name)
+		if err != nil {
+			p.client.Log.Warn("Failed to get built-in template", "template", name, "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.template.get.error", Other: "Encountered an error getting the template."})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{ID: "command.admin.template.get.builtIn", Other: "Template `{{.Name}}` uses the built-in template:"}, data) +
+			fmt.Sprintf("\n```\n%s\n```", source)
+	case "set":
+		source := parseTemplateArgument(args.Command)
+		if source == "" {
+			return p.localizeWithData(l, &i18n.Message{
+				ID:    "command.admin.template.set.missingTemplate",
+				Other: "Please specify the template, e.g. `/github admin template set {{.Name}} <template>`.",
+			}, data)
+		}
+
+		if err := p.saveCustomTemplate(name, source); err != nil {
+			return p.localizeWithData(l, &i18n.Message{
+				ID:    "command.admin.template.set.error",
+				Other: "Unable to set template `{{.Name}}`: {{.Error}}",
+			}, map[string]interface{}{"Name": name, "Error": err.Error()})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{ID: "command.admin.template.set.success", Other: "Successfully set template `{{.Name}}`."}, data)
+	case "reset":
+		if err := p.saveCustomTemplate(name, ""); err != nil {
+			p.client.Log.Warn("Failed to reset template", "template", name, "error", err.Error())
+			return p.localizeWithData(l, &i18n.Message{ID: "command.admin.template.reset.error", Other: "Encountered an error resetting template `{{.Name}}`."}, data)
+		}
+
+		return p.localizeWithData(l, &i18n.Message{ID: "command.admin.template.reset.success", Other: "Successfully reset template `{{.Name}}` to the built-in template."}, data)
+	default:
+		return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
+	}
+}
+
+func (p *Plugin) handleAdminTeamMap(_ *plugin.Context, args *model.CommandArgs, parameters []string) string {
+	l := p.getUserLocalizer(args.UserId)
+	if len(parameters) == 0 {
+		return p.localize(l, &i18n.Message{ID: "command.admin.teammap.invalid", Other: "Invalid admin teammap command. Available commands are 'list', 'add' and 'delete'."})
+	}
+
+	command := parameters[0]
+	if command == "list" {
+		mappings, err := p.getTeamMappings()
+		if err != nil {
+			p.client.Log.Warn("Failed to get team mappings", "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.teammap.list.error", Other: "Encountered an error getting the team mappings."})
+		}
+
+		if len(mappings) == 0 {
+			return p.localize(l, &i18n.Message{ID: "command.admin.teammap.list.empty", Other: "No GitHub teams are mapped."})
+		}
+
+		teams := make([]string, 0, len(mappings))
+		for team := range mappings {
+			teams = append(teams, team)
+		}
+		sort.Strings(teams)
+
+		txt := "### " + p.localize(l, &i18n.Message{ID: "command.admin.teammap.list.title", Other: "GitHub team mappings"}) + "\n"
+		for _, team := range teams {
+			txt += fmt.Sprintf("* `%s`: %s\n", team, p.describeTeamMapping(mappings[team]))
+		}
+
+		return txt
+	}
+
+	if len(parameters) < 2 || normalizeTeamName(parameters[1]) == "" {
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.admin.teammap.missingTeam",
+			Other: "Please specify the GitHub team, e.g. `/github admin teammap {{.Command}} my-org/my-team`.",
+		}, map[string]interface{}{"Command": command})
+	}
+	team := normalizeTeamName(parameters[1])
+
+	switch command {
+	case "add":
+		mapping := &TeamMapping{}
+		switch {
+		case len(parameters) > 2 && strings.HasPrefix(parameters[2], "@"):
+			group, err := p.client.Group.GetByName(strings.TrimPrefix(parameters[2], "@"))
+			if err != nil {
+				return p.localize(l, &i18n.Message{ID: "command.admin.teammap.add.unknownGroup", Other: "Unable to find the user group. Please specify it by name, e.g. `@developers`."})
+			}
+			mapping.GroupID = group.Id
+		case len(parameters) > 2:
+			channel, err := p.client.Channel.GetByName(args.TeamId, strings.TrimPrefix(parameters[2], "~"), false)
+			if err != nil {
+				return p.localize(l, &i18n.Message{ID: "command.admin.teammap.add.unknownChannel", Other: "Unable to find the channel. Please specify a channel of this team, e.g. `~town-square`."})
+			}
+			mapping.ChannelID = channel.Id
+		default:
+			mapping.ChannelID = args.ChannelId
+		}
+
+		err := p.updateTeamMappings(func(mappings map[string]*TeamMapping) {
+			mappings[team] = mapping
+		})
+		if err != nil {
+			p.client.Log.Warn("Failed to store team mapping", "team", team, "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.teammap.add.error", Other: "Encountered an error mapping the team."})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.admin.teammap.add.success",
+			Other: "Mentions of and review requests for `{{.Team}}` will be sent to {{.Target}}.",
+		}, map[string]interface{}{"Team": team, "Target": p.describeTeamMapping(mapping)})
+	case "delete":
+		removed := false
+		err := p.updateTeamMappings(func(mappings map[string]*TeamMapping) {
+			_, removed = mappings[team]
+			delete(mappings, team)
+		})
+		if err != nil {
+			p.client.Log.Warn("Failed to delete team mapping", "team", team, "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.teammap.delete.error", Other: "Encountered an error deleting the team mapping."})
+		}
+
+		if !removed {
+			return p.localizeWithData(l, &i18n.Message{ID: "command.admin.teammap.delete.notMapped", Other: "`{{.Team}}` is not mapped."}, map[string]interface{}{"Team": team})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.admin.teammap.delete.success",
+			Other: "`{{.Team}}` is no longer mapped. Review requests will be sent to the members of the team again.",
+		}, map[string]interface{}{"Team": team})
+	default:
+		return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
+	}
+}
+
+func (p *Plugin) handleAdminUserMap(_ *plugin.Context, args *model.CommandArgs, parameters []string) string {
+	l := p.getUserLocalizer(args.UserId)
+	if len(parameters) == 0 {
+		return p.localize(l, &i18n.Message{ID: "command.admin.usermap.invalid", Other: "Invalid admin usermap command. Available commands are 'list', 'add', 'delete', 'import' and 'match-emails'."})
+	}
+
+	command := parameters[0]
+	switch command {
+	case "list":
+		mappings, err := p.getUserMappings()
+		if err != nil {
+			p.client.Log.Warn("Failed to get user mappings", "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.list.error", Other: "Encountered an error getting the user mappings."})
+		}
+
+		if len(mappings) == 0 {
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.list.empty", Other: "No GitHub users are mapped."})
+		}
+
+		logins := make([]string, 0, len(mappings))
+		for login := range mappings {
+			logins = append(logins, login)
+		}
+		sort.Strings(logins)
+
+		txt := "### " + p.localize(l, &i18n.Message{ID: "command.admin.usermap.list.title", Other: "GitHub user mappings"}) + "\n"
+		for _, login := range logins {
+			userID := mappings[login]
+			name := userID
+			if user, err := p.client.User.Get(userID); err == nil {
+				name = "@" + user.Username
+			}
+			txt += fmt.Sprintf("* `%s`: %s\n", login, name)
+		}
+
+		return txt
+	case "add":
+		if len(parameters) < 3 || normalizeGitHubLogin(parameters[1]) == "" {
+			return p.localize(l, &i18n.Message{
+				ID:    "command.admin.usermap.add.missingArguments",
+				Other: "Please specify the GitHub user and the Mattermost user, e.g. `/github admin usermap add octocat @jane`.",
+			})
+		}
+
+		result, err := p.ImportUserMappings([]*UserMappingRow{{Login: normalizeGitHubLogin(parameters[1]), User: parameters[2]}})
+		if err != nil {
+			p.client.Log.Warn("Failed to store user mapping", "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.add.error", Other: "Encountered an error mapping the user."})
+		}
+
+		if result.Mapped == 0 {
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.add.unknownUser", Other: "Unable to find the Mattermost user. Please specify it by username or email address."})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.admin.usermap.add.success",
+			Other: "Mentions of `{{.Login}}` will be shown as {{.User}}.",
+		}, map[string]interface{}{"Login": normalizeGitHubLogin(parameters[1]), "User": parameters[2]})
+	case "delete":
+		if len(parameters) < 2 || normalizeGitHubLogin(parameters[1]) == "" {
+			return p.localize(l, &i18n.Message{
+				ID:    "command.admin.usermap.delete.missingLogin",
+				Other: "Please specify the GitHub user, e.g. `/github admin usermap delete octocat`.",
+			})
+		}
+		login := normalizeGitHubLogin(parameters[1])
+
+		removed := false
+		err := p.updateUserMappings(func(mappings map[string]string) {
+			_, removed = mappings[login]
+			delete(mappings, login)
+		})
+		if err != nil {
+			p.client.Log.Warn("Failed to delete user mapping", "login", login, "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.delete.error", Other: "Encountered an error deleting the user mapping."})
+		}
+
+		if !removed {
+			return p.localizeWithData(l, &i18n.Message{ID: "command.admin.usermap.delete.notMapped", Other: "`{{.Login}}` is not mapped."}, map[string]interface{}{"Login": login})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{ID: "command.admin.usermap.delete.success", Other: "`{{.Login}}` is no longer mapped."}, map[string]interface{}{"Login": login})
+	case "import":
+		rows, err := parseUserMappingRows([]byte(parseRawArgument(args.Command, 4)))
+		if err != nil {
+			return p.localizeWithData(l, &i18n.Message{
+				ID:    "command.admin.usermap.import.invalid",
+				Other: "Unable to parse the user mappings: {{.Error}}\nPlease add one `github-login,mattermost-username-or-email` line per user below `/github admin usermap import`.",
+			}, map[string]interface{}{"Error": err.Error()})
+		}
+
+		result, err := p.ImportUserMappings(rows)
+		if err != nil {
+			p.client.Log.Warn("Failed to import user mappings", "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.import.error", Other: "Encountered an error importing the user mappings."})
+		}
+
+		txt := p.localizePlural(l, &i18n.Message{
+			ID:    "command.admin.usermap.import.success",
+			One:   "Mapped {{.Count}} GitHub user.",
+			Other: "Mapped {{.Count}} GitHub users.",
+		}, result.Mapped, nil)
+		if len(result.Unresolved) > 0 {
+			txt += "\n" + p.localize(l, &i18n.Message{ID: "command.admin.usermap.import.unresolved", Other: "The following Mattermost users were not found:"}) + "\n"
+			txt += describeUserMappingRows(result.Unresolved)
+		}
+
+		return txt
+	case "match-emails":
+		org := p.getConfiguration().GitHubOrg
+		if len(parameters) > 1 {
+			org = parameters[1]
+		}
+		if org == "" {
+			return p.localize(l, &i18n.Message{
+				ID:    "command.admin.usermap.matchEmails.missingOrg",
+				Other: "Please specify the GitHub organization, e.g. `/github admin usermap match-emails my-org`.",
+			})
+		}
+
+		info, apiErr := p.getGitHubUserInfo(args.UserId)
+		if apiErr != nil {
+			return p.localize(l, &i18n.Message{
+				ID:    "command.admin.usermap.matchEmails.notConnected",
+				Other: "You must connect your GitHub account as an owner of the organization to match its members by email address.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), teamMembersTimeout)
+		defer cancel()
+
+		members, err := p.graphQLConnect(info).GetOrganizationMembers(ctx, org)
+		if err != nil {
+			p.client.Log.Warn("Failed to get organization members", "org", org, "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.matchEmails.fetchError", Other: "Encountered an error getting the members of the organization."})
+		}
+
+		matched, err := p.MatchUserMappingsByEmail(members)
+		if err != nil {
+			p.client.Log.Warn("Failed to store user mappings", "error", err.Error())
+			return p.localize(l, &i18n.Message{ID: "command.admin.usermap.matchEmails.error", Other: "Encountered an error storing the user mappings."})
+		}
+
+		return p.localizeWithData(l, &i18n.Message{
+			ID:    "command.admin.usermap.matchEmails.success",
+			Other: "Matched {{.Count}} of {{.Members}} members of {{.Org}} by email address.",
+		}, map[string]interface{}{"Count": matched, "Members": len(members), "Org": org})
+	default:
+		return p.localizeWithData(l, unknownSubcommandMessage, map[string]interface{}{"Command": command})
+	}
+}
+
+// describeTeamMapping returns the ~channel or @group a GitHub team is mapped to.
+func (p *Plugin) describeTeamMapping(mapping *TeamMapping) string {
+	if mapping.GroupID != "" {
+		if group, err := p.client.Group.Get(mapping.GroupID); err == nil && group.Name != nil {
+			return "@" + *group.Name
+		}
+		return mapping.GroupID
+	}
+
+	if channel, err := p.client.Channel.Get(mapping.ChannelID); err == nil {
+		return "~" + channel.Name
+	}
+	return mapping.ChannelID
+}
+
+// parseTemplateArgument returns the raw template following `/github admin template set <name>`
+// in the given command, preserving its line breaks. The template may be wrapped in a code block.
+func parseTemplateArgument(command string) string {
+	return parseRawArgument(command, 5)
+}
+
+// parseRawArgument returns the raw argument following the first words of the given command,
+// preserving its line breaks. The argument may be wrapped in a code block.
+func parseRawArgument(command string, words int) string {
+	source := command
+	for i := 0; i < words; i++ {
+		source = strings.TrimLeftFunc(source, unicode.IsSpace)
+		end := strings.IndexFunc(source, unicode.IsSpace)
+		if end == -1 {
+			return ""
+		}
+		source = source[end:]
+	}
+
+	source = strings.TrimLeft(source, " \t")
+	trimmed := strings.TrimSpace(source)
+	if strings.HasPrefix(trimmed, "```") && strings.HasSuffix(trimmed, "```") {
+		// Drop the opening line, including an optional language, and the closing fence.
+		newline := strings.Index(trimmed, "\n")
+		if newline == -1 {
+			return ""
+		}
+		return strings.TrimSuffix(strings.TrimSuffix(trimmed[newline+1:], "```"), "\n")
+	}
+
+	if trimmed == "" {
+		return ""
+	}
+
+	return source
+}
+
 type CommandHandleFunc func(c *plugin.Context, args *model.CommandArgs, parameters []string, userInfo *GitHubUserInfo) string
 
 func (p *Plugin) isAuthorizedSysAdmin(userID string) (bool, error) {
@@ -564,40 +1427,58 @@ func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*mo
 	}
 
 	config := p.getConfiguration()
+	l := p.getUserLocalizer(args.UserId)
 
 	if validationErr := config.IsValid(); validationErr != nil {
 		isSysAdmin, err := p.isAuthorizedSysAdmin(args.UserId)
 		var text string
 		switch {
 		case err != nil:
-			text = "Error checking user's permissions"
-			p.client.Log.Warn(text, "error", err.Error())
+			text = p.localize(l, permissionsCheckErrorMessage)
+			p.client.Log.Warn("Error checking user's permissions", "error", err.Error())
 		case isSysAdmin:
-			text = fmt.Sprintf("Before using this plugin, you'll need to configure it by running `/github setup`: %s", validationErr.Error())
+			text = p.localizeWithData(l, &i18n.Message{
+				ID:    "command.notConfigured.admin",
+				Other: "Before using this plugin, you'll need to configure it by running `/github setup`: {{.Error}}",
+			}, map[string]interface{}{"Error": validationErr.Error()})
 		default:
-			text = "Please contact your system administrator to correctly configure the GitHub plugin."
+			text = p.localize(l, &i18n.Message{ID: "command.notConfigured", Other: "Please contact your system administrator to correctly configure the GitHub plugin."})
 		}
 
 		p.postCommandResponse(args, text)
 		return &model.CommandResponse{}, nil
 	}
 
+	if action == "admin" {
+		message := p.handleAdmin(c, args, parameters)
+		if message != "" {
+			p.postCommandResponse(args, message)
+		}
+		return &model.CommandResponse{}, nil
+	}
+
 	if action == "connect" {
 		siteURL := p.client.Configuration.GetConfig().ServiceSettings.SiteURL
 		if siteURL == nil {
-			p.postCommandResponse(args, "Encountered an error connecting to GitHub.")
+			p.postCommandResponse(args, p.localize(l, &i18n.Message{ID: "command.connect.error", Other: "Encountered an error connecting to GitHub."}))
 			return &model.CommandResponse{}, nil
 		}
 
 		privateAllowed := p.getConfiguration().ConnectToPrivateByDefault
 		if len(parameters) > 0 {
 			if privateAllowed {
-				p.postCommandResponse(args, fmt.Sprintf("Unknown command `%v`. Do you meant `/github connect`?", args.Command))
+				p.postCommandResponse(args, p.localizeWithData(l, &i18n.Message{
+					ID:    "command.connect.unknown",
+					Other: "Unknown command `{{.Command}}`. Do you meant `/github connect`?",
+				}, map[string]interface{}{"Command": args.Command}))
 				return &model.CommandResponse{}, nil
 			}
 
 			if len(parameters) != 1 || parameters[0] != "private" {
-				p.postCommandResponse(args, fmt.Sprintf("Unknown command `%v`. Do you meant `/github connect private`?", args.Command))
+				p.postCommandResponse(args, p.localizeWithData(l, &i18n.Message{
+					ID:    "command.connect.unknownPrivate",
+					Other: "Unknown command `{{.Command}}`. Do you meant `/github connect private`?",
+				}, map[string]interface{}{"Command": args.Command}))
 				return &model.CommandResponse{}, nil
 			}
 
@@ -607,22 +1488,28 @@ func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*mo
 		qparams := ""
 		if privateAllowed {
 			if !p.getConfiguration().EnablePrivateRepo {
-				p.postCommandResponse(args, "Private repositories are disabled. Please ask a System Admin to enabled them.")
+				p.postCommandResponse(args, p.localize(l, &i18n.Message{ID: "command.connect.privateDisabled", Other: "Private repositories are disabled. Please ask a System Admin to enabled them."}))
 				return &model.CommandResponse{}, nil
 			}
 			qparams = "?private=true"
 		}
 
-		msg := fmt.Sprintf("[Click here to link your GitHub account.](%s/plugins/%s/oauth/connect%s)", *siteURL, Manifest.Id, qparams)
+		msg := p.localizeWithData(l, &i18n.Message{
+			ID:    "command.connect.link",
+			Other: "[Click here to link your GitHub account.]({{.URL}})",
+		}, map[string]interface{}{"URL": fmt.Sprintf("%s/plugins/%s/oauth/connect%s", *siteURL, Manifest.Id, qparams)})
 		p.postCommandResponse(args, msg)
 		return &model.CommandResponse{}, nil
 	}
 
 	info, apiErr := p.getGitHubUserInfo(args.UserId)
 	if apiErr != nil {
-		text := "Unknown error."
+		text := p.localize(l, &i18n.Message{ID: "command.unknownError", Other: "Unknown error."})
 		if apiErr.ID == apiErrorIDNotConnected {
-			text = "You must connect your account to GitHub first. Either click on the GitHub logo in the bottom left of the screen or enter `/github connect`."
+			text = p.localize(l, &i18n.Message{
+				ID:    "command.notConnected",
+				Other: "You must connect your account to GitHub first. Either click on the GitHub logo in the bottom left of the screen or enter `/github connect`.",
+			})
 		}
 		p.postCommandResponse(args, text)
 		return &model.CommandResponse{}, nil
@@ -636,7 +1523,7 @@ func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*mo
 		return &model.CommandResponse{}, nil
 	}
 
-	p.postCommandResponse(args, fmt.Sprintf("Unknown action %v", action))
+	p.postCommandResponse(args, p.localizeWithData(l, &i18n.Message{ID: "command.unknownAction", Other: "Unknown action {{.Action}}"}, map[string]interface{}{"Action": action}))
 	return &model.CommandResponse{}, nil
 }
 
@@ -673,14 +1560,14 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 	todo := model.NewAutocompleteData("todo", "", "Get a list of unread messages and pull requests awaiting your review")
 	github.AddCommand(todo)
 
-	subscriptions := model.NewAutocompleteData("subscriptions", "[command]", "Available commands: list, add, delete")
+	subscriptions := model.NewAutocompleteData("subscriptions", "[command]", "Available commands: list, add, delete, export, import")
 
 	subscribeList := model.NewAutocompleteData("list", "", "List the current channel subscriptions")
 	subscriptions.AddCommand(subscribeList)
 
 	subscriptionsAdd := model.NewAutocompleteData("add", "[owner/repo] [features] [flags]", "Subscribe the current channel to receive notifications about opened pull requests and issues for an organization or repository. [features] and [flags] are optional arguments")
 	subscriptionsAdd.AddTextArgument("Owner/repo to subscribe to", "[owner/repo]", "")
-	subscriptionsAdd.AddNamedTextArgument("features", "Comma-delimited list of one or more of: issues, pulls, pulls_merged, pushes, creates, deletes, issue_creations, issue_comments, pull_reviews, label:\"<labelname>\". Defaults to pulls,issues,creates,deletes", "", `/[^,-\s]+(,[^,-\s]+)*/`, false)
+	subscriptionsAdd.AddNamedTextArgument("features", "Comma-delimited list of one or more of: issues, pulls, pulls_merged, pushes, creates, deletes, issue_creations, issue_comments, pull_reviews, discussions, security, deployments, milestones, projects, repo_admin, label:\"<labelname>\". Defaults to pulls,issues,creates,deletes", "", `/[^,-\s]+(,[^,-\s]+)*/`, false)
 
 	if config.GitHubOrg != "" {
 		subscriptionsAdd.AddNamedStaticListArgument("exclude-org-member", "Events triggered by organization members will not be delivered (the organization config should be set, otherwise this flag has not effect)", false, []model.AutocompleteListItem{
@@ -695,6 +1582,41 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 		})
 	}
 
+	subscriptionsAdd.AddNamedTextArgument("rate-limit", "Maximum number of notifications posted per minute. Further events are summarized", "[number]", "", false)
+	subscriptionsAdd.AddNamedTextArgument("quiet-hours", "Hold back notifications during these hours and summarize them afterwards", "[HH:MM-HH:MM]", "", false)
+	subscriptionsAdd.AddNamedTextArgument("timezone", "Timezone of the quiet hours, defaults to UTC", "[timezone]", "", false)
+	subscriptionsAdd.AddNamedTextArgument("locale", "Language of the notifications, defaults to the server language", "[locale]", "", false)
+	subscriptionsAdd.AddNamedStaticListArgument("push-detail", "Include the stats of each commit in push notifications", false, []model.AutocompleteListItem{
+		{
+			Item:     "true",
+			HelpText: "List files changed, additions and deletions of each commit",
+		},
+		{
+			Item:     "false",
+			HelpText: "List the commit messages only",
+		},
+	})
+	subscriptionsAdd.AddNamedStaticListArgument("severity", "Minimum severity of the security alerts to deliver", false, []model.AutocompleteListItem{
+		{
+			Item:     "low",
+			HelpText: "Deliver all security alerts",
+		},
+		{
+			Item:     "medium",
+			HelpText: "Deliver security alerts of medium or higher severity",
+		},
+		{
+			Item:     "high",
+			HelpText: "Deliver security alerts of high or critical severity",
+		},
+		{
+			Item:     "critical",
+			HelpText: "Deliver critical security alerts only",
+		},
+	})
+	subscriptionsAdd.AddNamedTextArgument("environments", "Only deliver deployments to these environments", "[production,staging]", "", false)
+	subscriptionsAdd.AddNamedTextArgument("topic", "Only deliver events from repositories with one of these topics. Requires an organization or a repository pattern. Replaces the topics of an existing subscription", "[team-payments,team-search]", "", false)
+
 	subscriptionsAdd.AddNamedStaticListArgument("render-style", "Determine the rendering style of various notifications.", false, []model.AutocompleteListItem{
 		{
 			Item:     "default",
@@ -708,6 +1630,10 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 			Item:     "collapsed",
 			HelpText: "Notifications come in a one-line format, without enlarged fonts or advanced layouts.",
 		},
+		{
+			Item:     "attachment",
+			HelpText: "Notifications come as message attachments with a colored sidebar and fields for labels, assignees and reviewers.",
+		},
 	})
 
 	subscriptions.AddCommand(subscriptionsAdd)
@@ -715,6 +1641,23 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 	subscriptionsDelete.AddTextArgument("Owner/repo to unsubscribe from", "[owner/repo]", "")
 	subscriptions.AddCommand(subscriptionsDelete)
 
+	subscriptionsExport := model.NewAutocompleteData("export", "[flags]", "Export the subscriptions of all channels as YAML or JSON. System Admins only")
+	addAdminSubscriptionFilterArguments(subscriptionsExport)
+	subscriptionsExport.AddNamedStaticListArgument("format", "Format of the export", false, []model.AutocompleteListItem{
+		{
+			Item:     subscriptionsConfigFormatYAML,
+			HelpText: "Export as YAML (default)",
+		},
+		{
+			Item:     subscriptionsConfigFormatJSON,
+			HelpText: "Export as JSON",
+		},
+	})
+	subscriptions.AddCommand(subscriptionsExport)
+
+	subscriptionsImport := model.NewAutocompleteData("import", "", "Import a subscription configuration. System Admins only")
+	subscriptions.AddCommand(subscriptionsImport)
+
 	github.AddCommand(subscriptions)
 
 	issue := model.NewAutocompleteData("issue", "[command]", "Available commands: create")
@@ -728,7 +1671,7 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 	me := model.NewAutocompleteData("me", "", "Display the connected GitHub account")
 	github.AddCommand(me)
 
-	mute := model.NewAutocompleteData("mute", "[command]", "Available commands: list, add, delete, delete-all")
+	mute := model.NewAutocompleteData("mute", "[command]", "Available commands: list, add, repo, label, delete, delete-repo, delete-label, delete-all")
 
 	muteAdd := model.NewAutocompleteData("add", "[github username]", "Mute notifications from the provided GitHub user")
 	muteAdd.AddTextArgument("GitHub user to mute", "[username]", "")
@@ -738,17 +1681,33 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 	muteDelete.AddTextArgument("GitHub user to unmute", "[username]", "")
 	mute.AddCommand(muteDelete)
 
+	muteRepo := model.NewAutocompleteData("repo", "[owner/repo]", "Mute notifications about the provided repository")
+	muteRepo.AddTextArgument("Repository to mute", "[owner/repo]", "")
+	mute.AddCommand(muteRepo)
+
+	muteLabel := model.NewAutocompleteData("label", "[label]", "Mute notifications about pull requests and issues with the provided label")
+	muteLabel.AddTextArgument("Label to mute", "[label]", "")
+	mute.AddCommand(muteLabel)
+
+	muteDeleteRepo := model.NewAutocompleteData("delete-repo", "[owner/repo]", "Unmute notifications about the provided repository")
+	muteDeleteRepo.AddTextArgument("Repository to unmute", "[owner/repo]", "")
+	mute.AddCommand(muteDeleteRepo)
+
+	muteDeleteLabel := model.NewAutocompleteData("delete-label", "[label]", "Unmute notifications about the provided label")
+	muteDeleteLabel.AddTextArgument("Label to unmute", "[label]", "")
+	mute.AddCommand(muteDeleteLabel)
+
 	github.AddCommand(mute)
 
-	muteDeleteAll := model.NewAutocompleteData("delete-all", "", "Unmute all muted GitHub users")
+	muteDeleteAll := model.NewAutocompleteData("delete-all", "", "Unmute all muted GitHub users, repositories and labels")
 	mute.AddCommand(muteDeleteAll)
 
-	muteList := model.NewAutocompleteData("list", "", "List muted GitHub users")
+	muteList := model.NewAutocompleteData("list", "", "List muted GitHub users, repositories and labels")
 	mute.AddCommand(muteList)
 
 	settings := model.NewAutocompleteData("settings", "[setting] [value]", "Update your user settings")
 
-	settingNotifications := model.NewAutocompleteData("notifications", "", "Turn notifications on/off")
+	settingNotifications := model.NewAutocompleteData("notifications", "[type] [value]", "Turn notifications on/off")
 	settingValue := []model.AutocompleteListItem{{
 		HelpText: "Turn notifications on",
 		Item:     "on",
@@ -756,7 +1715,24 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 		HelpText: "Turn notifications off",
 		Item:     "off",
 	}}
-	settingNotifications.AddStaticListArgument("", true, settingValue)
+	notificationTypeHelpTexts := map[string]string{
+		notificationTypeReviewRequests: "Review requests for you",
+		notificationTypeMentions:       "Mentions of you",
+		notificationTypeComments:       "Comments on your pull requests and issues, and the ones assigned to you",
+		notificationTypeAssignments:    "Pull requests and issues assigned to you",
+		notificationTypeReviewResults:  "Reviews of your pull requests",
+		notificationTypeCIFailures:     "Failed and passed checks of your pull requests",
+		notificationTypeReadyToMerge:   "Your pull requests that are ready to merge",
+	}
+	// A command can't have both arguments and subcommands, so on and off are offered as subcommands.
+	for _, item := range settingValue {
+		settingNotifications.AddCommand(model.NewAutocompleteData(item.Item, "", item.HelpText))
+	}
+	for _, notificationType := range notificationTypes {
+		settingNotificationType := model.NewAutocompleteData(notificationType, "[value]", "Turn notifications about "+strings.ToLower(notificationTypeHelpTexts[notificationType])+" on/off")
+		settingNotificationType.AddStaticListArgument("", true, settingValue)
+		settingNotifications.AddCommand(settingNotificationType)
+	}
 	settings.AddCommand(settingNotifications)
 
 	remainderNotifications := model.NewAutocompleteData("reminders", "", "Turn notifications on/off")
@@ -775,6 +1751,96 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 
 	github.AddCommand(settings)
 
+	admin := model.NewAutocompleteData("admin", "[command]", "Available commands: subscriptions, template, teammap, usermap")
+	admin.RoleID = model.SystemAdminRoleId
+
+	adminSubscriptions := model.NewAutocompleteData("subscriptions", "[command]", "Available commands: list, delete, export")
+
+	adminSubscriptionsList := model.NewAutocompleteData("list", "[flags]", "List the subscriptions of all channels")
+	addAdminSubscriptionFilterArguments(adminSubscriptionsList)
+	adminSubscriptions.AddCommand(adminSubscriptionsList)
+
+	adminSubscriptionsDelete := model.NewAutocompleteData("delete", "[flags]", "Delete all subscriptions matching the given repository or team")
+	addAdminSubscriptionFilterArguments(adminSubscriptionsDelete)
+	adminSubscriptions.AddCommand(adminSubscriptionsDelete)
+
+	adminSubscriptionsExport := model.NewAutocompleteData("export", "[flags]", "Export the subscriptions of all channels")
+	addAdminSubscriptionFilterArguments(adminSubscriptionsExport)
+	adminSubscriptionsExport.AddNamedStaticListArgument("format", "Format of the export", false, []model.AutocompleteListItem{
+		{
+			Item:     "json",
+			HelpText: "Export as JSON (default)",
+		},
+		{
+			Item:     "csv",
+			HelpText: "Export as CSV",
+		},
+	})
+	adminSubscriptions.AddCommand(adminSubscriptionsExport)
+
+	admin.AddCommand(adminSubscriptions)
+
+	adminTemplate := model.NewAutocompleteData("template", "[command]", "Available commands: list, get, set, reset")
+
+	var templateNames []model.AutocompleteListItem
+	for _, name := range getCustomizableTemplateNames() {
+		templateNames = append(templateNames, model.AutocompleteListItem{Item: name})
+	}
+
+	adminTemplate.AddCommand(model.NewAutocompleteData("list", "", "List the notification templates"))
+
+	adminTemplateGet := model.NewAutocompleteData("get", "[name]", "Show a notification template")
+	adminTemplateGet.AddStaticListArgument("Name of the template", true, templateNames)
+	adminTemplate.AddCommand(adminTemplateGet)
+
+	adminTemplateSet := model.NewAutocompleteData("set", "[name] [template]", "Override a notification template")
+	adminTemplateSet.AddStaticListArgument("Name of the template", true, templateNames)
+	adminTemplateSet.AddTextArgument("Go template to use instead of the built-in one", "[template]", "")
+	adminTemplate.AddCommand(adminTemplateSet)
+
+	adminTemplateReset := model.NewAutocompleteData("reset", "[name]", "Restore the built-in notification template")
+	adminTemplateReset.AddStaticListArgument("Name of the template", true, templateNames)
+	adminTemplate.AddCommand(adminTemplateReset)
+
+	admin.AddCommand(adminTemplate)
+
+	adminTeamMap := model.NewAutocompleteData("teammap", "[command]", "Available commands: list, add, delete")
+	adminTeamMap.AddCommand(model.NewAutocompleteData("list", "", "List the GitHub teams mapped to channels and user groups"))
+
+	adminTeamMapAdd := model.NewAutocompleteData("add", "[org/team] [~channel|@group]", "Send mentions of and review requests for a GitHub team to a channel or user group")
+	adminTeamMapAdd.AddTextArgument("GitHub team, e.g. my-org/my-team", "[org/team]", "")
+	adminTeamMapAdd.AddTextArgument("Channel or user group, defaults to the current channel", "[~channel|@group]", "")
+	adminTeamMap.AddCommand(adminTeamMapAdd)
+
+	adminTeamMapDelete := model.NewAutocompleteData("delete", "[org/team]", "Remove the channel or user group mapped to a GitHub team")
+	adminTeamMapDelete.AddTextArgument("GitHub team, e.g. my-org/my-team", "[org/team]", "")
+	adminTeamMap.AddCommand(adminTeamMapDelete)
+
+	admin.AddCommand(adminTeamMap)
+
+	adminUserMap := model.NewAutocompleteData("usermap", "[command]", "Available commands: list, add, delete, import, match-emails")
+	adminUserMap.AddCommand(model.NewAutocompleteData("list", "", "List the GitHub users mapped to Mattermost users"))
+
+	adminUserMapAdd := model.NewAutocompleteData("add", "[github-login] [@username|email]", "Show mentions of a GitHub user as a Mattermost user")
+	adminUserMapAdd.AddTextArgument("GitHub login, e.g. octocat", "[github-login]", "")
+	adminUserMapAdd.AddTextArgument("Mattermost username or email address", "[@username|email]", "")
+	adminUserMap.AddCommand(adminUserMapAdd)
+
+	adminUserMapDelete := model.NewAutocompleteData("delete", "[github-login]", "Remove the Mattermost user mapped to a GitHub user")
+	adminUserMapDelete.AddTextArgument("GitHub login, e.g. octocat", "[github-login]", "")
+	adminUserMap.AddCommand(adminUserMapDelete)
+
+	adminUserMapImport := model.NewAutocompleteData("import", "[csv]", "Map GitHub users to Mattermost users in bulk")
+	adminUserMapImport.AddTextArgument("One github-login,mattermost-username-or-email line per user", "[csv]", "")
+	adminUserMap.AddCommand(adminUserMapImport)
+
+	adminUserMapMatchEmails := model.NewAutocompleteData("match-emails", "[org]", "Map the members of a GitHub organization to the Mattermost users with the same email address")
+	adminUserMapMatchEmails.AddTextArgument("GitHub organization, defaults to the configured one", "[org]", "")
+	adminUserMap.AddCommand(adminUserMapMatchEmails)
+
+	admin.AddCommand(adminUserMap)
+	github.AddCommand(admin)
+
 	setup := model.NewAutocompleteData("setup", "[command]", "Available commands: oauth, webhook, announcement")
 	setup.RoleID = model.SystemAdminRoleId
 	setup.AddCommand(model.NewAutocompleteData("oauth", "", "Set up the OAuth2 Application in GitHub"))
@@ -791,6 +1857,11 @@ func getAutocompleteData(config *Configuration) *model.AutocompleteData {
 	return github
 }
 
+func addAdminSubscriptionFilterArguments(data *model.AutocompleteData) {
+	data.AddNamedTextArgument("repo", "Only include subscriptions of this owner or owner/repo", "[owner/repo]", "", false)
+	data.AddNamedTextArgument("team", "Only include subscriptions of channels in this team", "[team name]", "", false)
+}
+
 // parseCommand parses the entire command input string and retrieves the command, action and parameters
 func parseCommand(input string) (command, action string, parameters []string) {
 	split := make([]string, 0)
@@ -197,3 +197,46 @@ func TestParseCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTemplateArgument(t *testing.T) {
+	tcs := []struct {
+		Command  string
+		Expected string
+	}{
+		{Command: "/github admin template set newPR", Expected: ""},
+		{Command: "/github admin template set newPR   ", Expected: ""},
+		{Command: "/github admin template set newPR {{.Event.GetAction}}", Expected: "{{.Event.GetAction}}"},
+		{Command: "/github  admin template set newPR New PR:\n{{.Event.GetPullRequest.GetTitle}}", Expected: "New PR:\n{{.Event.GetPullRequest.GetTitle}}"},
+		{Command: "/github admin template set newPR ```\n{{if .Event}}\n  yes\n{{end}}\n```", Expected: "{{if .Event}}\n  yes\n{{end}}"},
+		{Command: "/github admin template set newPR\n```gotemplate\n{{.Event}}\n```", Expected: "{{.Event}}"},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.Expected, parseTemplateArgument(tc.Command), tc.Command)
+	}
+}
+
+func TestGetAutocompleteData(t *testing.T) {
+	for _, config := range []*Configuration{{}, {GitHubOrg: "mattermost", EnablePrivateRepo: true}} {
+		assert.NoError(t, getAutocompleteData(config).IsValid())
+	}
+}
+
+func TestUserSettingsNotificationTypes(t *testing.T) {
+	settings := &UserSettings{Notifications: true}
+	for _, notificationType := range notificationTypes {
+		assert.True(t, settings.IsNotificationEnabled(notificationType), notificationType)
+	}
+
+	settings.SetNotificationEnabled(notificationTypeMentions, false)
+	settings.SetNotificationEnabled(notificationTypeCIFailures, false)
+	settings.SetNotificationEnabled(notificationTypeMentions, false)
+	assert.Equal(t, []string{notificationTypeCIFailures, notificationTypeMentions}, settings.DisabledNotifications)
+	assert.False(t, settings.IsNotificationEnabled(notificationTypeMentions))
+	assert.True(t, settings.IsNotificationEnabled(notificationTypeComments))
+
+	settings.SetNotificationEnabled(notificationTypeMentions, true)
+	settings.SetNotificationEnabled(notificationTypeCIFailures, true)
+	assert.Nil(t, settings.DisabledNotifications)
+	assert.True(t, settings.IsNotificationEnabled(notificationTypeMentions))
+}
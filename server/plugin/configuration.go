@@ -36,6 +36,9 @@ type Configuration struct {
 	EnterpriseBaseURL           string `json:"enterprisebaseurl"`
 	EnterpriseUploadURL         string `json:"enterpriseuploadurl"`
 	EnableCodePreview           string `json:"enablecodepreview"`
+	CodePreviewMaxLines         int    `json:"codepreviewmaxlines"`
+	CodePreviewLineContext      int    `json:"codepreviewlinecontext"`
+	NotificationTemplates       string `json:"notificationtemplates"`
 	EnableWebhookEventLogging   bool   `json:"enablewebhookeventlogging"`
 	UsePreregisteredApplication bool   `json:"usepreregisteredapplication"`
 }
@@ -93,6 +96,24 @@ func (c *Configuration) getBaseURL() string {
 	return "https://github.com/"
 }
 
+// getCodePreviewMaxLines returns the maximum number of lines of a code preview.
+func (c *Configuration) getCodePreviewMaxLines() int {
+	if c.CodePreviewMaxLines <= 0 {
+		return maxPreviewLines
+	}
+
+	return c.CodePreviewMaxLines
+}
+
+// getCodePreviewLineContext returns the number of lines shown around a single linked line.
+func (c *Configuration) getCodePreviewLineContext() int {
+	if c.CodePreviewLineContext <= 0 {
+		return permalinkLineContext
+	}
+
+	return c.CodePreviewLineContext
+}
+
 func (c *Configuration) sanitize() {
 	c.EnterpriseBaseURL = strings.TrimRight(c.EnterpriseBaseURL, "/")
 	c.EnterpriseUploadURL = strings.TrimRight(c.EnterpriseUploadURL, "/")
@@ -209,6 +230,8 @@ func (p *Plugin) OnConfigurationChange() error {
 
 	p.setConfiguration(configuration)
 
+	p.loadCustomTemplates(configuration)
+
 	command, err := p.getCommand(configuration)
 	if err != nil {
 		return errors.Wrap(err, "failed to get command")
@@ -0,0 +1,682 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+
+	"github.com/mattermost/mattermost-plugin-github/server/plugin/graphql"
+)
+
+var (
+	customTemplatesLock sync.RWMutex
+	// customTemplates is a clone of masterTemplate with the admin overrides applied. It is nil
+	// if no template has been customized.
+	customTemplates       *template.Template
+	customTemplateSources map[string]string
+	// localizedTemplates are clones of masterTemplate with the translations of a locale and the
+	// admin overrides applied, keyed by locale.
+	localizedTemplates        map[string]*template.Template
+	localizedTemplatesMatcher language.Matcher
+	localizedTemplatesLocales []string
+	// templateTranslations are the translated template sources, keyed by locale and template name.
+	templateTranslations map[string]map[string]string
+
+	templateRenderErrorCallback func(name string, err error)
+)
+
+// nonCustomizableTemplates are the templates of masterTemplate that are not notifications.
+var nonCustomizableTemplates = map[string]bool{
+	"master":   true,
+	"helpText": true,
+}
+
+func registerTemplateRenderErrorCallback(callback func(name string, err error)) {
+	templateRenderErrorCallback = callback
+}
+
+// getCustomizableTemplateNames returns the sorted names of all templates that can be overridden.
+func getCustomizableTemplateNames() []string {
+	var names []string
+	for _, t := range masterTemplate.Templates() {
+		if nonCustomizableTemplates[t.Name()] {
+			continue
+		}
+		names = append(names, t.Name())
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func isCustomizableTemplate(name string) bool {
+	return !nonCustomizableTemplates[name] && masterTemplate.Lookup(name) != nil
+}
+
+// isTranslatableTemplate reports whether name is a template that can be translated. Unlike admin
+// overrides, translations may also cover the help text.
+func isTranslatableTemplate(name string) bool {
+	return name != "master" && masterTemplate.Lookup(name) != nil
+}
+
+// getBuiltInTemplateSource returns the source of the built-in template with the given name.
+func getBuiltInTemplateSource(name string) (string, error) {
+	t := masterTemplate.Lookup(name)
+	if t == nil || t.Tree == nil {
+		return "", errors.Errorf("no template named %s", name)
+	}
+
+	return t.Tree.Root.String(), nil
+}
+
+// getCustomTemplate returns the customized template with the given name, or nil if the
+// built-in one should be used.
+func getCustomTemplate(name string) *template.Template {
+	customTemplatesLock.RLock()
+	defer customTemplatesLock.RUnlock()
+
+	if customTemplates == nil {
+		return nil
+	}
+
+	return customTemplates.Lookup(name)
+}
+
+// getCustomTemplateSources returns a copy of the currently applied template overrides.
+func getCustomTemplateSources() map[string]string {
+	customTemplatesLock.RLock()
+	defer customTemplatesLock.RUnlock()
+
+	sources := make(map[string]string, len(customTemplateSources))
+	for name, source := range customTemplateSources {
+		sources[name] = source
+	}
+
+	return sources
+}
+
+func setCustomTemplates(t *template.Template, sources map[string]string, localized map[string]*template.Template) {
+	customTemplatesLock.Lock()
+	defer customTemplatesLock.Unlock()
+
+	if len(sources) == 0 {
+		t = nil
+	}
+
+	customTemplates = t
+	customTemplateSources = sources
+
+	// English is the first locale, so that it is chosen if no translation matches.
+	locales := []string{defaultLocale}
+	tags := []language.Tag{language.English}
+	for locale := range localized {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales[1:])
+	for _, locale := range locales[1:] {
+		tags = append(tags, language.Make(locale))
+	}
+
+	localizedTemplates = localized
+	localizedTemplatesLocales = locales
+	localizedTemplatesMatcher = language.NewMatcher(tags)
+}
+
+func setTemplateTranslations(translations map[string]map[string]string) {
+	customTemplatesLock.Lock()
+	defer customTemplatesLock.Unlock()
+
+	templateTranslations = translations
+}
+
+func getTemplateTranslations() map[string]map[string]string {
+	customTemplatesLock.RLock()
+	defer customTemplatesLock.RUnlock()
+
+	return templateTranslations
+}
+
+// getLocalizedTemplate returns the template with the given name translated to locale, or nil if
+// there is no translation for locale.
+func getLocalizedTemplate(locale, name string) *template.Template {
+	customTemplatesLock.RLock()
+	defer customTemplatesLock.RUnlock()
+
+	if len(localizedTemplates) == 0 {
+		return nil
+	}
+
+	_, index, confidence := localizedTemplatesMatcher.Match(language.Make(locale))
+	if confidence == language.No || index == 0 {
+		return nil
+	}
+
+	t := localizedTemplates[localizedTemplatesLocales[index]]
+	if t == nil {
+		return nil
+	}
+
+	return t.Lookup(name)
+}
+
+// renderLocalizedTemplate renders the template with the given name in the language of locale.
+// If the template is not translated, or the translation fails to render, the template is rendered
+// like renderTemplate does.
+func renderLocalizedTemplate(locale, name string, data interface{}) (string, error) {
+	if localized := getLocalizedTemplate(locale, name); localized != nil {
+		var output bytes.Buffer
+		err := localized.Execute(&output, data)
+		if err == nil {
+			return output.String(), nil
+		}
+
+		if templateRenderErrorCallback != nil {
+			templateRenderErrorCallback(name, err)
+		}
+	}
+
+	return renderTemplate(name, data)
+}
+
+// parseCustomTemplates applies the given overrides to a clone of masterTemplate. Every override
+// has access to the same functions and partial templates as the built-in ones. The result is
+// rendered against sample events of every notification to catch errors before they reach a channel.
+func parseCustomTemplates(overrides map[string]string) (*template.Template, error) {
+	return parseTemplates(overrides, isCustomizableTemplate)
+}
+
+// parseTemplates applies sources to a clone of masterTemplate, accepting only the template names
+// for which isAllowed returns true, and test-renders the result.
+func parseTemplates(sources map[string]string, isAllowed func(name string) bool) (*template.Template, error) {
+	custom, err := masterTemplate.Clone()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to clone templates")
+	}
+
+	for _, name := range sortedKeys(sources) {
+		if !isAllowed(name) {
+			return nil, errors.Errorf("unknown template %s", name)
+		}
+
+		if _, err = custom.New(name).Parse(sources[name]); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse template %s", name)
+		}
+	}
+
+	// Render the samples without looking up the GitHub users in them.
+	validation, err := custom.Clone()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to clone templates")
+	}
+	validation.Funcs(template.FuncMap{
+		"lookupMattermostUsername":  func(string) string { return "" },
+		"replaceAllGitHubUsernames": func(body string) string { return body },
+	})
+
+	fixtures := getTemplateFixtures()
+	for _, name := range sortedKeys(fixtures) {
+		for _, data := range fixtures[name] {
+			if err = validation.ExecuteTemplate(&bytes.Buffer{}, name, data); err != nil {
+				return nil, errors.Wrapf(err, "failed to render template %s with a sample event", name)
+			}
+		}
+	}
+
+	return custom, nil
+}
+
+// parseValidTemplates is like parseTemplates, but skips the sources that fail to parse or render
+// instead of failing. It returns the accepted sources, and the errors of the skipped ones by name.
+func parseValidTemplates(sources map[string]string, isAllowed func(name string) bool) (*template.Template, map[string]string, map[string]error) {
+	if t, err := parseTemplates(sources, isAllowed); err == nil {
+		return t, sources, nil
+	}
+
+	var valid *template.Template
+	accepted := map[string]string{}
+	errs := map[string]error{}
+	for _, name := range sortedKeys(sources) {
+		accepted[name] = sources[name]
+
+		t, err := parseTemplates(accepted, isAllowed)
+		if err != nil {
+			errs[name] = err
+			delete(accepted, name)
+			continue
+		}
+
+		valid = t
+	}
+
+	return valid, accepted, errs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// parseNotificationTemplatesSetting decodes the NotificationTemplates setting, a JSON object
+// mapping template names to their source.
+func parseNotificationTemplatesSetting(setting string) (map[string]string, error) {
+	overrides := map[string]string{}
+	if strings.TrimSpace(setting) == "" {
+		return overrides, nil
+	}
+
+	if err := json.Unmarshal([]byte(setting), &overrides); err != nil {
+		return nil, errors.Wrap(err, "notification templates must be a JSON object of template names to templates")
+	}
+
+	return overrides, nil
+}
+
+// loadCustomTemplates applies the template overrides of the given configuration, and the
+// template translations. Templates that fail to parse or render are logged and skipped, so that
+// the built-in template is used instead.
+func (p *Plugin) loadCustomTemplates(config *Configuration) {
+	overrides, err := parseNotificationTemplatesSetting(config.NotificationTemplates)
+	if err != nil {
+		p.client.Log.Warn("Failed to load custom notification templates", "error", err.Error())
+		overrides = map[string]string{}
+	}
+
+	custom, accepted, errs := parseValidTemplates(overrides, isCustomizableTemplate)
+	for _, name := range sortedKeys(errs) {
+		p.client.Log.Warn("Skipping invalid custom notification template", "template", name, "error", errs[name].Error())
+	}
+
+	localized := map[string]*template.Template{}
+	for locale, translations := range getTemplateTranslations() {
+		sources := map[string]string{}
+		for name, source := range translations {
+			sources[name] = source
+		}
+		// Admin overrides take precedence over translations.
+		for name, source := range accepted {
+			sources[name] = source
+		}
+
+		t, _, errs := parseValidTemplates(sources, isTranslatableTemplate)
+		for _, name := range sortedKeys(errs) {
+			p.client.Log.Warn("Skipping invalid translated notification template", "locale", locale, "template", name, "error", errs[name].Error())
+		}
+
+		if t != nil {
+			localized[locale] = t
+		}
+	}
+
+	setCustomTemplates(custom, accepted, localized)
+}
+
+// saveCustomTemplate validates and stores the override of a template in the plugin configuration.
+// An empty source removes the override.
+func (p *Plugin) saveCustomTemplate(name, source string) error {
+	if !isCustomizableTemplate(name) {
+		return errors.Errorf("unknown template %s", name)
+	}
+
+	config := p.getConfiguration().Clone()
+
+	overrides, err := parseNotificationTemplatesSetting(config.NotificationTemplates)
+	if err != nil {
+		return err
+	}
+
+	if source == "" {
+		delete(overrides, name)
+	} else {
+		overrides[name] = source
+		if _, err = parseCustomTemplates(overrides); err != nil {
+			return err
+		}
+	}
+
+	config.NotificationTemplates = ""
+	if len(overrides) > 0 {
+		setting, err := json.MarshalIndent(overrides, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode notification templates")
+		}
+		config.NotificationTemplates = string(setting)
+	}
+
+	configMap, err := config.ToMap()
+	if err != nil {
+		return err
+	}
+
+	if err = p.client.Configuration.SavePluginConfig(configMap); err != nil {
+		return errors.Wrap(err, "failed to save plugin config")
+	}
+
+	return nil
+}
+
+// getTemplateFixtures returns sample data for every notification template, keyed by template name.
+func getTemplateFixtures() map[string][]interface{} {
+	fixtureRepo := &github.Repository{
+		FullName: github.String("mattermost/mattermost-plugin-github"),
+		HTMLURL:  github.String("https://github.com/mattermost/mattermost-plugin-github"),
+	}
+	sender := &github.User{
+		Login:   github.String("octocat"),
+		HTMLURL: github.String("https://github.com/octocat"),
+	}
+	labels := []*github.Label{{Name: github.String("Help Wanted")}}
+	createdAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	pr := &github.PullRequest{
+		Number:    github.Int(42),
+		Title:     github.String("Leverage git-get-head"),
+		Body:      github.String("Fixes a bug, cc @octocat"),
+		HTMLURL:   github.String("https://github.com/mattermost/mattermost-plugin-github/pull/42"),
+		Labels:    labels,
+		Assignees: []*github.User{sender},
+		Merged:    github.Bool(true),
+		CreatedAt: &createdAt,
+	}
+	issue := &github.Issue{
+		Number:    github.Int(1),
+		Title:     github.String("Implement git-get-head"),
+		Body:      github.String("git-get-head should be implemented, cc @octocat"),
+		HTMLURL:   github.String("https://github.com/mattermost/mattermost-plugin-github/issues/1"),
+		Labels:    labels,
+		Assignees: []*github.User{sender},
+		CreatedAt: &createdAt,
+	}
+	label := &github.Label{Name: github.String("Help Wanted")}
+
+	prEvent := &github.PullRequestEvent{Action: github.String("opened"), Repo: fixtureRepo, PullRequest: pr, Sender: sender, Label: label}
+	teamReviewRequestEvent := &github.PullRequestEvent{
+		Action:        github.String("review_requested"),
+		Repo:          fixtureRepo,
+		PullRequest:   pr,
+		Sender:        sender,
+		RequestedTeam: &github.Team{Name: github.String("Core"), Slug: github.String("core")},
+	}
+	issuesEvent := &github.IssuesEvent{Action: github.String("opened"), Repo: fixtureRepo, Issue: issue, Sender: sender, Label: label}
+	pullComment := &github.IssueComment{
+		Body:    github.String("LGTM @octocat"),
+		HTMLURL: github.String("https://github.com/mattermost/mattermost-plugin-github/pull/42#issuecomment-1"),
+	}
+	issueCommentEvent := &github.IssueCommentEvent{Action: github.String("created"), Repo: fixtureRepo, Issue: issue, Comment: pullComment, Sender: sender}
+	reviewEvent := &github.PullRequestReviewEvent{
+		Action:      github.String("submitted"),
+		Repo:        fixtureRepo,
+		PullRequest: pr,
+		Sender:      sender,
+		Review: &github.PullRequestReview{
+			State:   github.String("approved"),
+			Body:    github.String("Looks good"),
+			HTMLURL: github.String("https://github.com/mattermost/mattermost-plugin-github/pull/42#pullrequestreview-1"),
+		},
+	}
+	reviewCommentEvent := &github.PullRequestReviewCommentEvent{
+		Action:      github.String("created"),
+		Repo:        fixtureRepo,
+		PullRequest: pr,
+		Sender:      sender,
+		Comment: &github.PullRequestComment{
+			Body:     github.String("Nit: rename this"),
+			DiffHunk: github.String("@@ -1 +1 @@"),
+			HTMLURL:  github.String("https://github.com/mattermost/mattermost-plugin-github/pull/42#discussion_r1"),
+		},
+	}
+	pushEvent := &github.PushEvent{
+		Ref:     github.String("refs/heads/master"),
+		Compare: github.String("https://github.com/mattermost/mattermost-plugin-github/compare/a...b"),
+		Repo: &github.PushEventRepository{
+			FullName: fixtureRepo.FullName,
+			HTMLURL:  fixtureRepo.HTMLURL,
+		},
+		Sender: sender,
+		Commits: []*github.HeadCommit{{
+			ID:        github.String("a10867b14bb761a232cd80139fbd4c0d33264240"),
+			URL:       github.String("https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240"),
+			Message:   github.String("Fix the build"),
+			Committer: &github.CommitAuthor{Name: github.String("The Octocat")},
+		}},
+	}
+	pushDetail := &PushDetail{
+		Event:         pushEvent,
+		Commits:       []*PushCommitDetail{{HeadCommit: pushEvent.Commits[0], HasStats: true, FilesChanged: 2, Additions: 10, Deletions: 3}},
+		TotalCommits:  1,
+		DefaultBranch: true,
+		HasStats:      true,
+		FilesChanged:  2,
+		Additions:     10,
+		Deletions:     3,
+	}
+	forcedPushEvent := *pushEvent
+	forcedPushEvent.Forced = github.Bool(true)
+	truncatedPushDetail := &PushDetail{
+		Event:           &forcedPushEvent,
+		Commits:         []*PushCommitDetail{{HeadCommit: pushEvent.Commits[0]}},
+		TotalCommits:    25,
+		MoreCommits:     24,
+		ProtectedBranch: true,
+	}
+	createEvent := &github.CreateEvent{Ref: github.String("feature"), RefType: github.String("branch"), Repo: fixtureRepo, Sender: sender}
+	deleteEvent := &github.DeleteEvent{Ref: github.String("feature"), RefType: github.String("branch"), Repo: fixtureRepo, Sender: sender}
+	starEvent := &github.StarEvent{Action: github.String("created"), Repo: fixtureRepo, Sender: sender}
+	discussion := &Discussion{
+		Number:   github.Int(7),
+		Title:    github.String("How do I subscribe to discussions?"),
+		Body:     github.String("Is there a feature for it, cc @octocat"),
+		HTMLURL:  github.String("https://github.com/mattermost/mattermost-plugin-github/discussions/7"),
+		User:     sender,
+		Category: &DiscussionCategory{Name: github.String("Q&A"), Emoji: github.String(":pray:"), IsAnswerable: github.Bool(true)},
+		Labels:   labels,
+	}
+	discussionComment := &DiscussionComment{
+		Body:    github.String("Use the discussions feature @octocat"),
+		HTMLURL: github.String("https://github.com/mattermost/mattermost-plugin-github/discussions/7#discussioncomment-1"),
+		User:    sender,
+	}
+	discussionEvent := &DiscussionEvent{Action: github.String("created"), Repo: fixtureRepo, Discussion: discussion, Sender: sender}
+	discussionAnsweredEvent := &DiscussionEvent{Action: github.String("answered"), Repo: fixtureRepo, Discussion: discussion, Answer: discussionComment, Sender: sender}
+	discussionCommentEvent := &DiscussionCommentEvent{Action: github.String("created"), Repo: fixtureRepo, Discussion: discussion, Comment: discussionComment, Sender: sender}
+	dependabotAlertEvent := &DependabotAlertEvent{
+		Action: github.String("created"),
+		Alert: DependabotAlert{
+			Number:     3,
+			HTMLURL:    "https://github.com/mattermost/mattermost-plugin-github/security/dependabot/3",
+			Dependency: DependabotDependency{Package: DependabotPackage{Ecosystem: "npm", Name: "lodash"}, ManifestPath: "webapp/package-lock.json"},
+			SecurityAdvisory: SecurityAdvisory{
+				GHSAID:   "GHSA-35jh-r3h4-6jhm",
+				Summary:  "Command Injection in lodash",
+				Severity: "high",
+			},
+			SecurityVulnerability: SecurityVulnerability{VulnerableVersionRange: "< 4.17.21", FirstPatchedVersion: FirstPatchedVersion{Identifier: "4.17.21"}},
+		},
+		Repo:   fixtureRepo,
+		Sender: sender,
+	}
+	codeScanningAlertEvent := &CodeScanningAlertEvent{
+		Action: github.String("closed_by_user"),
+		Alert: CodeScanningAlert{
+			Number:             5,
+			HTMLURL:            "https://github.com/mattermost/mattermost-plugin-github/security/code-scanning/5",
+			Rule:               CodeScanningRule{ID: "go/sql-injection", Description: "Database query built from user-controlled sources", Severity: "error", SecuritySeverityLevel: "high"},
+			Tool:               CodeScanningTool{Name: "CodeQL"},
+			MostRecentInstance: CodeScanningAlertInstance{Ref: "refs/heads/master", Location: CodeScanningLocation{Path: "server/plugin/api.go", StartLine: 42}},
+		},
+		Repo:   fixtureRepo,
+		Sender: sender,
+	}
+	secretScanningAlertEvent := &SecretScanningAlertEvent{
+		Action: github.String("resolved"),
+		Alert: SecretScanningAlert{
+			Number:                2,
+			HTMLURL:               "https://github.com/mattermost/mattermost-plugin-github/security/secret-scanning/2",
+			SecretType:            "github_personal_access_token",
+			SecretTypeDisplayName: "GitHub Personal Access Token",
+			Resolution:            "revoked",
+		},
+		Repo:   fixtureRepo,
+		Sender: sender,
+	}
+	deployment := &github.Deployment{
+		ID:          github.Int64(11),
+		SHA:         github.String("a10867b14bb761a232cd80139fbd4c0d33264240"),
+		Ref:         github.String("v1.2.0"),
+		Environment: github.String("production"),
+		Description: github.String("Release 1.2.0"),
+		Creator:     sender,
+	}
+	deploymentEvent := &github.DeploymentEvent{Deployment: deployment, Repo: fixtureRepo, Sender: sender}
+	deploymentStatusEvent := func(state string) *github.DeploymentStatusEvent {
+		return &github.DeploymentStatusEvent{
+			Deployment: deployment,
+			DeploymentStatus: &github.DeploymentStatus{
+				State:          github.String(state),
+				Environment:    github.String("production"),
+				Description:    github.String("Deployment " + state),
+				LogURL:         github.String("https://github.com/mattermost/mattermost-plugin-github/actions/runs/1"),
+				EnvironmentURL: github.String("https://example.com"),
+			},
+			Repo:   fixtureRepo,
+			Sender: sender,
+		}
+	}
+	milestoneDueOn := time.Date(2022, time.March, 1, 8, 0, 0, 0, time.UTC)
+	previousMilestoneDueOn := time.Date(2022, time.February, 15, 8, 0, 0, 0, time.UTC)
+	milestone := &github.Milestone{
+		Title:        github.String("v1.2.0"),
+		HTMLURL:      github.String("https://github.com/mattermost/mattermost-plugin-github/milestone/4"),
+		OpenIssues:   github.Int(3),
+		ClosedIssues: github.Int(9),
+		DueOn:        &milestoneDueOn,
+	}
+	milestoneEvent := func(action string, changes *MilestoneChanges) *MilestoneEvent {
+		return &MilestoneEvent{Action: github.String(action), Milestone: milestone, Changes: changes, Repo: fixtureRepo, Sender: sender}
+	}
+	previousDueOn := &MilestoneChanges{DueOn: &MilestoneDueOnChange{From: &previousMilestoneDueOn}}
+	projectItemEvent := &ProjectsV2ItemEvent{Action: github.String("edited"), Sender: sender}
+	projectItem := &graphql.ProjectItem{
+		ProjectTitle: "Roadmap",
+		ProjectURL:   "https://github.com/orgs/mattermost/projects/1",
+		ContentType:  "Issue",
+		Title:        pr.GetTitle(),
+		URL:          "https://github.com/mattermost/mattermost-plugin-github/issues/42",
+		Number:       42,
+		Repository:   fixtureRepo.GetFullName(),
+	}
+	draftProjectItem := &graphql.ProjectItem{ProjectTitle: "Roadmap", ContentType: "DraftIssue", Title: "Plan the release"}
+	projectItemMoves := []interface{}{
+		&ProjectItemMove{Event: projectItemEvent, Item: projectItem, Field: "Status", From: "Todo", To: "In Progress"},
+		&ProjectItemMove{Event: projectItemEvent, Item: draftProjectItem, Field: "Priority"},
+	}
+	repositoryEvent := func(action string) *github.RepositoryEvent {
+		return &github.RepositoryEvent{Action: github.String(action), Repo: fixtureRepo, Sender: sender}
+	}
+	memberEvent := func(action string, permission *MemberPermissionChange) *MemberEvent {
+		return &MemberEvent{Action: github.String(action), Member: sender, Changes: &MemberChanges{Permission: permission}, Repo: fixtureRepo, Sender: sender}
+	}
+	branchProtectionRule := &github.BranchProtectionRule{
+		Name:                                 github.String("master"),
+		PullRequestReviewsEnforcementLevel:   github.String("everyone"),
+		RequiredApprovingReviewCount:         github.Int(2),
+		RequiredStatusChecks:                 []string{"build", "test"},
+		RequiredStatusChecksEnforcementLevel: github.String("everyone"),
+		AdminEnforced:                        github.Bool(true),
+	}
+	branchProtectionRuleEvent := func(action string) *github.BranchProtectionRuleEvent {
+		return &github.BranchProtectionRuleEvent{Action: github.String(action), Rule: branchProtectionRule, Repo: fixtureRepo, Sender: sender}
+	}
+	forkee := &github.Repository{FullName: github.String("octocat/mattermost-plugin-github"), HTMLURL: github.String("https://github.com/octocat/mattermost-plugin-github")}
+	ciPassed := &CIStatusNotification{
+		Repository: fixtureRepo.GetFullName(),
+		Number:     pr.GetNumber(),
+		Title:      pr.GetTitle(),
+		URL:        pr.GetHTMLURL(),
+		SHA:        "a10867b14bb761a232cd80139fbd4c0d33264240",
+		State:      ciStateSuccess,
+	}
+	ciFailed := *ciPassed
+	ciFailed.State = ciStateFailure
+	ciFailed.FailedCheck = &CICheck{Name: "build", URL: "https://github.com/mattermost/mattermost-plugin-github/runs/1"}
+
+	withStyles := func(event interface{}) []interface{} {
+		var fixtures []interface{}
+		for _, style := range []string{"", "collapsed", "skip-body"} {
+			fixtures = append(fixtures, &EventWithRenderConfig{Event: event, Config: RenderConfig{Style: style}})
+		}
+		return fixtures
+	}
+
+	return map[string][]interface{}{
+		"newDraftPR":                             withStyles(prEvent),
+		"newPR":                                  withStyles(prEvent),
+		"markedReadyToReviewPR":                  withStyles(prEvent),
+		"closedPR":                               {prEvent},
+		"pullRequestLabelled":                    {prEvent},
+		"pullRequestMentionNotification":         {prEvent},
+		"pullRequestNotification":                {prEvent, teamReviewRequestEvent},
+		"teamReviewRequest":                      {teamReviewRequestEvent},
+		"pullRequestTeamMentionNotification":     {&PullRequestTeamMention{Team: "mattermost/core", PullRequestEvent: prEvent}},
+		"commentTeamMentionNotification":         {&CommentTeamMention{Team: "mattermost/core", IssueCommentEvent: issueCommentEvent}},
+		"newIssue":                               withStyles(issuesEvent),
+		"closedIssue":                            withStyles(issuesEvent),
+		"issueLabelled":                          withStyles(issuesEvent),
+		"reopenedIssue":                          withStyles(issuesEvent),
+		"issueNotification":                      {issuesEvent},
+		"pushedCommits":                          {pushEvent},
+		"pushedCommitsDetail":                    {pushDetail, truncatedPushDetail},
+		"newCreateMessage":                       {createEvent},
+		"newDeleteMessage":                       {deleteEvent},
+		"issueComment":                           {issueCommentEvent},
+		"commentMentionNotification":             {issueCommentEvent},
+		"commentAuthorPullRequestNotification":   {issueCommentEvent},
+		"commentAuthorIssueNotification":         {issueCommentEvent},
+		"commentAssigneePullRequestNotification": {issueCommentEvent},
+		"commentAssigneeIssueNotification":       {issueCommentEvent},
+		"commentAssigneeSelfMentionPullRequestNotification": {issueCommentEvent},
+		"commentAssigneeSelfMentionIssueNotification":       {issueCommentEvent},
+		"pullRequestReviewEvent":                            {reviewEvent},
+		"pullRequestReviewNotification":                     {reviewEvent},
+		"newReviewComment":                                  {reviewCommentEvent},
+		"newRepoStar":                                       {starEvent},
+		"newDiscussion":                                     withStyles(discussionEvent),
+		"discussionAnswered":                                {discussionAnsweredEvent},
+		"discussionComment":                                 {discussionCommentEvent},
+		"discussionCommentMentionNotification":              {discussionCommentEvent},
+		"discussionCommentTeamMentionNotification":          {&DiscussionCommentTeamMention{Team: "mattermost/core", DiscussionCommentEvent: discussionCommentEvent}},
+		"dependabotAlert":                                   {dependabotAlertEvent},
+		"codeScanningAlert":                                 {codeScanningAlertEvent},
+		"secretScanningAlert":                               {secretScanningAlertEvent},
+		"newDeployment":                                     {deploymentEvent},
+		"deploymentPending":                                 {deploymentStatusEvent("queued"), deploymentStatusEvent("pending")},
+		"deploymentInProgress":                              {deploymentStatusEvent("in_progress")},
+		"deploymentSuccess":                                 {deploymentStatusEvent("success")},
+		"deploymentFailure":                                 {deploymentStatusEvent("failure"), deploymentStatusEvent("error")},
+		"deploymentInactive":                                {deploymentStatusEvent("inactive")},
+		"newMilestone":                                      {milestoneEvent("created", nil)},
+		"closedMilestone":                                   {milestoneEvent("closed", nil)},
+		"milestoneDueDateChanged":                           {milestoneEvent("edited", previousDueOn), milestoneEvent("edited", &MilestoneChanges{DueOn: &MilestoneDueOnChange{}})},
+		"projectItemMoved":                                  projectItemMoves,
+		"repositoryChanged":                                 {repositoryEvent("created"), repositoryEvent("archived"), repositoryEvent("privatized"), repositoryEvent("transferred")},
+		"repositoryMemberChanged":                           {memberEvent("added", &MemberPermissionChange{To: github.String("write")}), memberEvent("edited", &MemberPermissionChange{From: github.String("write"), To: github.String("admin")}), memberEvent("removed", nil)},
+		"branchProtectionRuleChanged":                       {branchProtectionRuleEvent("created"), branchProtectionRuleEvent("edited"), branchProtectionRuleEvent("deleted")},
+		"repositoryPublicized":                              {&github.PublicEvent{Repo: fixtureRepo, Sender: sender}},
+		"repositoryForked":                                  {&github.ForkEvent{Forkee: forkee, Repo: fixtureRepo, Sender: sender}},
+		"ciStatusNotification":                              {ciPassed, &ciFailed},
+		"readyToMergeNotification":                          {&ReadyToMergeNotification{Repository: ciPassed.Repository, Number: ciPassed.Number, Title: ciPassed.Title, URL: ciPassed.URL}},
+		"helpText":                                          {&Configuration{}, &Configuration{EnablePrivateRepo: true}},
+	}
+}
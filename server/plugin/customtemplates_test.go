@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCustomTemplates(t *testing.T) {
+	t.Run("overrides a template", func(t *testing.T) {
+		custom, err := parseCustomTemplates(map[string]string{
+			"newCreateMessage": `{{.GetRefType}} {{.GetRef}} was created in {{template "repo" .GetRepo}}`,
+		})
+		require.NoError(t, err)
+
+		event := &github.CreateEvent{
+			Ref:     sToP("feature"),
+			RefType: sToP("branch"),
+			Repo:    &repo,
+		}
+		var output bytes.Buffer
+		require.NoError(t, custom.ExecuteTemplate(&output, "newCreateMessage", event))
+		assert.Equal(t, "branch feature was created in [\\[mattermost-plugin-github\\]](https://github.com/mattermost/mattermost-plugin-github)", output.String())
+	})
+
+	t.Run("overriding a partial template affects other templates", func(t *testing.T) {
+		custom, err := parseCustomTemplates(map[string]string{
+			"repo": `{{.GetFullName}}`,
+		})
+		require.NoError(t, err)
+
+		event := &github.DeleteEvent{
+			Ref:     sToP("feature"),
+			RefType: sToP("branch"),
+			Repo:    &repo,
+			Sender:  &user,
+		}
+		var output bytes.Buffer
+		require.NoError(t, custom.ExecuteTemplate(&output, "newDeleteMessage", event))
+		assert.Equal(t, "\nmattermost-plugin-github branch feature deleted by [panda](https://github.com/panda)\n", output.String())
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		_, err := parseCustomTemplates(map[string]string{"unknown": "text"})
+		assert.EqualError(t, err, "unknown template unknown")
+	})
+
+	t.Run("help text cannot be customized", func(t *testing.T) {
+		_, err := parseCustomTemplates(map[string]string{"helpText": "text"})
+		assert.Error(t, err)
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		_, err := parseCustomTemplates(map[string]string{"newPR": "{{if}}"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse template newPR")
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		_, err := parseCustomTemplates(map[string]string{"newPR": "{{.Event | unknownFunction}}"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse template newPR")
+	})
+
+	t.Run("render error", func(t *testing.T) {
+		_, err := parseCustomTemplates(map[string]string{"newPR": "{{.Event.GetPullRequest.UnknownField}}"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to render template newPR with a sample event")
+	})
+}
+
+func TestGetTemplateFixtures(t *testing.T) {
+	fixtures := getTemplateFixtures()
+
+	// Partial templates are rendered as part of the notification templates using them.
+	partials := map[string]bool{
+		"user": true, "repo": true, "pullRequest": true, "issue": true,
+		"discussion": true, "deployment": true, "milestone": true, "milestoneProgress": true, "labels": true, "assignee": true,
+		"eventRepoPullRequest": true, "eventRepoPullRequestWithTitle": true,
+		"reviewRepoPullRequest": true, "reviewRepoPullRequestWithTitle": true,
+		"eventRepoIssue": true, "eventRepoIssueWithTitle": true,
+		"eventRepoIssueFullLink": true, "eventRepoIssueFullLinkWithTitle": true,
+	}
+	for _, name := range getCustomizableTemplateNames() {
+		if partials[name] {
+			continue
+		}
+
+		assert.Contains(t, fixtures, name, "template %s has no sample event", name)
+	}
+
+	for name, data := range fixtures {
+		for _, d := range data {
+			_, err := renderTemplate(name, d)
+			assert.NoError(t, err, name)
+		}
+	}
+}
+
+func TestRenderTemplateWithCustomTemplates(t *testing.T) {
+	t.Cleanup(func() {
+		setCustomTemplates(nil, nil, nil)
+		registerTemplateRenderErrorCallback(nil)
+	})
+
+	event := &github.StarEvent{
+		Action: sToP("created"),
+		Repo:   &repo,
+		Sender: &user,
+	}
+
+	overrides := map[string]string{
+		"newRepoStar": `{{.GetSender.GetLogin}} starred {{.GetRepo.GetFullName}}{{if .GetSender.GetName}} ({{index .GetRepo.Topics 0}}){{end}}`,
+	}
+	custom, err := parseCustomTemplates(overrides)
+	require.NoError(t, err)
+	setCustomTemplates(custom, overrides, nil)
+
+	var renderErrors []string
+	registerTemplateRenderErrorCallback(func(name string, err error) {
+		renderErrors = append(renderErrors, name)
+	})
+
+	actual, err := renderTemplate("newRepoStar", event)
+	require.NoError(t, err)
+	assert.Equal(t, "panda starred mattermost-plugin-github", actual)
+	assert.Empty(t, renderErrors)
+
+	// The customized template fails for senders with a name, as the repository has no topics.
+	named := *event
+	named.Sender = &github.User{Login: sToP("panda"), Name: sToP("Panda"), HTMLURL: sToP("https://github.com/panda")}
+	actual, err = renderTemplate("newRepoStar", &named)
+	require.NoError(t, err)
+	assert.Equal(t, "\n[\\[mattermost-plugin-github\\]](https://github.com/mattermost/mattermost-plugin-github) starred by [panda](https://github.com/panda)\nIt now has **1** stars.", actual)
+	assert.Equal(t, []string{"newRepoStar"}, renderErrors)
+
+	setCustomTemplates(nil, nil, nil)
+	actual, err = renderTemplate("newRepoStar", event)
+	require.NoError(t, err)
+	assert.Equal(t, "\n[\\[mattermost-plugin-github\\]](https://github.com/mattermost/mattermost-plugin-github) starred by [panda](https://github.com/panda)\nIt now has **1** stars.", actual)
+}
+
+func TestPlugin_LoadCustomTemplates(t *testing.T) {
+	t.Cleanup(func() {
+		setCustomTemplates(nil, nil, nil)
+	})
+
+	api := &plugintest.API{}
+	api.On("LogWarn", "Skipping invalid custom notification template", "template", "newPR", "error", mock.AnythingOfType("string"))
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	p.loadCustomTemplates(&Configuration{
+		NotificationTemplates: `{"newPR": "{{if}}", "closedIssue": "closed {{.Event.GetIssue.GetNumber}}"}`,
+	})
+
+	assert.Equal(t, map[string]string{"closedIssue": "closed {{.Event.GetIssue.GetNumber}}"}, getCustomTemplateSources())
+	api.AssertExpectations(t)
+
+	actual, err := renderTemplate("closedIssue", GetEventWithRenderConfig(&github.IssuesEvent{Issue: &github.Issue{Number: iToP(7)}}, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "closed 7", actual)
+
+	p.loadCustomTemplates(&Configuration{})
+	assert.Nil(t, getCustomTemplate("closedIssue"))
+	assert.Empty(t, getCustomTemplateSources())
+}
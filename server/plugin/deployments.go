@@ -0,0 +1,161 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	deploymentPostKeyPrefix = "_deploy_"
+	// deploymentPostTTL is how long status updates of a deployment are threaded under its post.
+	deploymentPostTTL = 30 * 24 * time.Hour
+)
+
+// deploymentStatusTemplates maps the states of a deployment to the template announcing them.
+var deploymentStatusTemplates = map[string]string{
+	"queued":      "deploymentPending",
+	"pending":     "deploymentPending",
+	"in_progress": "deploymentInProgress",
+	"success":     "deploymentSuccess",
+	"failure":     "deploymentFailure",
+	"error":       "deploymentFailure",
+	"inactive":    "deploymentInactive",
+}
+
+// normalizeEnvironments returns the comma-delimited list of environments in lowercase, without
+// blanks and empty entries.
+func normalizeEnvironments(value string) (string, error) {
+	var environments []string
+	for _, environment := range strings.Split(value, ",") {
+		environment = strings.ToLower(strings.TrimSpace(environment))
+		if environment == "" || containsValue(environments, environment) {
+			continue
+		}
+		environments = append(environments, environment)
+	}
+
+	if len(environments) == 0 {
+		return "", errors.New("no environments given")
+	}
+
+	return strings.Join(environments, ","), nil
+}
+
+// matchesEnvironment returns true if events of the given deployment environment are delivered to
+// the subscription. Subscriptions without an environment filter get the events of all of them.
+func (s *Subscription) matchesEnvironment(environment string) bool {
+	if s.Environments() == "" {
+		return true
+	}
+
+	return containsValue(strings.Split(s.Environments(), ","), strings.ToLower(environment))
+}
+
+func deploymentPostKey(channelID string, deploymentID int64) string {
+	hash := sha256.Sum256([]byte(channelID + "/" + strconv.FormatInt(deploymentID, 10)))
+	return deploymentPostKeyPrefix + hex.EncodeToString(hash[:16])
+}
+
+// getDeploymentStatusEnvironment returns the environment of a deployment status, which older
+// events only set on the deployment.
+func getDeploymentStatusEnvironment(event *github.DeploymentStatusEvent) string {
+	if environment := event.GetDeploymentStatus().GetEnvironment(); environment != "" {
+		return environment
+	}
+
+	return event.GetDeployment().GetEnvironment()
+}
+
+func (p *Plugin) postDeploymentEvent(event *github.DeploymentEvent) {
+	deployment := event.GetDeployment()
+
+	subs := p.GetSubscribedChannelsForRepository(event.GetRepo())
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Deployments() || !sub.matchesEnvironment(deployment.GetEnvironment()) {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "newDeployment", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_deployment",
+			Message:   message,
+			ChannelId: sub.ChannelID,
+		}
+
+		postID := p.createSubscriptionPost(sub, post, event)
+		if postID == "" {
+			continue
+		}
+
+		// Remember the post, so that status updates of the deployment are threaded under it.
+		key := deploymentPostKey(sub.ChannelID, deployment.GetID())
+		if _, err := p.client.KV.Set(key, postID, pluginapi.SetExpiry(deploymentPostTTL)); err != nil {
+			p.client.Log.Warn("Failed to store deployment post", "channel_id", sub.ChannelID, "error", err.Error())
+		}
+	}
+}
+
+func (p *Plugin) postDeploymentStatusEvent(event *github.DeploymentStatusEvent) {
+	statusTemplate, ok := deploymentStatusTemplates[event.GetDeploymentStatus().GetState()]
+	if !ok {
+		return
+	}
+
+	subs := p.GetSubscribedChannelsForRepository(event.GetRepo())
+	if len(subs) == 0 {
+		return
+	}
+
+	environment := getDeploymentStatusEnvironment(event)
+	for _, sub := range subs {
+		if !sub.Deployments() || !sub.matchesEnvironment(environment) {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), statusTemplate, event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		var rootID string
+		if err := p.client.KV.Get(deploymentPostKey(sub.ChannelID, event.GetDeployment().GetID()), &rootID); err != nil {
+			p.client.Log.Warn("Failed to get deployment post", "channel_id", sub.ChannelID, "error", err.Error())
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_deployment_status",
+			Message:   message,
+			ChannelId: sub.ChannelID,
+			RootId:    rootID,
+		}
+		p.createSubscriptionPost(sub, post, event)
+	}
+}
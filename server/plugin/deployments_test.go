@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEnvironments(t *testing.T) {
+	environments, err := normalizeEnvironments(" Production, staging,,production ")
+	require.NoError(t, err)
+	assert.Equal(t, "production,staging", environments)
+
+	_, err = normalizeEnvironments(" , ")
+	assert.Error(t, err)
+}
+
+func TestSubscriptionMatchesEnvironment(t *testing.T) {
+	sub := &Subscription{}
+	assert.True(t, sub.matchesEnvironment("anything"))
+
+	sub.Flags.Environments = "production,staging"
+	assert.True(t, sub.matchesEnvironment("Production"))
+	assert.True(t, sub.matchesEnvironment("staging"))
+	assert.False(t, sub.matchesEnvironment("preview"))
+}
+
+func TestDeploymentThreading(t *testing.T) {
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/mattermost-plugin-github": {
+			{ChannelID: "channel1", Features: "deployments", Flags: SubscriptionFlags{Environments: "production"}, Repository: "mattermost/mattermost-plugin-github"},
+			{ChannelID: "channel2", Features: "deployments", Flags: SubscriptionFlags{Environments: "staging"}, Repository: "mattermost/mattermost-plugin-github"},
+		},
+	}}
+	key := deploymentPostKey("channel1", 11)
+	rootID, err := json.Marshal("channel1-post")
+	require.NoError(t, err)
+
+	p, api := setupSubscriptionTestPlugin(t, subs)
+	api.On("KVGet", key).Return(rootID, nil)
+	api.On("KVSetWithOptions", key, rootID, mock.Anything).Return(true, nil)
+
+	repo := &github.Repository{FullName: github.String("mattermost/mattermost-plugin-github")}
+	deployment := &github.Deployment{ID: github.Int64(11), Ref: github.String("v1.2.0"), Environment: github.String("production")}
+
+	p.postDeploymentEvent(&github.DeploymentEvent{Deployment: deployment, Repo: repo})
+
+	api.AssertCalled(t, "KVSetWithOptions", key, rootID, mock.Anything)
+
+	p.postDeploymentStatusEvent(&github.DeploymentStatusEvent{
+		Deployment:       deployment,
+		DeploymentStatus: &github.DeploymentStatus{State: github.String("success")},
+		Repo:             repo,
+	})
+
+	api.AssertNumberOfCalls(t, "CreatePost", 2)
+	api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "channel1" && post.Type == "custom_git_deployment_status" && post.RootId == "channel1-post"
+	}))
+}
@@ -0,0 +1,335 @@
+package plugin
+
+import (
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+const (
+	actionAnswered = "answered"
+)
+
+// DiscussionCategory is the category of a GitHub discussion.
+type DiscussionCategory struct {
+	Name         *string `json:"name,omitempty"`
+	Emoji        *string `json:"emoji,omitempty"`
+	IsAnswerable *bool   `json:"is_answerable,omitempty"`
+}
+
+// Discussion is a GitHub discussion, as sent in discussion webhook events.
+type Discussion struct {
+	Number   *int                `json:"number,omitempty"`
+	Title    *string             `json:"title,omitempty"`
+	Body     *string             `json:"body,omitempty"`
+	HTMLURL  *string             `json:"html_url,omitempty"`
+	State    *string             `json:"state,omitempty"`
+	User     *github.User        `json:"user,omitempty"`
+	Category *DiscussionCategory `json:"category,omitempty"`
+	Labels   []*github.Label     `json:"labels,omitempty"`
+}
+
+// DiscussionComment is a comment on a GitHub discussion, or a reply to such a comment.
+type DiscussionComment struct {
+	ID       *int64       `json:"id,omitempty"`
+	ParentID *int64       `json:"parent_id,omitempty"`
+	Body     *string      `json:"body,omitempty"`
+	HTMLURL  *string      `json:"html_url,omitempty"`
+	User     *github.User `json:"user,omitempty"`
+}
+
+// DiscussionEvent is triggered when a discussion is created, answered or otherwise changed.
+type DiscussionEvent struct {
+	Action     *string              `json:"action,omitempty"`
+	Discussion *Discussion          `json:"discussion,omitempty"`
+	Answer     *DiscussionComment   `json:"answer,omitempty"`
+	Repo       *github.Repository   `json:"repository,omitempty"`
+	Sender     *github.User         `json:"sender,omitempty"`
+	Org        *github.Organization `json:"organization,omitempty"`
+}
+
+// DiscussionCommentEvent is triggered when a comment on a discussion is created, edited or
+// deleted.
+type DiscussionCommentEvent struct {
+	Action     *string              `json:"action,omitempty"`
+	Discussion *Discussion          `json:"discussion,omitempty"`
+	Comment    *DiscussionComment   `json:"comment,omitempty"`
+	Repo       *github.Repository   `json:"repository,omitempty"`
+	Sender     *github.User         `json:"sender,omitempty"`
+	Org        *github.Organization `json:"organization,omitempty"`
+}
+
+func (c *DiscussionCategory) GetName() string {
+	if c == nil || c.Name == nil {
+		return ""
+	}
+	return *c.Name
+}
+
+func (c *DiscussionCategory) GetEmoji() string {
+	if c == nil || c.Emoji == nil {
+		return ""
+	}
+	return *c.Emoji
+}
+
+func (c *DiscussionCategory) GetIsAnswerable() bool {
+	if c == nil || c.IsAnswerable == nil {
+		return false
+	}
+	return *c.IsAnswerable
+}
+
+func (d *Discussion) GetNumber() int {
+	if d == nil || d.Number == nil {
+		return 0
+	}
+	return *d.Number
+}
+
+func (d *Discussion) GetTitle() string {
+	if d == nil || d.Title == nil {
+		return ""
+	}
+	return *d.Title
+}
+
+func (d *Discussion) GetBody() string {
+	if d == nil || d.Body == nil {
+		return ""
+	}
+	return *d.Body
+}
+
+func (d *Discussion) GetHTMLURL() string {
+	if d == nil || d.HTMLURL == nil {
+		return ""
+	}
+	return *d.HTMLURL
+}
+
+func (d *Discussion) GetState() string {
+	if d == nil || d.State == nil {
+		return ""
+	}
+	return *d.State
+}
+
+func (d *Discussion) GetUser() *github.User {
+	if d == nil {
+		return nil
+	}
+	return d.User
+}
+
+func (d *Discussion) GetCategory() *DiscussionCategory {
+	if d == nil {
+		return nil
+	}
+	return d.Category
+}
+
+func (c *DiscussionComment) GetID() int64 {
+	if c == nil || c.ID == nil {
+		return 0
+	}
+	return *c.ID
+}
+
+func (c *DiscussionComment) GetParentID() int64 {
+	if c == nil || c.ParentID == nil {
+		return 0
+	}
+	return *c.ParentID
+}
+
+func (c *DiscussionComment) GetBody() string {
+	if c == nil || c.Body == nil {
+		return ""
+	}
+	return *c.Body
+}
+
+func (c *DiscussionComment) GetHTMLURL() string {
+	if c == nil || c.HTMLURL == nil {
+		return ""
+	}
+	return *c.HTMLURL
+}
+
+func (c *DiscussionComment) GetUser() *github.User {
+	if c == nil {
+		return nil
+	}
+	return c.User
+}
+
+func (e *DiscussionEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *DiscussionEvent) GetDiscussion() *Discussion {
+	if e == nil {
+		return nil
+	}
+	return e.Discussion
+}
+
+func (e *DiscussionEvent) GetAnswer() *DiscussionComment {
+	if e == nil {
+		return nil
+	}
+	return e.Answer
+}
+
+func (e *DiscussionEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *DiscussionEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (e *DiscussionCommentEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *DiscussionCommentEvent) GetDiscussion() *Discussion {
+	if e == nil {
+		return nil
+	}
+	return e.Discussion
+}
+
+func (e *DiscussionCommentEvent) GetComment() *DiscussionComment {
+	if e == nil {
+		return nil
+	}
+	return e.Comment
+}
+
+func (e *DiscussionCommentEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *DiscussionCommentEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (p *Plugin) postDiscussionEvent(event *DiscussionEvent) {
+	repo := event.GetRepo()
+
+	var discussionTemplate string
+	switch event.GetAction() {
+	case actionCreated:
+		discussionTemplate = "newDiscussion"
+	case actionAnswered:
+		discussionTemplate = "discussionAnswered"
+	default:
+		return
+	}
+
+	subs := p.GetSubscribedChannelsForRepository(repo)
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Discussions() {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		var data interface{} = event
+		if discussionTemplate == "newDiscussion" {
+			data = GetEventWithRenderConfig(event, sub)
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), discussionTemplate, data)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_discussion",
+			Message:   sanitizeDescription(message),
+			ChannelId: sub.ChannelID,
+		}
+		p.createSubscriptionPost(sub, post, event)
+	}
+}
+
+func (p *Plugin) postDiscussionCommentEvent(event *DiscussionCommentEvent) {
+	if event.GetAction() != actionCreated {
+		return
+	}
+
+	subs := p.GetSubscribedChannelsForRepository(event.GetRepo())
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Discussions() {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "discussionComment", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_discussion_comment",
+			Message:   message,
+			ChannelId: sub.ChannelID,
+		}
+		p.createSubscriptionPost(sub, post, event)
+	}
+}
+
+// handleDiscussionCommentMentionNotification notifies the users and teams mentioned in a new
+// discussion comment, like handleCommentMentionNotification does for issue comments.
+func (p *Plugin) handleDiscussionCommentMentionNotification(event *DiscussionCommentEvent) {
+	if event.GetAction() != actionCreated {
+		return
+	}
+
+	body := event.GetComment().GetBody()
+	labels := event.GetDiscussion().Labels
+
+	p.notifyMentionedTeams(body, event.GetRepo(), labels, "discussionCommentTeamMentionNotification", func(team string) interface{} {
+		return &DiscussionCommentTeamMention{Team: team, DiscussionCommentEvent: event}
+	})
+
+	skip := []string{event.GetDiscussion().GetUser().GetLogin()}
+	p.notifyMentionedUsers(body, event.GetRepo(), labels, event.GetSender(), skip, "discussionCommentMentionNotification", event)
+}
@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const discussionCommentPayload = `{
+	"action": "created",
+	"discussion": {
+		"number": 7,
+		"title": "How to subscribe?",
+		"html_url": "https://github.com/mattermost/mattermost-plugin-github/discussions/7",
+		"category": {"name": "Q&A", "emoji": ":pray:", "is_answerable": true}
+	},
+	"comment": {
+		"id": 1,
+		"body": "Use the discussions feature @alice",
+		"html_url": "https://github.com/mattermost/mattermost-plugin-github/discussions/7#discussioncomment-1",
+		"user": {"login": "panda"}
+	},
+	"repository": {"full_name": "mattermost/mattermost-plugin-github", "html_url": "https://github.com/mattermost/mattermost-plugin-github"},
+	"sender": {"login": "panda", "html_url": "https://github.com/panda"}
+}`
+
+func TestParseWebhook(t *testing.T) {
+	t.Run("discussion comment", func(t *testing.T) {
+		event, err := parseWebhook("discussion_comment", []byte(discussionCommentPayload))
+		require.NoError(t, err)
+
+		commentEvent, ok := event.(*DiscussionCommentEvent)
+		require.True(t, ok)
+		assert.Equal(t, "created", commentEvent.GetAction())
+		assert.Equal(t, 7, commentEvent.GetDiscussion().GetNumber())
+		assert.Equal(t, "Q&A", commentEvent.GetDiscussion().GetCategory().GetName())
+		assert.Equal(t, "panda", commentEvent.GetComment().GetUser().GetLogin())
+		assert.Equal(t, "mattermost/mattermost-plugin-github", commentEvent.GetRepo().GetFullName())
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := parseWebhook("discussion", []byte(`{"action": 1}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("event known to the GitHub client", func(t *testing.T) {
+		event, err := parseWebhook("star", []byte(`{"action": "created"}`))
+		require.NoError(t, err)
+		assert.IsType(t, &github.StarEvent{}, event)
+	})
+}
+
+func TestPostDiscussionCommentEvent(t *testing.T) {
+	event, err := parseWebhook("discussion_comment", []byte(discussionCommentPayload))
+	require.NoError(t, err)
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/mattermost-plugin-github": {
+			{ChannelID: "channel1", Features: "discussions", Repository: "mattermost/mattermost-plugin-github"},
+			{ChannelID: "channel2", Features: "pulls,issues", Repository: "mattermost/mattermost-plugin-github"},
+		},
+	}}
+	p, api := setupSubscriptionTestPlugin(t, subs)
+
+	p.postDiscussionCommentEvent(event.(*DiscussionCommentEvent))
+
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+	api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "channel1" && post.Type == "custom_git_discussion_comment" && strings.Contains(post.Message, "New comment by")
+	}))
+}
+
+func TestHandleDiscussionCommentMentionNotification(t *testing.T) {
+	data, err := json.Marshal(map[string]*TeamMapping{"mattermost/core": {ChannelID: "channel1"}})
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("KVGet", teamMappingsKey).Return(data, nil)
+	api.On("KVGet", mock.AnythingOfType("string")).Return(nil, nil)
+	api.On("GetConfig").Return(&model.Config{})
+	api.On("CreatePost", mock.Anything).Return(func(post *model.Post) *model.Post { return post.Clone() }, nil)
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+	p.BotUserID = "bot"
+
+	event := &DiscussionCommentEvent{
+		Action:     github.String("created"),
+		Repo:       &github.Repository{FullName: github.String("mattermost/mattermost-plugin-github")},
+		Discussion: &Discussion{Number: github.Int(7), Title: github.String("How to subscribe?"), User: &github.User{Login: github.String("alice")}},
+		Comment:    &DiscussionComment{Body: github.String("@mattermost/core @alice @bob please have a look")},
+		Sender:     &github.User{Login: github.String("panda")},
+	}
+
+	p.handleDiscussionCommentMentionNotification(event)
+
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+	api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "channel1" && strings.Contains(post.Message, "mentioned **@mattermost/core**")
+	}))
+	// The author of the discussion is skipped, like the author of an issue.
+	api.AssertNotCalled(t, "KVGet", "alice"+githubUsernameKey)
+	api.AssertCalled(t, "KVGet", "bob"+githubUsernameKey)
+}
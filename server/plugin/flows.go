@@ -11,6 +11,8 @@ import (
 	"github.com/gorilla/mux"
 	pluginapi "github.com/mattermost/mattermost-plugin-api"
 	"github.com/mattermost/mattermost-plugin-api/experimental/flow"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
 
 	"github.com/pkg/errors"
 
@@ -38,6 +40,18 @@ type FlowManager struct {
 	pingBroker PingBroker
 	tracker    Tracker
 
+	getUserLocale    func(userID string) string
+	getLocalizer     func(locale string) *i18n.Localizer
+	localize         func(l *i18n.Localizer, message *i18n.Message) string
+	localizeWithData func(l *i18n.Localizer, message *i18n.Message, data map[string]interface{}) string
+
+	// flows holds the wizards in every language with translations, English first.
+	flows       []*localizedFlows
+	flowMatcher language.Matcher
+}
+
+// localizedFlows are the wizards in one language.
+type localizedFlows struct {
 	setupFlow        *flow.Flow
 	oauthFlow        *flow.Flow
 	webhokFlow       *flow.Flow
@@ -55,61 +69,101 @@ func (p *Plugin) NewFlowManager() *FlowManager {
 
 		pingBroker: p.webhookBroker,
 		tracker:    p,
+
+		getUserLocale:    p.getUserLocale,
+		getLocalizer:     p.getLocalizer,
+		localize:         p.localize,
+		localizeWithData: p.localizeWithData,
+	}
+
+	tags := []language.Tag{language.English}
+	for _, tag := range p.bundle.LanguageTags() {
+		if tag != language.English {
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, tag := range tags {
+		fm.flows = append(fm.flows, fm.newLocalizedFlows(tag))
 	}
+	fm.flowMatcher = language.NewMatcher(tags)
 
-	fm.setupFlow = fm.newFlow("setup").WithSteps(
-		fm.stepWelcome(),
+	return fm
+}
+
+// newLocalizedFlows builds the wizards in the language of tag. The English flows keep the
+// unsuffixed names, so that wizards in progress survive upgrades.
+func (fm *FlowManager) newLocalizedFlows(tag language.Tag) *localizedFlows {
+	l := fm.getLocalizer(tag.String())
+	suffix := ""
+	if tag != language.English {
+		suffix = "-" + tag.String()
+	}
+
+	flows := &localizedFlows{}
+	flows.setupFlow = fm.newFlow(flow.Name("setup"+suffix)).WithSteps(
+		fm.stepWelcome(l),
 
-		fm.stepDelegateQuestion(),
-		fm.stepDelegateConfirmation(),
-		fm.stepDelegateComplete(),
+		fm.stepDelegateQuestion(l),
+		fm.stepDelegateConfirmation(l),
+		fm.stepDelegateComplete(l),
 
-		fm.stepEnterprise(),
-		fm.stepOAuthInfo(),
-		fm.stepOAuthInput(),
-		fm.stepOAuthConnect(),
+		fm.stepEnterprise(l),
+		fm.stepOAuthInfo(l),
+		fm.stepOAuthInput(l),
+		fm.stepOAuthConnect(l),
 
-		fm.stepWebhookQuestion(),
-		fm.stepWebhookWarning(),
-		fm.stepWebhookConfirmation(),
+		fm.stepWebhookQuestion(l),
+		fm.stepWebhookWarning(l),
+		fm.stepWebhookConfirmation(l),
 
-		fm.stepAnnouncementQuestion(),
-		fm.stepAnnouncementConfirmation(),
+		fm.stepAnnouncementQuestion(l),
+		fm.stepAnnouncementConfirmation(l),
 
-		fm.doneStep(),
+		fm.doneStep(l),
 
-		fm.stepCancel("setup"),
+		fm.stepCancel(l, "setup"),
 	)
 
-	fm.oauthFlow = fm.newFlow("oauth").WithSteps(
-		fm.stepEnterprise(),
-		fm.stepOAuthInfo(),
-		fm.stepOAuthInput(),
-		fm.stepOAuthConnect().Terminal(),
+	flows.oauthFlow = fm.newFlow(flow.Name("oauth"+suffix)).WithSteps(
+		fm.stepEnterprise(l),
+		fm.stepOAuthInfo(l),
+		fm.stepOAuthInput(l),
+		fm.stepOAuthConnect(l).Terminal(),
 
-		fm.stepCancel("setup oauth"),
+		fm.stepCancel(l, "setup oauth"),
 	)
-	fm.webhokFlow = fm.newFlow("webhook").WithSteps(
-		fm.stepWebhookQuestion(),
+	flows.webhokFlow = fm.newFlow(flow.Name("webhook"+suffix)).WithSteps(
+		fm.stepWebhookQuestion(l),
 		flow.NewStep(stepWebhookConfirmation).
-			WithText("Use `/github subscriptions add` to subscribe any Mattermost channel to your GitHub repository. [Learn more](https://github.com/mattermost/mattermost-plugin-github#slash-commands)").
+			WithText(fm.localize(l, subscribeHintMessage)).
 			Terminal(),
 
-		fm.stepCancel("setup webhook"),
+		fm.stepCancel(l, "setup webhook"),
 	)
-	fm.announcementFlow = fm.newFlow("announcement").WithSteps(
-		fm.stepAnnouncementQuestion(),
-		fm.stepAnnouncementConfirmation().Terminal(),
+	flows.announcementFlow = fm.newFlow(flow.Name("announcement"+suffix)).WithSteps(
+		fm.stepAnnouncementQuestion(l),
+		fm.stepAnnouncementConfirmation(l).Terminal(),
 
-		fm.stepCancel("setup announcement"),
+		fm.stepCancel(l, "setup announcement"),
 	)
 
-	return fm
+	return flows
 }
 
-func (fm *FlowManager) doneStep() flow.Step {
+// getFlows returns the wizards in the language of the given user.
+func (fm *FlowManager) getFlows(userID string) *localizedFlows {
+	_, index, _ := fm.flowMatcher.Match(language.Make(fm.getUserLocale(userID)))
+	if index < 0 || index >= len(fm.flows) {
+		index = 0
+	}
+
+	return fm.flows[index]
+}
+
+func (fm *FlowManager) doneStep(l *i18n.Localizer) flow.Step {
 	return flow.NewStep(stepDone).
-		WithText(":tada: You successfully installed GitHub.").
+		WithText(fm.localize(l, &i18n.Message{ID: "flow.done", Other: ":tada: You successfully installed GitHub."})).
 		OnRender(fm.onDone).Terminal()
 }
 
@@ -118,7 +172,7 @@ func (fm *FlowManager) onDone(f *flow.Flow) {
 
 	delegatedFrom := f.GetState().GetString(keyDelegatedFrom)
 	if delegatedFrom != "" {
-		err := fm.setupFlow.ForUser(delegatedFrom).Go(stepDelegateComplete)
+		err := fm.getFlows(delegatedFrom).setupFlow.ForUser(delegatedFrom).Go(stepDelegateComplete)
 		fm.client.Log.Warn("failed start configuration wizard for delegate", "error", err)
 	}
 }
@@ -174,33 +228,51 @@ const (
 	keyIsOAuthConfigured           = "IsOAuthConfigured"
 )
 
-func cancelButton() flow.Button {
+// Messages shared by several steps.
+var (
+	continueMessage      = &i18n.Message{ID: "flow.continue", Other: "Continue"}
+	yesMessage           = &i18n.Message{ID: "flow.yes", Other: "Yes"}
+	noMessage            = &i18n.Message{ID: "flow.no", Other: "No"}
+	toMessage            = &i18n.Message{ID: "flow.to", Other: "To"}
+	saveMessage          = &i18n.Message{ID: "flow.saveAndContinue", Other: "Save & continue"}
+	subscribeHintMessage = &i18n.Message{
+		ID:    "flow.webhook.subscribeHint",
+		Other: "Use `/github subscriptions add` to subscribe any Mattermost channel to your GitHub repository. [Learn more](https://github.com/mattermost/mattermost-plugin-github#slash-commands)",
+	}
+)
+
+// flowVariable returns a reference to a value of the flow state, to pass a flow template
+// variable through a localized message.
+func flowVariable(name string) string {
+	return "{{ ." + name + " }}"
+}
+
+func (fm *FlowManager) cancelButton(l *i18n.Localizer) flow.Button {
 	return flow.Button{
-		Name:    "Cancel setup",
+		Name:    fm.localize(l, &i18n.Message{ID: "flow.cancel", Other: "Cancel setup"}),
 		Color:   flow.ColorDanger,
 		OnClick: flow.Goto(stepCancel),
 	}
 }
 
-func (fm *FlowManager) stepCancel(command string) flow.Step {
+func (fm *FlowManager) stepCancel(l *i18n.Localizer, command string) flow.Step {
 	return flow.NewStep(stepCancel).
 		Terminal().
-		WithText(fmt.Sprintf("GitHub integration setup has stopped. Restart setup later by running `/github %s`. Learn more about the plugin [here](%s).", command, Manifest.HomepageURL)).
+		WithText(fm.localizeWithData(l, &i18n.Message{
+			ID:    "flow.cancelled",
+			Other: "GitHub integration setup has stopped. Restart setup later by running `/github {{.Command}}`. Learn more about the plugin [here]({{.URL}}).",
+		}, map[string]interface{}{"Command": command, "URL": Manifest.HomepageURL})).
 		WithColor(flow.ColorDanger)
 }
 
-func continueButtonF(f func(f *flow.Flow) (flow.Name, flow.State, error)) flow.Button {
+func (fm *FlowManager) continueButton(l *i18n.Localizer, next flow.Name) flow.Button {
 	return flow.Button{
-		Name:    "Continue",
+		Name:    fm.localize(l, continueMessage),
 		Color:   flow.ColorPrimary,
-		OnClick: f,
+		OnClick: flow.Goto(next),
 	}
 }
 
-func continueButton(next flow.Name) flow.Button {
-	return continueButtonF(flow.Goto(next))
-}
-
 func (fm *FlowManager) getBaseState() flow.State {
 	config := fm.getConfiguration()
 	isOAuthConfigured := config.GitHubOAuthClientID != "" || config.GitHubOAuthClientSecret != ""
@@ -215,7 +287,7 @@ func (fm *FlowManager) StartSetupWizard(userID string, delegatedFrom string) err
 	state := fm.getBaseState()
 	state[keyDelegatedFrom] = delegatedFrom
 
-	err := fm.setupFlow.ForUser(userID).Start(state)
+	err := fm.getFlows(userID).setupFlow.ForUser(userID).Start(state)
 	if err != nil {
 		return err
 	}
@@ -243,7 +315,7 @@ func (fm *FlowManager) trackCompleteSetupWizard(userID string) {
 func (fm *FlowManager) StartOauthWizard(userID string) error {
 	state := fm.getBaseState()
 
-	err := fm.oauthFlow.ForUser(userID).Start(state)
+	err := fm.getFlows(userID).oauthFlow.ForUser(userID).Start(state)
 	if err != nil {
 		return err
 	}
@@ -265,33 +337,41 @@ func (fm *FlowManager) trackCompleteOauthWizard(userID string) {
 	})
 }
 
-func (fm *FlowManager) stepWelcome() flow.Step {
-	welcomePretext := ":wave: Welcome to your GitHub integration! [Learn more](https://github.com/mattermost/mattermost-plugin-github#readme)"
+func (fm *FlowManager) stepWelcome(l *i18n.Localizer) flow.Step {
+	welcomePretext := fm.localize(l, &i18n.Message{
+		ID:    "flow.welcome.pretext",
+		Other: ":wave: Welcome to your GitHub integration! [Learn more](https://github.com/mattermost/mattermost-plugin-github#readme)",
+	})
 
-	welcomeText := `
-{{- if .UsePreregisteredApplication -}}
-Just a few configuration steps to go!
-- **Step 1:** Connect your GitHub account
-- **Step 2:** Create a webhook in GitHub
-{{- else -}}
-Just a few configuration steps to go!
-- **Step 1:** Register an OAuth application in GitHub and enter OAuth values.
-- **Step 2:** Connect your GitHub account
-- **Step 3:** Create a webhook in GitHub
-{{- end -}}`
+	welcomeText := "{{- if .UsePreregisteredApplication -}}\n" +
+		fm.localize(l, &i18n.Message{
+			ID: "flow.welcome.preregistered",
+			Other: "Just a few configuration steps to go!\n" +
+				"- **Step 1:** Connect your GitHub account\n" +
+				"- **Step 2:** Create a webhook in GitHub",
+		}) +
+		"\n{{- else -}}\n" +
+		fm.localize(l, &i18n.Message{
+			ID: "flow.welcome",
+			Other: "Just a few configuration steps to go!\n" +
+				"- **Step 1:** Register an OAuth application in GitHub and enter OAuth values.\n" +
+				"- **Step 2:** Connect your GitHub account\n" +
+				"- **Step 3:** Create a webhook in GitHub",
+		}) +
+		"\n{{- end -}}"
 
 	return flow.NewStep(stepWelcome).
 		WithText(welcomeText).
 		WithPretext(welcomePretext).
-		WithButton(continueButton(""))
+		WithButton(fm.continueButton(l, ""))
 }
 
-func (fm *FlowManager) stepDelegateQuestion() flow.Step {
-	delegateQuestionText := "Are you setting this GitHub integration up, or is someone else?"
+func (fm *FlowManager) stepDelegateQuestion(l *i18n.Localizer) flow.Step {
+	delegateQuestionText := fm.localize(l, &i18n.Message{ID: "flow.delegate.question", Other: "Are you setting this GitHub integration up, or is someone else?"})
 	return flow.NewStep(stepDelegateQuestion).
 		WithText(delegateQuestionText).
 		WithButton(flow.Button{
-			Name:  "I'll do it myself",
+			Name:  fm.localize(l, &i18n.Message{ID: "flow.delegate.myself", Other: "I'll do it myself"}),
 			Color: flow.ColorPrimary,
 			OnClick: func(f *flow.Flow) (flow.Name, flow.State, error) {
 				if f.GetState().GetBool(keyUsePreregisteredApplication) {
@@ -302,18 +382,18 @@ func (fm *FlowManager) stepDelegateQuestion() flow.Step {
 			},
 		}).
 		WithButton(flow.Button{
-			Name:  "I need someone else",
+			Name:  fm.localize(l, &i18n.Message{ID: "flow.delegate.someoneElse", Other: "I need someone else"}),
 			Color: flow.ColorDefault,
 			Dialog: &model.Dialog{
-				Title:       "Send instructions",
-				SubmitLabel: "Send",
+				Title:       fm.localize(l, &i18n.Message{ID: "flow.delegate.dialog.title", Other: "Send instructions"}),
+				SubmitLabel: fm.localize(l, &i18n.Message{ID: "flow.delegate.dialog.submit", Other: "Send"}),
 				Elements: []model.DialogElement{
 					{
-						DisplayName: "To",
+						DisplayName: fm.localize(l, toMessage),
 						Name:        "delegate",
 						Type:        "select",
 						DataSource:  "users",
-						Placeholder: "Search for people",
+						Placeholder: fm.localize(l, &i18n.Message{ID: "flow.delegate.dialog.placeholder", Other: "Search for people"}),
 					},
 				},
 			},
@@ -346,60 +426,70 @@ func (fm *FlowManager) submitDelegateSelection(f *flow.Flow, submitted map[strin
 	}, nil, nil
 }
 
-func (fm *FlowManager) stepDelegateConfirmation() flow.Step {
+func (fm *FlowManager) stepDelegateConfirmation(l *i18n.Localizer) flow.Step {
+	data := map[string]interface{}{"DelegatedTo": flowVariable(keyDelegatedTo)}
 	return flow.NewStep(stepDelegateConfirmation).
-		WithText("GitHub integration setup details have been sent to @{{ .DelegatedTo }}").
+		WithText(fm.localizeWithData(l, &i18n.Message{
+			ID:    "flow.delegate.confirmation",
+			Other: "GitHub integration setup details have been sent to @{{.DelegatedTo}}",
+		}, data)).
 		WithButton(flow.Button{
-			Name:     "Waiting for @{{ .DelegatedTo }}...",
+			Name:     fm.localizeWithData(l, &i18n.Message{ID: "flow.delegate.waiting", Other: "Waiting for @{{.DelegatedTo}}..."}, data),
 			Color:    flow.ColorDefault,
 			Disabled: true,
 		}).
-		WithButton(cancelButton())
+		WithButton(fm.cancelButton(l))
 }
 
-func (fm *FlowManager) stepDelegateComplete() flow.Step {
+func (fm *FlowManager) stepDelegateComplete(l *i18n.Localizer) flow.Step {
 	return flow.NewStep(stepDelegateComplete).
-		WithText("@{{ .DelegatedTo }} completed configuring the integration.").
+		WithText(fm.localizeWithData(l, &i18n.Message{
+			ID:    "flow.delegate.complete",
+			Other: "@{{.DelegatedTo}} completed configuring the integration.",
+		}, map[string]interface{}{"DelegatedTo": flowVariable(keyDelegatedTo)})).
 		Next(stepDone)
 }
 
-func (fm *FlowManager) stepEnterprise() flow.Step {
-	enterpriseText := "Do you have a GitHub Enterprise account?"
+func (fm *FlowManager) stepEnterprise(l *i18n.Localizer) flow.Step {
+	enterpriseText := fm.localize(l, &i18n.Message{ID: "flow.enterprise.question", Other: "Do you have a GitHub Enterprise account?"})
 	return flow.NewStep(stepEnterprise).
 		WithText(enterpriseText).
 		WithButton(flow.Button{
-			Name:  "Yes",
+			Name:  fm.localize(l, yesMessage),
 			Color: flow.ColorPrimary,
 			Dialog: &model.Dialog{
-				Title:            "Enterprise account",
-				IntroductionText: "Enter an **Enterprise Base URL** and **Enterprise Upload URL** by setting these values to match your GitHub Enterprise URL (Example: https://github.example.com). It's not necessary to have separate Base and Upload URLs.",
-				SubmitLabel:      "Save & continue",
+				Title: fm.localize(l, &i18n.Message{ID: "flow.enterprise.dialog.title", Other: "Enterprise account"}),
+				IntroductionText: fm.localize(l, &i18n.Message{
+					ID:    "flow.enterprise.dialog.introduction",
+					Other: "Enter an **Enterprise Base URL** and **Enterprise Upload URL** by setting these values to match your GitHub Enterprise URL (Example: https://github.example.com). It's not necessary to have separate Base and Upload URLs.",
+				}),
+				SubmitLabel: fm.localize(l, saveMessage),
 				Elements: []model.DialogElement{
 					{
 
-						DisplayName: "Enterprise Base URL",
+						DisplayName: fm.localize(l, &i18n.Message{ID: "flow.enterprise.dialog.baseURL", Other: "Enterprise Base URL"}),
 						Name:        "base_url",
 						Type:        "text",
 						SubType:     "url",
-						Placeholder: "Enter Enterprise Base URL",
+						Placeholder: fm.localize(l, &i18n.Message{ID: "flow.enterprise.dialog.baseURL.placeholder", Other: "Enter Enterprise Base URL"}),
 					},
 					{
-						DisplayName: "Enterprise Upload URL",
+						DisplayName: fm.localize(l, &i18n.Message{ID: "flow.enterprise.dialog.uploadURL", Other: "Enterprise Upload URL"}),
 						Name:        "upload_url",
 						Type:        "text",
 						SubType:     "url",
-						Placeholder: "Enter Enterprise Upload URL",
+						Placeholder: fm.localize(l, &i18n.Message{ID: "flow.enterprise.dialog.uploadURL.placeholder", Other: "Enter Enterprise Upload URL"}),
 					},
 				},
 			},
 			OnDialogSubmit: fm.submitEnterpriseConfig,
 		}).
 		WithButton(flow.Button{
-			Name:    "No",
+			Name:    fm.localize(l, noMessage),
 			Color:   flow.ColorDefault,
 			OnClick: flow.Goto(stepOAuthInfo),
 		}).
-		WithButton(cancelButton())
+		WithButton(fm.cancelButton(l))
 }
 
 func (fm *FlowManager) submitEnterpriseConfig(f *flow.Flow, submitted map[string]interface{}) (flow.Name, flow.State, map[string]string, error) {
@@ -461,60 +551,71 @@ func (fm *FlowManager) submitEnterpriseConfig(f *flow.Flow, submitted map[string
 	}, nil, nil
 }
 
-func (fm *FlowManager) stepOAuthInfo() flow.Step {
-	oauthPretext := `
-##### :white_check_mark: Step 1: Register an OAuth Application in GitHub
-You must first register the Mattermost GitHub Plugin as an authorized OAuth app.`
-	oauthMessage := fmt.Sprintf(""+
-		"1. In a browser, go to {{ .BaseURL}}settings/applications/new.\n"+
-		"2. Set the following values:\n"+
-		"	- Application name: `Mattermost GitHub Plugin - <your company name>`\n"+
-		"	- Homepage URL: `https://github.com/mattermost/mattermost-plugin-github`\n"+
-		"	- Authorization callback URL: `%s/oauth/complete`\n"+
-		"3. Select **Register application**\n"+
-		"4. Select **Generate a new client secret**.\n"+
-		"5. If prompted, complete 2FA.",
-		fm.pluginURL,
-	)
+func (fm *FlowManager) stepOAuthInfo(l *i18n.Localizer) flow.Step {
+	oauthPretext := "\n##### :white_check_mark: " +
+		fm.localize(l, &i18n.Message{ID: "flow.oauth.info.title", Other: "Step 1: Register an OAuth Application in GitHub"}) + "\n" +
+		fm.localize(l, &i18n.Message{ID: "flow.oauth.info.pretext", Other: "You must first register the Mattermost GitHub Plugin as an authorized OAuth app."})
+	oauthMessage := fm.localizeWithData(l, &i18n.Message{
+		ID: "flow.oauth.info",
+		Other: "1. In a browser, go to {{.BaseURL}}settings/applications/new.\n" +
+			"2. Set the following values:\n" +
+			"	- Application name: `Mattermost GitHub Plugin - <your company name>`\n" +
+			"	- Homepage URL: `https://github.com/mattermost/mattermost-plugin-github`\n" +
+			"	- Authorization callback URL: `{{.CallbackURL}}`\n" +
+			"3. Select **Register application**\n" +
+			"4. Select **Generate a new client secret**.\n" +
+			"5. If prompted, complete 2FA.",
+	}, map[string]interface{}{"BaseURL": flowVariable(keyBaseURL), "CallbackURL": fm.pluginURL + "/oauth/complete"})
 
 	return flow.NewStep(stepOAuthInfo).
 		WithPretext(oauthPretext).
 		WithText(oauthMessage).
 		WithImage("public/new-oauth-application.png").
-		WithButton(continueButton("")).
-		WithButton(cancelButton())
+		WithButton(fm.continueButton(l, "")).
+		WithButton(fm.cancelButton(l))
 }
 
-func (fm *FlowManager) stepOAuthInput() flow.Step {
+func (fm *FlowManager) stepOAuthInput(l *i18n.Localizer) flow.Step {
+	introduction := fm.localize(l, &i18n.Message{
+		ID:    "flow.oauth.input.dialog.introduction",
+		Other: "Please enter the **GitHub OAuth Client ID** and **GitHub OAuth Client Secret** you copied in a previous step.",
+	}) + "{{ if .IsOAuthConfigured }}\n\n" + fm.localize(l, &i18n.Message{
+		ID:    "flow.oauth.input.dialog.overwrite",
+		Other: "**Any existing OAuth configuration will be overwritten.**",
+	}) + "{{end}}"
+
 	return flow.NewStep(stepOAuthInput).
-		WithText("Click the Continue button below to open a dialog to enter the **GitHub OAuth Client ID** and **GitHub OAuth Client Secret**.").
+		WithText(fm.localize(l, &i18n.Message{
+			ID:    "flow.oauth.input",
+			Other: "Click the Continue button below to open a dialog to enter the **GitHub OAuth Client ID** and **GitHub OAuth Client Secret**.",
+		})).
 		WithButton(flow.Button{
-			Name:  "Continue",
+			Name:  fm.localize(l, continueMessage),
 			Color: flow.ColorPrimary,
 			Dialog: &model.Dialog{
-				Title:            "GitHub OAuth values",
-				IntroductionText: "Please enter the **GitHub OAuth Client ID** and **GitHub OAuth Client Secret** you copied in a previous step.{{ if .IsOAuthConfigured }}\n\n**Any existing OAuth configuration will be overwritten.**{{end}}",
-				SubmitLabel:      "Save & continue",
+				Title:            fm.localize(l, &i18n.Message{ID: "flow.oauth.input.dialog.title", Other: "GitHub OAuth values"}),
+				IntroductionText: introduction,
+				SubmitLabel:      fm.localize(l, saveMessage),
 				Elements: []model.DialogElement{
 					{
-						DisplayName: "GitHub OAuth Client ID",
+						DisplayName: fm.localize(l, &i18n.Message{ID: "flow.oauth.input.dialog.clientID", Other: "GitHub OAuth Client ID"}),
 						Name:        "client_id",
 						Type:        "text",
 						SubType:     "text",
-						Placeholder: "Enter GitHub OAuth Client ID",
+						Placeholder: fm.localize(l, &i18n.Message{ID: "flow.oauth.input.dialog.clientID.placeholder", Other: "Enter GitHub OAuth Client ID"}),
 					},
 					{
-						DisplayName: "GitHub OAuth Client Secret",
+						DisplayName: fm.localize(l, &i18n.Message{ID: "flow.oauth.input.dialog.clientSecret", Other: "GitHub OAuth Client Secret"}),
 						Name:        "client_secret",
 						Type:        "text",
 						SubType:     "text",
-						Placeholder: "Enter GitHub OAuth Client Secret",
+						Placeholder: fm.localize(l, &i18n.Message{ID: "flow.oauth.input.dialog.clientSecret.placeholder", Other: "Enter GitHub OAuth Client Secret"}),
 					},
 				},
 			},
 			OnDialogSubmit: fm.submitOAuthConfig,
 		}).
-		WithButton(cancelButton())
+		WithButton(fm.cancelButton(l))
 }
 
 func (fm *FlowManager) submitOAuthConfig(f *flow.Flow, submitted map[string]interface{}) (flow.Name, flow.State, map[string]string, error) {
@@ -532,7 +633,7 @@ func (fm *FlowManager) submitOAuthConfig(f *flow.Flow, submitted map[string]inte
 	clientID = strings.TrimSpace(clientID)
 
 	if len(clientID) != 20 {
-		errorList["client_id"] = "Client ID should be 20 characters long"
+		errorList["client_id"] = fm.localize(fm.getLocalizer(fm.getUserLocale(f.UserID)), &i18n.Message{ID: "flow.oauth.input.clientID.invalid", Other: "Client ID should be 20 characters long"})
 	}
 
 	clientSecretRaw, ok := submitted["client_secret"]
@@ -547,7 +648,7 @@ func (fm *FlowManager) submitOAuthConfig(f *flow.Flow, submitted map[string]inte
 	clientSecret = strings.TrimSpace(clientSecret)
 
 	if len(clientSecret) != 40 {
-		errorList["client_secret"] = "Client Secret should be 40 characters long"
+		errorList["client_secret"] = fm.localize(fm.getLocalizer(fm.getUserLocale(f.UserID)), &i18n.Message{ID: "flow.oauth.input.clientSecret.invalid", Other: "Client Secret should be 40 characters long"})
 	}
 
 	if len(errorList) != 0 {
@@ -571,10 +672,16 @@ func (fm *FlowManager) submitOAuthConfig(f *flow.Flow, submitted map[string]inte
 	return "", nil, nil, nil
 }
 
-func (fm *FlowManager) stepOAuthConnect() flow.Step {
-	connectPretext := "##### :white_check_mark: Step {{ if .UsePreregisteredApplication }}1{{ else }}2{{ end }}: Connect your GitHub account"
+func (fm *FlowManager) stepOAuthConnect(l *i18n.Localizer) flow.Step {
+	connectPretext := "##### :white_check_mark: " + fm.localizeWithData(l, &i18n.Message{
+		ID:    "flow.oauth.connect.title",
+		Other: "Step {{.Number}}: Connect your GitHub account",
+	}, map[string]interface{}{"Number": "{{ if .UsePreregisteredApplication }}1{{ else }}2{{ end }}"})
 	connectURL := fmt.Sprintf("%s/oauth/connect", fm.pluginURL)
-	connectText := fmt.Sprintf("Go [here](%s) to connect your account.", connectURL)
+	connectText := fm.localizeWithData(l, &i18n.Message{
+		ID:    "flow.oauth.connect",
+		Other: "Go [here]({{.URL}}) to connect your account.",
+	}, map[string]interface{}{"URL": connectURL})
 	return flow.NewStep(stepOAuthConnect).
 		WithText(connectText).
 		WithPretext(connectPretext).
@@ -585,7 +692,7 @@ func (fm *FlowManager) stepOAuthConnect() flow.Step {
 func (fm *FlowManager) StartWebhookWizard(userID string) error {
 	state := fm.getBaseState()
 
-	err := fm.webhokFlow.ForUser(userID).Start(state)
+	err := fm.getFlows(userID).webhokFlow.ForUser(userID).Start(state)
 	if err != nil {
 		return err
 	}
@@ -607,34 +714,42 @@ func (fm *FlowManager) trackCompleteWebhookWizard(userID string) {
 	})
 }
 
-func (fm *FlowManager) stepWebhookQuestion() flow.Step {
-	questionPretext := `##### :white_check_mark: Step {{ if .UsePreregisteredApplication }}2{{ else }}3{{ end }}: Create a Webhook in GitHub
-The final setup step requires a Mattermost System Admin to create a webhook for each GitHub organization or repository to receive notifications for, or want to subscribe to.`
+func (fm *FlowManager) stepWebhookQuestion(l *i18n.Localizer) flow.Step {
+	questionPretext := "##### :white_check_mark: " + fm.localizeWithData(l, &i18n.Message{
+		ID:    "flow.webhook.question.title",
+		Other: "Step {{.Number}}: Create a Webhook in GitHub",
+	}, map[string]interface{}{"Number": "{{ if .UsePreregisteredApplication }}2{{ else }}3{{ end }}"}) + "\n" + fm.localize(l, &i18n.Message{
+		ID:    "flow.webhook.question.pretext",
+		Other: "The final setup step requires a Mattermost System Admin to create a webhook for each GitHub organization or repository to receive notifications for, or want to subscribe to.",
+	})
 	return flow.NewStep(stepWebhookQuestion).
-		WithText("Do you want to create a webhook?").
+		WithText(fm.localize(l, &i18n.Message{ID: "flow.webhook.question", Other: "Do you want to create a webhook?"})).
 		WithPretext(questionPretext).
 		WithButton(flow.Button{
-			Name:  "Yes",
+			Name:  fm.localize(l, yesMessage),
 			Color: flow.ColorPrimary,
 			Dialog: &model.Dialog{
-				Title:       "Create webhook",
-				SubmitLabel: "Create",
+				Title:       fm.localize(l, &i18n.Message{ID: "flow.webhook.dialog.title", Other: "Create webhook"}),
+				SubmitLabel: fm.localize(l, &i18n.Message{ID: "flow.webhook.dialog.submit", Other: "Create"}),
 				Elements: []model.DialogElement{
 					{
 
-						DisplayName: "GitHub repository or organization name",
+						DisplayName: fm.localize(l, &i18n.Message{ID: "flow.webhook.dialog.repoOrg", Other: "GitHub repository or organization name"}),
 						Name:        "repo_org",
 						Type:        "text",
 						SubType:     "text",
-						Placeholder: "Enter GitHub repository or organization name",
-						HelpText:    "Specify the GitHub repository or organization to connect to Mattermost. For example, mattermost/mattermost-server.",
+						Placeholder: fm.localize(l, &i18n.Message{ID: "flow.webhook.dialog.repoOrg.placeholder", Other: "Enter GitHub repository or organization name"}),
+						HelpText: fm.localize(l, &i18n.Message{
+							ID:    "flow.webhook.dialog.repoOrg.help",
+							Other: "Specify the GitHub repository or organization to connect to Mattermost. For example, mattermost/mattermost-server.",
+						}),
 					},
 				},
 			},
 			OnDialogSubmit: fm.submitWebhook,
 		}).
 		WithButton(flow.Button{
-			Name:    "No",
+			Name:    fm.localize(l, noMessage),
 			Color:   flow.ColorDefault,
 			OnClick: flow.Goto(stepWebhookWarning),
 		})
@@ -659,7 +774,11 @@ func (fm *FlowManager) submitWebhook(f *flow.Flow, submitted map[string]interfac
 		return "", nil, nil, errors.New("invalid format")
 	}
 
-	webhookEvents := []string{"create", "delete", "issue_comment", "issues", "pull_request", "pull_request_review", "pull_request_review_comment", "push", "star"}
+	webhookEvents := []string{"create", "delete", "issue_comment", "issues", "pull_request", "pull_request_review", "pull_request_review_comment", "push", "repository", "star", "status", "check_run", "workflow_run", "discussion", "discussion_comment", "dependabot_alert", "code_scanning_alert", "secret_scanning_alert", "deployment", "deployment_status", "milestone", "member", "branch_protection_rule", "public", "fork"}
+	if repo == "" {
+		// Projects belong to organizations, so only organization webhooks receive their events.
+		webhookEvents = append(webhookEvents, "projects_v2_item")
+	}
 
 	webhookConfig := map[string]interface{}{
 		"content_type": "json",
@@ -727,10 +846,13 @@ func (fm *FlowManager) submitWebhook(f *flow.Flow, submitted map[string]interfac
 	return stepWebhookConfirmation, nil, nil, nil
 }
 
-func (fm *FlowManager) stepWebhookWarning() flow.Step {
-	warnText := "The GitHub plugin uses a webhook to connect a GitHub account to Mattermost to listen for incoming GitHub events. " +
-		"You can't subscribe a channel to a repository for notifications until webhooks are configured.\n" +
-		"Restart setup later by running `/github setup webhook`"
+func (fm *FlowManager) stepWebhookWarning(l *i18n.Localizer) flow.Step {
+	warnText := fm.localize(l, &i18n.Message{
+		ID: "flow.webhook.warning",
+		Other: "The GitHub plugin uses a webhook to connect a GitHub account to Mattermost to listen for incoming GitHub events. " +
+			"You can't subscribe a channel to a repository for notifications until webhooks are configured.\n" +
+			"Restart setup later by running `/github setup webhook`",
+	})
 
 	return flow.NewStep(stepWebhookWarning).
 		WithText(warnText).
@@ -738,10 +860,10 @@ func (fm *FlowManager) stepWebhookWarning() flow.Step {
 		Next("")
 }
 
-func (fm *FlowManager) stepWebhookConfirmation() flow.Step {
+func (fm *FlowManager) stepWebhookConfirmation(l *i18n.Localizer) flow.Step {
 	return flow.NewStep(stepWebhookConfirmation).
-		WithTitle("Success! :tada: You've successfully set up your Mattermost GitHub integration! ").
-		WithText("Use `/github subscriptions add` to subscribe any Mattermost channel to your GitHub repository. [Learn more](https://github.com/mattermost/mattermost-plugin-github#slash-commands)").
+		WithTitle(fm.localize(l, &i18n.Message{ID: "flow.webhook.confirmation", Other: "Success! :tada: You've successfully set up your Mattermost GitHub integration! "})).
+		WithText(fm.localize(l, subscribeHintMessage)).
 		OnRender(func(f *flow.Flow) { fm.trackCompleteWebhookWizard(f.UserID) }).
 		Next("")
 }
@@ -749,7 +871,7 @@ func (fm *FlowManager) stepWebhookConfirmation() flow.Step {
 func (fm *FlowManager) StartAnnouncementWizard(userID string) error {
 	state := fm.getBaseState()
 
-	err := fm.announcementFlow.ForUser(userID).Start(state)
+	err := fm.getFlows(userID).announcementFlow.ForUser(userID).Start(state)
 	if err != nil {
 		return err
 	}
@@ -771,40 +893,43 @@ func (fm *FlowManager) trackCompletAnnouncementWizard(userID string) {
 	})
 }
 
-func (fm *FlowManager) stepAnnouncementQuestion() flow.Step {
-	defaultMessage := "Hi team,\n" +
-		"\n" +
-		"We've set up the Mattermost GitHub plugin to enable notifications from GitHub in Mattermost. To get started, run the `/github connect` slash command from any channel within Mattermost to connect that channel with GitHub. See the [documentation](https://github.com/mattermost/mattermost-plugin-github/blob/master/README.md#slash-commands) for details on using the GitHub plugin."
+func (fm *FlowManager) stepAnnouncementQuestion(l *i18n.Localizer) flow.Step {
+	defaultMessage := fm.localize(l, &i18n.Message{
+		ID: "flow.announcement.message",
+		Other: "Hi team,\n" +
+			"\n" +
+			"We've set up the Mattermost GitHub plugin to enable notifications from GitHub in Mattermost. To get started, run the `/github connect` slash command from any channel within Mattermost to connect that channel with GitHub. See the [documentation](https://github.com/mattermost/mattermost-plugin-github/blob/master/README.md#slash-commands) for details on using the GitHub plugin.",
+	})
 
 	return flow.NewStep(stepAnnouncementQuestion).
-		WithText("Want to let your team know?").
+		WithText(fm.localize(l, &i18n.Message{ID: "flow.announcement.question", Other: "Want to let your team know?"})).
 		WithButton(flow.Button{
-			Name:  "Send Message",
+			Name:  fm.localize(l, &i18n.Message{ID: "flow.announcement.send", Other: "Send Message"}),
 			Color: flow.ColorPrimary,
 			Dialog: &model.Dialog{
-				Title:       "Notify your team",
-				SubmitLabel: "Send message",
+				Title:       fm.localize(l, &i18n.Message{ID: "flow.announcement.dialog.title", Other: "Notify your team"}),
+				SubmitLabel: fm.localize(l, &i18n.Message{ID: "flow.announcement.dialog.submit", Other: "Send message"}),
 				Elements: []model.DialogElement{
 					{
-						DisplayName: "To",
+						DisplayName: fm.localize(l, toMessage),
 						Name:        "channel_id",
 						Type:        "select",
-						Placeholder: "Select channel",
+						Placeholder: fm.localize(l, &i18n.Message{ID: "flow.announcement.dialog.channel.placeholder", Other: "Select channel"}),
 						DataSource:  "channels",
 					},
 					{
-						DisplayName: "Message",
+						DisplayName: fm.localize(l, &i18n.Message{ID: "flow.announcement.dialog.message", Other: "Message"}),
 						Name:        "message",
 						Type:        "textarea",
 						Default:     defaultMessage,
-						HelpText:    "You can edit this message before sending it.",
+						HelpText:    fm.localize(l, &i18n.Message{ID: "flow.announcement.dialog.message.help", Other: "You can edit this message before sending it."}),
 					},
 				},
 			},
 			OnDialogSubmit: fm.submitChannelAnnouncement,
 		}).
 		WithButton(flow.Button{
-			Name:    "Not now",
+			Name:    fm.localize(l, &i18n.Message{ID: "flow.announcement.notNow", Other: "Not now"}),
 			Color:   flow.ColorDefault,
 			OnClick: flow.Goto(stepDone),
 		})
@@ -849,9 +974,12 @@ func (fm *FlowManager) submitChannelAnnouncement(f *flow.Flow, submitted map[str
 	}, nil, nil
 }
 
-func (fm *FlowManager) stepAnnouncementConfirmation() flow.Step {
+func (fm *FlowManager) stepAnnouncementConfirmation(l *i18n.Localizer) flow.Step {
 	return flow.NewStep(stepAnnouncementConfirmation).
-		WithText("Message to ~{{ .ChannelName }} was sent.").
+		WithText(fm.localizeWithData(l, &i18n.Message{
+			ID:    "flow.announcement.confirmation",
+			Other: "Message to ~{{.ChannelName}} was sent.",
+		}, map[string]interface{}{"ChannelName": flowVariable("ChannelName")})).
 		Next("").
 		OnRender(func(f *flow.Flow) { fm.trackCompletAnnouncementWizard(f.UserID) })
 }
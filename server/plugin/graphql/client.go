@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"net/http"
 	"net/url"
 	"path"
 
@@ -23,6 +24,13 @@ type Client struct {
 func NewClient(logger pluginapi.LogService, token oauth2.Token, username, orgName, enterpriseBaseURL string) *Client {
 	ts := oauth2.StaticTokenSource(&token)
 	httpClient := oauth2.NewClient(context.Background(), ts)
+
+	return NewClientWithHTTPClient(logger, httpClient, username, orgName, enterpriseBaseURL)
+}
+
+// NewClientWithHTTPClient is like NewClient, but sends the queries with an HTTP client that
+// already authenticates its requests, e.g. the one of a REST API client.
+func NewClientWithHTTPClient(logger pluginapi.LogService, httpClient *http.Client, username, orgName, enterpriseBaseURL string) *Client {
 	var client Client
 
 	if enterpriseBaseURL == "" {
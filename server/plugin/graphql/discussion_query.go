@@ -0,0 +1,35 @@
+package graphql
+
+import (
+	"github.com/shurcooL/githubv4"
+)
+
+type discussionQuery struct {
+	Repository struct {
+		Discussion struct {
+			Number githubv4.Int
+			Title  githubv4.String
+			URL    githubv4.URI
+			Author struct {
+				Login     githubv4.String
+				AvatarURL githubv4.URI `graphql:"avatarUrl"`
+				URL       githubv4.URI
+			}
+			Category struct {
+				Name githubv4.String
+			}
+			Answer struct {
+				URL githubv4.URI
+			}
+			Comments struct {
+				TotalCount githubv4.Int
+			}
+			Closed githubv4.Boolean
+			Labels struct {
+				Nodes []struct {
+					Name githubv4.String
+				}
+			} `graphql:"labels(first: 20)"`
+		} `graphql:"discussion(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
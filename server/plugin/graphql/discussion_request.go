@@ -0,0 +1,65 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+// Discussion is a GitHub discussion, as shown in link previews.
+type Discussion struct {
+	Number          int
+	Title           string
+	URL             string
+	AuthorLogin     string
+	AuthorAvatarURL string
+	AuthorURL       string
+	Category        string
+	Comments        int
+	Closed          bool
+	// Answered is true once a comment was marked as the answer.
+	Answered bool
+	Labels   []string
+}
+
+// GetDiscussion fetches a discussion of a repository.
+func (c *Client) GetDiscussion(ctx context.Context, owner, name string, number int) (*Discussion, error) {
+	params := map[string]interface{}{
+		queryParamOwner:  githubv4.String(owner),
+		queryParamName:   githubv4.String(name),
+		queryParamNumber: githubv4.Int(number),
+	}
+
+	var query discussionQuery
+	if err := c.executeQuery(ctx, &query, params); err != nil {
+		return nil, errors.Wrap(err, "Not able to execute the query")
+	}
+
+	d := query.Repository.Discussion
+	discussion := &Discussion{
+		Number:      int(d.Number),
+		Title:       string(d.Title),
+		AuthorLogin: string(d.Author.Login),
+		Category:    string(d.Category.Name),
+		Comments:    int(d.Comments.TotalCount),
+		Closed:      bool(d.Closed),
+		Answered:    d.Answer.URL.URL != nil,
+	}
+
+	if d.URL.URL != nil {
+		discussion.URL = d.URL.String()
+	}
+	if d.Author.AvatarURL.URL != nil {
+		discussion.AuthorAvatarURL = d.Author.AvatarURL.String()
+	}
+	if d.Author.URL.URL != nil {
+		discussion.AuthorURL = d.Author.URL.String()
+	}
+
+	for _, label := range d.Labels.Nodes {
+		discussion.Labels = append(discussion.Labels, string(label.Name))
+	}
+
+	return discussion, nil
+}
@@ -0,0 +1,36 @@
+package graphql
+
+import (
+	"github.com/shurcooL/githubv4"
+)
+
+type mergeStatusQuery struct {
+	Repository struct {
+		PullRequest struct {
+			Number         githubv4.Int
+			Title          githubv4.String
+			URL            githubv4.URI
+			State          githubv4.PullRequestState
+			IsDraft        githubv4.Boolean
+			HeadRefOid     githubv4.GitObjectID
+			ReviewDecision githubv4.PullRequestReviewDecision
+			Mergeable      githubv4.MergeableState
+
+			LatestOpinionatedReviews struct {
+				Nodes []struct {
+					State githubv4.PullRequestReviewState
+				}
+			} `graphql:"latestOpinionatedReviews(first: 100)"`
+
+			Commits struct {
+				Nodes []struct {
+					Commit struct {
+						StatusCheckRollup struct {
+							State githubv4.StatusState
+						}
+					}
+				}
+			} `graphql:"commits(last: 1)"`
+		} `graphql:"pullRequest(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
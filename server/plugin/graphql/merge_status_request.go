@@ -0,0 +1,97 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	queryParamOwner  = "owner"
+	queryParamName   = "name"
+	queryParamNumber = "number"
+)
+
+// PullRequestMergeStatus describes whether a pull request can be merged.
+type PullRequestMergeStatus struct {
+	Number  int
+	Title   string
+	URL     string
+	HeadSHA string
+	// Open is false once the pull request is closed or merged.
+	Open    bool
+	IsDraft bool
+	// Approved is true when the review requirements of the repository are met. Without required
+	// reviews, at least one reviewer has to approve and nobody may request changes.
+	Approved bool
+	// Mergeable is false on merge conflicts, and while GitHub is still checking for them.
+	Mergeable bool
+	// ChecksState is the combined state of the checks and commit statuses of the head commit, e.g.
+	// SUCCESS or FAILURE, or empty if it has none.
+	ChecksState string
+}
+
+// IsReadyToMerge reports whether the pull request is open, approved, free of conflicts and all
+// of its checks passed.
+func (s *PullRequestMergeStatus) IsReadyToMerge() bool {
+	checksPassed := s.ChecksState == "" || s.ChecksState == string(githubv4.StatusStateSuccess)
+	return s.Open && !s.IsDraft && s.Approved && s.Mergeable && checksPassed
+}
+
+// GetPullRequestMergeStatus fetches the review decision, mergeability and checks of a pull request.
+func (c *Client) GetPullRequestMergeStatus(ctx context.Context, owner, name string, number int) (*PullRequestMergeStatus, error) {
+	params := map[string]interface{}{
+		queryParamOwner:  githubv4.String(owner),
+		queryParamName:   githubv4.String(name),
+		queryParamNumber: githubv4.Int(number),
+	}
+
+	var query mergeStatusQuery
+	if err := c.executeQuery(ctx, &query, params); err != nil {
+		return nil, errors.Wrap(err, "Not able to execute the query")
+	}
+
+	pr := query.Repository.PullRequest
+	status := &PullRequestMergeStatus{
+		Number:    int(pr.Number),
+		Title:     string(pr.Title),
+		HeadSHA:   string(pr.HeadRefOid),
+		Open:      pr.State == githubv4.PullRequestStateOpen,
+		IsDraft:   bool(pr.IsDraft),
+		Mergeable: pr.Mergeable == githubv4.MergeableStateMergeable,
+	}
+
+	if pr.URL.URL != nil {
+		status.URL = pr.URL.String()
+	}
+
+	if len(pr.Commits.Nodes) > 0 {
+		status.ChecksState = string(pr.Commits.Nodes[0].Commit.StatusCheckRollup.State)
+	}
+
+	switch pr.ReviewDecision {
+	case githubv4.PullRequestReviewDecisionApproved:
+		status.Approved = true
+	case "":
+		status.Approved = isApprovedByReviewers(query)
+	}
+
+	return status, nil
+}
+
+// isApprovedByReviewers reports whether at least one reviewer approved the pull request and
+// nobody requested changes.
+func isApprovedByReviewers(query mergeStatusQuery) bool {
+	approved := false
+	for _, review := range query.Repository.PullRequest.LatestOpinionatedReviews.Nodes {
+		switch review.State {
+		case githubv4.PullRequestReviewStateChangesRequested:
+			return false
+		case githubv4.PullRequestReviewStateApproved:
+			approved = true
+		}
+	}
+
+	return approved
+}
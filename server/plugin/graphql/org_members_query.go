@@ -0,0 +1,21 @@
+package graphql
+
+import (
+	"github.com/shurcooL/githubv4"
+)
+
+type orgMembersQuery struct {
+	Organization struct {
+		MembersWithRole struct {
+			Nodes []struct {
+				Login                            githubv4.String
+				Email                            githubv4.String
+				OrganizationVerifiedDomainEmails []githubv4.String `graphql:"organizationVerifiedDomainEmails(login: $org)"`
+			}
+			PageInfo struct {
+				EndCursor   githubv4.String
+				HasNextPage bool
+			}
+		} `graphql:"membersWithRole(first: 100, after: $membersCursor)"`
+	} `graphql:"organization(login: $org)"`
+}
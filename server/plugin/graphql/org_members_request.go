@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	queryParamOrg           = "org"
+	queryParamMembersCursor = "membersCursor"
+)
+
+// OrganizationMember is a member of a GitHub organization with the email addresses visible to the
+// viewer.
+type OrganizationMember struct {
+	Login string
+	// Emails are the public email address of the member, and the addresses verified for the
+	// domains of the organization. The latter are only visible to owners of the organization.
+	Emails []string
+}
+
+// GetOrganizationMembers fetches all members of an organization with their email addresses.
+func (c *Client) GetOrganizationMembers(ctx context.Context, org string) ([]*OrganizationMember, error) {
+	params := map[string]interface{}{
+		queryParamOrg:           githubv4.String(org),
+		queryParamMembersCursor: (*githubv4.String)(nil),
+	}
+
+	var members []*OrganizationMember
+	for {
+		var query orgMembersQuery
+		if err := c.executeQuery(ctx, &query, params); err != nil {
+			return nil, errors.Wrap(err, "Not able to execute the query")
+		}
+
+		for _, node := range query.Organization.MembersWithRole.Nodes {
+			member := &OrganizationMember{Login: string(node.Login)}
+			if node.Email != "" {
+				member.Emails = append(member.Emails, string(node.Email))
+			}
+			for _, email := range node.OrganizationVerifiedDomainEmails {
+				member.Emails = append(member.Emails, string(email))
+			}
+
+			members = append(members, member)
+		}
+
+		if !query.Organization.MembersWithRole.PageInfo.HasNextPage {
+			break
+		}
+
+		params[queryParamMembersCursor] = githubv4.NewString(query.Organization.MembersWithRole.PageInfo.EndCursor)
+	}
+
+	return members, nil
+}
@@ -0,0 +1,48 @@
+package graphql
+
+import (
+	"github.com/shurcooL/githubv4"
+)
+
+type projectItemContent struct {
+	Title      githubv4.String
+	URL        githubv4.URI
+	Number     githubv4.Int
+	Repository struct {
+		NameWithOwner githubv4.String
+		IsPrivate     githubv4.Boolean
+	}
+}
+
+type projectItemQuery struct {
+	Node struct {
+		ProjectV2Item struct {
+			Project struct {
+				Title  githubv4.String
+				URL    githubv4.URI
+				Number githubv4.Int
+			}
+			Content struct {
+				Typename    githubv4.String    `graphql:"__typename"`
+				Issue       projectItemContent `graphql:"... on Issue"`
+				PullRequest projectItemContent `graphql:"... on PullRequest"`
+				DraftIssue  struct {
+					Title githubv4.String
+				} `graphql:"... on DraftIssue"`
+			}
+			FieldValues struct {
+				Nodes []struct {
+					SingleSelectValue struct {
+						Name  githubv4.String
+						Field struct {
+							SingleSelectField struct {
+								ID   githubv4.ID
+								Name githubv4.String
+							} `graphql:"... on ProjectV2SingleSelectField"`
+						}
+					} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+				}
+			} `graphql:"fieldValues(first: 50)"`
+		} `graphql:"... on ProjectV2Item"`
+	} `graphql:"node(id: $id)"`
+}
@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	queryParamID = "id"
+)
+
+// ProjectItemFieldValue is the selected option of a single select field of a project item, like
+// the column of a board.
+type ProjectItemFieldValue struct {
+	FieldID   string
+	FieldName string
+	Name      string
+}
+
+// ProjectItem is an item of a project, which is an issue, a pull request or a draft issue.
+type ProjectItem struct {
+	ProjectTitle  string
+	ProjectURL    string
+	ProjectNumber int
+	// ContentType is either Issue, PullRequest or DraftIssue.
+	ContentType string
+	Title       string
+	// URL, Number and Repository are empty for draft issues.
+	URL        string
+	Number     int
+	Repository string
+	// Private is true for issues and pull requests of private repositories.
+	Private      bool
+	SingleSelect []*ProjectItemFieldValue
+}
+
+// GetProjectItem fetches an item of a project by its node ID, as sent in projects_v2_item events.
+func (c *Client) GetProjectItem(ctx context.Context, nodeID string) (*ProjectItem, error) {
+	params := map[string]interface{}{
+		queryParamID: githubv4.ID(nodeID),
+	}
+
+	var query projectItemQuery
+	if err := c.executeQuery(ctx, &query, params); err != nil {
+		return nil, errors.Wrap(err, "Not able to execute the query")
+	}
+
+	i := query.Node.ProjectV2Item
+	item := &ProjectItem{
+		ProjectTitle:  string(i.Project.Title),
+		ProjectNumber: int(i.Project.Number),
+		ContentType:   string(i.Content.Typename),
+	}
+	if i.Project.URL.URL != nil {
+		item.ProjectURL = i.Project.URL.String()
+	}
+
+	var content projectItemContent
+	switch item.ContentType {
+	case "Issue":
+		content = i.Content.Issue
+	case "PullRequest":
+		content = i.Content.PullRequest
+	case "DraftIssue":
+		content.Title = i.Content.DraftIssue.Title
+	default:
+		return nil, errors.Errorf("project item %s not found", nodeID)
+	}
+
+	item.Title = string(content.Title)
+	item.Number = int(content.Number)
+	item.Repository = string(content.Repository.NameWithOwner)
+	item.Private = bool(content.Repository.IsPrivate)
+	if content.URL.URL != nil {
+		item.URL = content.URL.String()
+	}
+
+	for _, node := range i.FieldValues.Nodes {
+		field := node.SingleSelectValue.Field.SingleSelectField
+		if field.ID == nil {
+			continue
+		}
+
+		item.SingleSelect = append(item.SingleSelect, &ProjectItemFieldValue{
+			FieldID:   fmt.Sprint(field.ID),
+			FieldName: string(field.Name),
+			Name:      string(node.SingleSelectValue.Name),
+		})
+	}
+
+	return item, nil
+}
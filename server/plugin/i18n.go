@@ -0,0 +1,194 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+const (
+	// i18nPath is the path of the translation files within the plugin bundle.
+	i18nPath = "assets/i18n"
+
+	// templateMessagePrefix prefixes the IDs of translated notification templates, e.g.
+	// "template.newPR".
+	templateMessagePrefix = "template."
+
+	defaultLocale = "en"
+)
+
+// loadTranslations reads the translation files named active.<locale>.json from dir.
+//
+// Besides regular messages, translation files may contain translated notification templates.
+// These are returned separately, keyed by locale and template name, as they are parsed like
+// the built-in templates rather than as go-i18n messages.
+func loadTranslations(dir string) (*i18n.Bundle, map[string]map[string]string, error) {
+	bundle := i18n.NewBundle(language.English)
+	unmarshalFuncs := map[string]i18n.UnmarshalFunc{"json": json.Unmarshal}
+	templates := map[string]map[string]string{}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to open i18n directory")
+	}
+
+	for _, file := range files {
+		name := file.Name()
+		// The English messages are defined in the code. active.en.json only serves as the
+		// source for translators.
+		if !strings.HasPrefix(name, "active.") || !strings.HasSuffix(name, ".json") || name == "active.en.json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read message file %s", name)
+		}
+
+		messageFile, err := i18n.ParseMessageFileBytes(data, name, unmarshalFuncs)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse message file %s", name)
+		}
+
+		locale := messageFile.Tag.String()
+		var messages []*i18n.Message
+		for _, message := range messageFile.Messages {
+			templateName := strings.TrimPrefix(message.ID, templateMessagePrefix)
+			if templateName == message.ID {
+				messages = append(messages, message)
+				continue
+			}
+
+			if templates[locale] == nil {
+				templates[locale] = map[string]string{}
+			}
+			templates[locale][templateName] = message.Other
+		}
+
+		if err = bundle.AddMessages(messageFile.Tag, messages...); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to add messages of %s", name)
+		}
+	}
+
+	return bundle, templates, nil
+}
+
+// initTranslations loads the translations shipped with the plugin.
+func (p *Plugin) initTranslations() error {
+	bundlePath, err := p.client.System.GetBundlePath()
+	if err != nil {
+		return errors.Wrap(err, "failed to get bundle path")
+	}
+
+	bundle, templates, err := loadTranslations(filepath.Join(bundlePath, i18nPath))
+	if err != nil {
+		return err
+	}
+
+	p.bundle = bundle
+	setTemplateTranslations(templates)
+	p.loadCustomTemplates(p.getConfiguration())
+
+	return nil
+}
+
+// getServerLocale returns the default locale of the server, used for posts in channels.
+func (p *Plugin) getServerLocale() string {
+	locale := p.client.Configuration.GetConfig().LocalizationSettings.DefaultServerLocale
+	if locale == nil || *locale == "" {
+		return defaultLocale
+	}
+
+	return *locale
+}
+
+// getUserLocale returns the locale of a Mattermost user, falling back to the server locale.
+func (p *Plugin) getUserLocale(userID string) string {
+	user, err := p.client.User.Get(userID)
+	if err != nil {
+		p.client.Log.Debug("Failed to get user's locale", "user_id", userID, "error", err.Error())
+		return p.getServerLocale()
+	}
+
+	if user.Locale == "" {
+		return p.getServerLocale()
+	}
+
+	return user.Locale
+}
+
+// getSubscriptionLocale returns the locale of the notifications of a subscription.
+func (p *Plugin) getSubscriptionLocale(sub *Subscription) string {
+	if sub.Locale() != "" {
+		return sub.Locale()
+	}
+
+	return p.getServerLocale()
+}
+
+func (p *Plugin) getLocalizer(locale string) *i18n.Localizer {
+	return i18n.NewLocalizer(p.bundle, locale)
+}
+
+func (p *Plugin) getUserLocalizer(userID string) *i18n.Localizer {
+	return p.getLocalizer(p.getUserLocale(userID))
+}
+
+// localize returns message in the language of l, falling back to its default text.
+func (p *Plugin) localize(l *i18n.Localizer, message *i18n.Message) string {
+	return p.localizeWithData(l, message, nil)
+}
+
+// localizeWithData is like localize, but executes the message as a template with data.
+func (p *Plugin) localizeWithData(l *i18n.Localizer, message *i18n.Message, data map[string]interface{}) string {
+	return p.localizeConfig(l, &i18n.LocalizeConfig{
+		DefaultMessage: message,
+		TemplateData:   data,
+	})
+}
+
+// localizePlural is like localizeWithData, but picks the plural form of message matching count.
+// count is available to the message as {{.Count}}.
+func (p *Plugin) localizePlural(l *i18n.Localizer, message *i18n.Message, count int, data map[string]interface{}) string {
+	templateData := map[string]interface{}{"Count": count}
+	for key, value := range data {
+		templateData[key] = value
+	}
+
+	return p.localizeConfig(l, &i18n.LocalizeConfig{
+		DefaultMessage: message,
+		PluralCount:    count,
+		TemplateData:   templateData,
+	})
+}
+
+func (p *Plugin) localizeConfig(l *i18n.Localizer, config *i18n.LocalizeConfig) string {
+	message := config.DefaultMessage
+	localized, err := l.Localize(config)
+	// Messages without a translation are rendered from their default text.
+	var notFound *i18n.MessageNotFoundErr
+	if errors.As(err, &notFound) {
+		return localized
+	}
+	if err != nil {
+		p.client.Log.Warn("Failed to localize message", "message_id", message.ID, "error", err.Error())
+		return message.Other
+	}
+
+	return localized
+}
+
+// normalizeLocale validates a locale like "de" or "pt-BR" and returns it in its canonical form.
+func normalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", errors.Errorf("invalid locale %s", locale)
+	}
+
+	return tag.String(), nil
+}
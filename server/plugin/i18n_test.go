@@ -0,0 +1,147 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTranslations(t *testing.T) {
+	t.Run("messages and templates", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "active.en.json"), []byte(`{"greeting": "Hello"}`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "active.de.json"), []byte(`{
+			"greeting": "Hallo",
+			"template.closedIssue": "geschlossen"
+		}`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a message file"), 0600))
+
+		bundle, templates, err := loadTranslations(dir)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]map[string]string{"de": {"closedIssue": "geschlossen"}}, templates)
+
+		greeting := &i18n.Message{ID: "greeting", Other: "Hello"}
+		localized, err := i18n.NewLocalizer(bundle, "de-AT").Localize(&i18n.LocalizeConfig{DefaultMessage: greeting})
+		require.NoError(t, err)
+		assert.Equal(t, "Hallo", localized)
+
+		_, err = i18n.NewLocalizer(bundle, "de").Localize(&i18n.LocalizeConfig{MessageID: templateMessagePrefix + "closedIssue"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "active.de.json"), []byte(`{`), 0600))
+
+		_, _, err := loadTranslations(dir)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, _, err := loadTranslations(filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+
+	t.Run("shipped translations are valid", func(t *testing.T) {
+		_, templates, err := loadTranslations(filepath.Join("..", "..", i18nPath))
+		require.NoError(t, err)
+
+		for locale, sources := range templates {
+			_, err := parseTemplates(sources, isTranslatableTemplate)
+			assert.NoError(t, err, locale)
+		}
+	})
+}
+
+func TestLocalize(t *testing.T) {
+	api := &plugintest.API{}
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+	p.bundle.MustParseMessageFileBytes([]byte(`{
+		"greeting": "Hallo {{.Name}}",
+		"deleted": {"one": "{{.Count}} Abonnement gelöscht", "other": "{{.Count}} Abonnements gelöscht"}
+	}`), "active.de.json")
+
+	greeting := &i18n.Message{ID: "greeting", Other: "Hello {{.Name}}"}
+	deleted := &i18n.Message{ID: "deleted", One: "Deleted {{.Count}} subscription", Other: "Deleted {{.Count}} subscriptions"}
+	untranslated := &i18n.Message{ID: "untranslated", Other: "Untranslated"}
+
+	de := p.getLocalizer("de")
+	en := p.getLocalizer("en")
+
+	assert.Equal(t, "Hallo panda", p.localizeWithData(de, greeting, map[string]interface{}{"Name": "panda"}))
+	assert.Equal(t, "Hello panda", p.localizeWithData(en, greeting, map[string]interface{}{"Name": "panda"}))
+	assert.Equal(t, "Untranslated", p.localize(de, untranslated))
+	assert.Equal(t, "Hello panda", p.localizeWithData(p.getLocalizer("fr"), greeting, map[string]interface{}{"Name": "panda"}))
+
+	assert.Equal(t, "1 Abonnement gelöscht", p.localizePlural(de, deleted, 1, nil))
+	assert.Equal(t, "3 Abonnements gelöscht", p.localizePlural(de, deleted, 3, nil))
+	assert.Equal(t, "Deleted 1 subscription", p.localizePlural(en, deleted, 1, nil))
+	assert.Equal(t, "Deleted 3 subscriptions", p.localizePlural(en, deleted, 3, nil))
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	locale, err := normalizeLocale("pt-br")
+	require.NoError(t, err)
+	assert.Equal(t, "pt-BR", locale)
+
+	_, err = normalizeLocale("not a locale")
+	assert.EqualError(t, err, "invalid locale not a locale")
+}
+
+func TestRenderLocalizedTemplate(t *testing.T) {
+	t.Cleanup(func() {
+		setTemplateTranslations(nil)
+		setCustomTemplates(nil, nil, nil)
+	})
+
+	api := &plugintest.API{}
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	setTemplateTranslations(map[string]map[string]string{
+		"de": {
+			"closedIssue":   "Issue {{.Event.GetIssue.GetNumber}} geschlossen",
+			"reopenedIssue": "Issue {{.Event.GetIssue.GetNumber}} wieder geöffnet",
+		},
+	})
+	p.loadCustomTemplates(&Configuration{
+		NotificationTemplates: `{"reopenedIssue": "reopened {{.Event.GetIssue.GetNumber}}"}`,
+	})
+
+	event := GetEventWithRenderConfig(&github.IssuesEvent{Issue: &github.Issue{Number: iToP(7)}}, nil)
+
+	actual, err := renderLocalizedTemplate("de", "closedIssue", event)
+	require.NoError(t, err)
+	assert.Equal(t, "Issue 7 geschlossen", actual)
+
+	actual, err = renderLocalizedTemplate("de-CH", "closedIssue", event)
+	require.NoError(t, err)
+	assert.Equal(t, "Issue 7 geschlossen", actual)
+
+	// Admin overrides take precedence over translations.
+	actual, err = renderLocalizedTemplate("de", "reopenedIssue", event)
+	require.NoError(t, err)
+	assert.Equal(t, "reopened 7", actual)
+
+	english, err := renderTemplate("closedIssue", event)
+	require.NoError(t, err)
+
+	actual, err = renderLocalizedTemplate("fr", "closedIssue", event)
+	require.NoError(t, err)
+	assert.Equal(t, english, actual)
+
+	actual, err = renderLocalizedTemplate("en", "closedIssue", event)
+	require.NoError(t, err)
+	assert.Equal(t, english, actual)
+}
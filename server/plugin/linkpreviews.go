@@ -0,0 +1,480 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-github/server/plugin/graphql"
+)
+
+// maxLinkPreviews sets the maximum number of issue, pull request, commit and discussion
+// previews attached to a single message.
+const maxLinkPreviews = 5
+
+const (
+	linkPreviewTypeIssue      = "issues"
+	linkPreviewTypePull       = "pull"
+	linkPreviewTypeCommit     = "commit"
+	linkPreviewTypeDiscussion = "discussions"
+)
+
+const (
+	linkPreviewColorOpen   = "#2da44e"
+	linkPreviewColorClosed = "#cf222e"
+	linkPreviewColorMerged = "#8250df"
+	linkPreviewColorDraft  = "#6e7781"
+)
+
+var (
+	stateFieldMessage  = &i18n.Message{ID: "linkPreview.field.state", Other: "State"}
+	ciFieldMessage     = &i18n.Message{ID: "linkPreview.field.ci", Other: "CI"}
+	stateOpenMessage   = &i18n.Message{ID: "linkPreview.state.open", Other: "Open"}
+	stateClosedMessage = &i18n.Message{ID: "linkPreview.state.closed", Other: "Closed"}
+)
+
+// githubObjectLinkRegex matches links to issues, pull requests, commits and discussions.
+var githubObjectLinkRegex = regexp.MustCompile(`https?://(?:www\.)?github\.com/([\w-]+)/([\w-.]+)/(issues|pull|commit|discussions)/(\w+)(?:[/?#][^\s)]*)?`)
+
+// objectLink is a link to a GitHub issue, pull request, commit or discussion found in a message.
+type objectLink struct {
+	word       string
+	owner      string
+	repo       string
+	objectType string
+	id         string
+}
+
+// getObjectLinks returns the distinct issue, pull request, commit and discussion links of a
+// message, in the order they appear in.
+func getObjectLinks(msg string) []objectLink {
+	matches := githubObjectLinkRegex.FindAllStringSubmatch(msg, -1)
+	indices := githubObjectLinkRegex.FindAllStringIndex(msg, -1)
+
+	var links []objectLink
+	seen := map[string]bool{}
+	for i, m := range matches {
+		if len(links) >= maxLinkPreviews {
+			break
+		}
+		// ignore if the link is inside a markdown link
+		if isInsideLink(msg, indices[i][0]) {
+			continue
+		}
+
+		link := objectLink{
+			word:       m[0],
+			owner:      m[1],
+			repo:       m[2],
+			objectType: m[3],
+			id:         m[4],
+		}
+		if link.objectType != linkPreviewTypeCommit {
+			if _, err := strconv.Atoi(link.id); err != nil {
+				continue
+			}
+		}
+
+		key := strings.ToLower(link.owner + "/" + link.repo + "/" + link.objectType + "/" + link.id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// addLinkPreviews attaches a preview of the issues, pull requests, commits and discussions linked
+// in msg to the post. The previews are fetched concurrently with the token of the poster, so they
+// only include data the poster has access to, and in the language of the poster. Previews not
+// fetched before the deadline of ctx are left out.
+func (p *Plugin) addLinkPreviews(ctx context.Context, post *model.Post, msg string, ghClient *github.Client) {
+	links := getObjectLinks(msg)
+	if len(links) == 0 {
+		return
+	}
+
+	l := p.getUserLocalizer(post.UserId)
+	previews := make([]*model.SlackAttachment, len(links))
+	var wg sync.WaitGroup
+	for i, link := range links {
+		wg.Add(1)
+		go func(i int, link objectLink) {
+			defer wg.Done()
+			previews[i] = p.getLinkPreview(ctx, l, link, ghClient)
+		}(i, link)
+	}
+	wg.Wait()
+
+	attachments := post.Attachments()
+	for _, preview := range previews {
+		if preview != nil {
+			attachments = append(attachments, preview)
+		}
+	}
+
+	if len(attachments) > len(post.Attachments()) {
+		model.ParseSlackAttachment(post, attachments)
+	}
+}
+
+func (p *Plugin) getLinkPreview(ctx context.Context, l *i18n.Localizer, link objectLink, ghClient *github.Client) *model.SlackAttachment {
+	config := p.getConfiguration()
+
+	if config.EnableCodePreview != "privateAndPublic" {
+		private, ok := p.isRepoPrivate(ctx, link.owner, link.repo, ghClient)
+		if !ok || private {
+			return nil
+		}
+	}
+
+	var attachment *model.SlackAttachment
+	var err error
+	switch link.objectType {
+	case linkPreviewTypeIssue:
+		attachment, err = p.getIssuePreview(ctx, l, link, ghClient)
+	case linkPreviewTypePull:
+		attachment, err = p.getPullRequestPreview(ctx, l, link, ghClient)
+	case linkPreviewTypeCommit:
+		attachment, err = p.getCommitPreview(ctx, l, link, ghClient)
+	case linkPreviewTypeDiscussion:
+		attachment, err = p.getDiscussionPreview(ctx, l, link, ghClient)
+	}
+	if err != nil {
+		p.client.Log.Warn("Error while fetching link preview", "error", err.Error(), "link", link.word)
+		return nil
+	}
+
+	return attachment
+}
+
+// isRepoPrivate returns whether a repository is private. The second return value is false if
+// the repository could not be fetched.
+func (p *Plugin) isRepoPrivate(ctx context.Context, owner, repo string, ghClient *github.Client) (bool, bool) {
+	key := strings.ToLower(owner + "/" + repo)
+	if cached, ok := p.repoPrivacyCache.Get(key); ok {
+		return cached.(bool), true
+	}
+
+	ghRepo, _, err := ghClient.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		p.client.Log.Warn("Error while fetching repository information", "error", err.Error(), "repo", repo, "user", owner)
+		return false, false
+	}
+
+	p.repoPrivacyCache.Add(key, ghRepo.GetPrivate())
+
+	return ghRepo.GetPrivate(), true
+}
+
+func (p *Plugin) getIssuePreview(ctx context.Context, l *i18n.Localizer, link objectLink, ghClient *github.Client) (*model.SlackAttachment, error) {
+	number, _ := strconv.Atoi(link.id)
+	issue, _, err := ghClient.Issues.Get(ctx, link.owner, link.repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	state := p.localize(l, stateOpenMessage)
+	color := linkPreviewColorOpen
+	if issue.GetState() == "closed" {
+		state = p.localize(l, stateClosedMessage)
+		color = linkPreviewColorClosed
+	}
+
+	attachment := &model.SlackAttachment{
+		Fallback:   fmt.Sprintf("%s/%s#%d: %s", link.owner, link.repo, issue.GetNumber(), issue.GetTitle()),
+		Color:      color,
+		AuthorName: issue.GetUser().GetLogin(),
+		AuthorIcon: issue.GetUser().GetAvatarURL(),
+		AuthorLink: issue.GetUser().GetHTMLURL(),
+		Title:      fmt.Sprintf("#%d %s", issue.GetNumber(), issue.GetTitle()),
+		TitleLink:  issue.GetHTMLURL(),
+		Fields: []*model.SlackAttachmentField{
+			{Title: p.localize(l, stateFieldMessage), Value: state, Short: true},
+		},
+		Footer: fmt.Sprintf("%s/%s", link.owner, link.repo),
+	}
+	addLabelsField(attachment, p.localize(l, labelsFieldMessage), issue.Labels)
+
+	return attachment, nil
+}
+
+func (p *Plugin) getPullRequestPreview(ctx context.Context, l *i18n.Localizer, link objectLink, ghClient *github.Client) (*model.SlackAttachment, error) {
+	number, _ := strconv.Atoi(link.id)
+	pr, _, err := ghClient.PullRequests.Get(ctx, link.owner, link.repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	state := p.localize(l, stateOpenMessage)
+	color := linkPreviewColorOpen
+	switch {
+	case pr.GetMerged():
+		state = p.localize(l, &i18n.Message{ID: "linkPreview.state.merged", Other: "Merged"})
+		color = linkPreviewColorMerged
+	case pr.GetState() == "closed":
+		state = p.localize(l, stateClosedMessage)
+		color = linkPreviewColorClosed
+	case pr.GetDraft():
+		state = p.localize(l, &i18n.Message{ID: "linkPreview.state.draft", Other: "Draft"})
+		color = linkPreviewColorDraft
+	}
+
+	attachment := &model.SlackAttachment{
+		Fallback:   fmt.Sprintf("%s/%s#%d: %s", link.owner, link.repo, pr.GetNumber(), pr.GetTitle()),
+		Color:      color,
+		AuthorName: pr.GetUser().GetLogin(),
+		AuthorIcon: pr.GetUser().GetAvatarURL(),
+		AuthorLink: pr.GetUser().GetHTMLURL(),
+		Title:      fmt.Sprintf("#%d %s", pr.GetNumber(), pr.GetTitle()),
+		TitleLink:  pr.GetHTMLURL(),
+		Fields: []*model.SlackAttachmentField{
+			{Title: p.localize(l, stateFieldMessage), Value: state, Short: true},
+		},
+		Footer: fmt.Sprintf("%s/%s", link.owner, link.repo),
+	}
+
+	if pr.GetState() == "open" {
+		reviews, _, err := ghClient.PullRequests.ListReviews(ctx, link.owner, link.repo, number, &github.ListOptions{PerPage: 100})
+		if err != nil {
+			p.client.Log.Warn("Error while fetching pull request reviews", "error", err.Error(), "link", link.word)
+		} else {
+			attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{
+				Title: p.localize(l, &i18n.Message{ID: "linkPreview.field.reviews", Other: "Reviews"}),
+				Value: p.getReviewStatus(l, reviews),
+				Short: true,
+			})
+		}
+	}
+
+	if ciStatus := p.getCIStatus(ctx, l, link.owner, link.repo, pr.GetHead().GetSHA(), ghClient); ciStatus != "" {
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: p.localize(l, ciFieldMessage), Value: ciStatus, Short: true})
+	}
+
+	addLabelsField(attachment, p.localize(l, labelsFieldMessage), pr.Labels)
+
+	return attachment, nil
+}
+
+func (p *Plugin) getCommitPreview(ctx context.Context, l *i18n.Localizer, link objectLink, ghClient *github.Client) (*model.SlackAttachment, error) {
+	commit, _, err := ghClient.Repositories.GetCommit(ctx, link.owner, link.repo, link.id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	message := commit.GetCommit().GetMessage()
+	title, body, _ := strings.Cut(message, "\n")
+
+	author := commit.GetAuthor().GetLogin()
+	if author == "" {
+		author = commit.GetCommit().GetAuthor().GetName()
+	}
+
+	attachment := &model.SlackAttachment{
+		Fallback:   fmt.Sprintf("%s/%s@%s: %s", link.owner, link.repo, shortSHA(commit.GetSHA()), title),
+		AuthorName: author,
+		AuthorIcon: commit.GetAuthor().GetAvatarURL(),
+		AuthorLink: commit.GetAuthor().GetHTMLURL(),
+		Title:      title,
+		TitleLink:  commit.GetHTMLURL(),
+		Text:       strings.TrimSpace(body),
+		Fields: []*model.SlackAttachmentField{
+			{Title: p.localize(l, &i18n.Message{ID: "linkPreview.field.commit", Other: "Commit"}), Value: fmt.Sprintf("`%s`", shortSHA(commit.GetSHA())), Short: true},
+			{Title: p.localize(l, &i18n.Message{ID: "linkPreview.field.changes", Other: "Changes"}), Value: fmt.Sprintf("+%d −%d", commit.GetStats().GetAdditions(), commit.GetStats().GetDeletions()), Short: true},
+		},
+		Footer: fmt.Sprintf("%s/%s", link.owner, link.repo),
+	}
+
+	if ciStatus := p.getCIStatus(ctx, l, link.owner, link.repo, commit.GetSHA(), ghClient); ciStatus != "" {
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: p.localize(l, ciFieldMessage), Value: ciStatus, Short: true})
+	}
+
+	return attachment, nil
+}
+
+// getCIStatus combines the commit statuses and check runs of a commit into a single status.
+func (p *Plugin) getCIStatus(ctx context.Context, l *i18n.Localizer, owner, repo, sha string, ghClient *github.Client) string {
+	return p.formatCIState(l, p.getCIState(ctx, owner, repo, sha, ghClient))
+}
+
+// getCIState combines the commit statuses and check runs of a commit into one of ciStateSuccess,
+// ciStateFailure and ciStatePending, or an empty string if the commit has no CI.
+func (p *Plugin) getCIState(ctx context.Context, owner, repo, sha string, ghClient *github.Client) string {
+	if sha == "" {
+		return ""
+	}
+
+	var states []string
+
+	status, _, err := ghClient.Repositories.GetCombinedStatus(ctx, owner, repo, sha, nil)
+	if err != nil {
+		p.client.Log.Warn("Error while fetching commit status", "error", err.Error(), "sha", sha)
+	} else if status.GetTotalCount() > 0 {
+		states = append(states, status.GetState())
+	}
+
+	checkRuns, _, err := ghClient.Checks.ListCheckRunsForRef(ctx, owner, repo, sha, &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}})
+	if err != nil {
+		p.client.Log.Warn("Error while fetching check runs", "error", err.Error(), "sha", sha)
+	} else {
+		for _, run := range checkRuns.CheckRuns {
+			switch {
+			case run.GetStatus() != "completed":
+				states = append(states, ciStatePending)
+			case isPassingConclusion(run.GetConclusion()):
+				states = append(states, ciStateSuccess)
+			default:
+				states = append(states, ciStateFailure)
+			}
+		}
+	}
+
+	return combineCIStates(states)
+}
+
+// isPassingConclusion reports whether the conclusion of a completed check run or workflow run
+// doesn't fail the commit.
+func isPassingConclusion(conclusion string) bool {
+	return conclusion == "success" || conclusion == "neutral" || conclusion == "skipped"
+}
+
+func combineCIStates(states []string) string {
+	if len(states) == 0 {
+		return ""
+	}
+
+	pending := false
+	for _, state := range states {
+		switch state {
+		case ciStateFailure, "error":
+			return ciStateFailure
+		case ciStatePending:
+			pending = true
+		}
+	}
+
+	if pending {
+		return ciStatePending
+	}
+
+	return ciStateSuccess
+}
+
+func (p *Plugin) formatCIState(l *i18n.Localizer, state string) string {
+	switch state {
+	case ciStateFailure:
+		return ":x: " + p.localize(l, &i18n.Message{ID: "linkPreview.ci.failing", Other: "Failing"})
+	case ciStatePending:
+		return ":hourglass_flowing_sand: " + p.localize(l, &i18n.Message{ID: "linkPreview.ci.pending", Other: "Pending"})
+	case ciStateSuccess:
+		return ":white_check_mark: " + p.localize(l, &i18n.Message{ID: "linkPreview.ci.passing", Other: "Passing"})
+	}
+
+	return ""
+}
+
+// getDiscussionPreview fetches the discussion with the GraphQL API, as discussions are not part of
+// the REST API. The queries are sent with the HTTP client of ghClient.
+func (p *Plugin) getDiscussionPreview(ctx context.Context, l *i18n.Localizer, link objectLink, ghClient *github.Client) (*model.SlackAttachment, error) {
+	config := p.getConfiguration()
+	graphQLClient := graphql.NewClientWithHTTPClient(p.client.Log, ghClient.Client(), "", config.GitHubOrg, config.EnterpriseBaseURL)
+	if graphQLClient == nil {
+		return nil, errors.New("failed to create GraphQL client")
+	}
+
+	number, _ := strconv.Atoi(link.id)
+	discussion, err := graphQLClient.GetDiscussion(ctx, link.owner, link.repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	state := p.localize(l, stateOpenMessage)
+	color := linkPreviewColorOpen
+	switch {
+	case discussion.Answered:
+		state = p.localize(l, &i18n.Message{ID: "linkPreview.state.answered", Other: "Answered"})
+		color = linkPreviewColorMerged
+	case discussion.Closed:
+		state = p.localize(l, stateClosedMessage)
+		color = linkPreviewColorClosed
+	}
+
+	attachment := &model.SlackAttachment{
+		Fallback:   fmt.Sprintf("%s/%s#%d: %s", link.owner, link.repo, discussion.Number, discussion.Title),
+		Color:      color,
+		AuthorName: discussion.AuthorLogin,
+		AuthorIcon: discussion.AuthorAvatarURL,
+		AuthorLink: discussion.AuthorURL,
+		Title:      fmt.Sprintf("#%d %s", discussion.Number, discussion.Title),
+		TitleLink:  discussion.URL,
+		Fields: []*model.SlackAttachmentField{
+			{Title: p.localize(l, stateFieldMessage), Value: state, Short: true},
+			{Title: p.localize(l, &i18n.Message{ID: "linkPreview.field.category", Other: "Category"}), Value: discussion.Category, Short: true},
+			{Title: p.localize(l, &i18n.Message{ID: "linkPreview.field.comments", Other: "Comments"}), Value: strconv.Itoa(discussion.Comments), Short: true},
+		},
+		Footer: fmt.Sprintf("%s/%s", link.owner, link.repo),
+	}
+
+	var labels []*github.Label
+	for _, label := range discussion.Labels {
+		labels = append(labels, &github.Label{Name: github.String(label)})
+	}
+	addLabelsField(attachment, p.localize(l, labelsFieldMessage), labels)
+
+	return attachment, nil
+}
+
+// getReviewStatus summarizes the latest review of every reviewer.
+func (p *Plugin) getReviewStatus(l *i18n.Localizer, reviews []*github.PullRequestReview) string {
+	latest := map[string]string{}
+	for _, review := range reviews {
+		switch review.GetState() {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[review.GetUser().GetLogin()] = review.GetState()
+		}
+	}
+
+	approvals := 0
+	for _, state := range latest {
+		if state == "CHANGES_REQUESTED" {
+			return p.localize(l, &i18n.Message{ID: "linkPreview.reviews.changesRequested", Other: "Changes requested"})
+		}
+		if state == "APPROVED" {
+			approvals++
+		}
+	}
+
+	if approvals == 0 {
+		return p.localize(l, &i18n.Message{ID: "linkPreview.reviews.required", Other: "Review required"})
+	}
+
+	return p.localizePlural(l, &i18n.Message{
+		ID:    "linkPreview.reviews.approvals",
+		One:   "{{.Count}} approval",
+		Other: "{{.Count}} approvals",
+	}, approvals, nil)
+}
+
+func addLabelsField(attachment *model.SlackAttachment, title string, labels []*github.Label) {
+	if len(labels) == 0 {
+		return
+	}
+
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		names[i] = "`" + label.GetName() + "`"
+	}
+
+	attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: title, Value: strings.Join(names, " "), Short: false})
+}
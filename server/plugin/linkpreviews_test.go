@@ -0,0 +1,195 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetObjectLinks(t *testing.T) {
+	tcs := []struct {
+		name  string
+		input string
+		links []objectLink
+	}{
+		{
+			name:  "issue, pull request and commit",
+			input: "see https://github.com/mattermost/mattermost-server/issues/12, https://github.com/mattermost/mattermost-server/pull/34/files and https://github.com/mattermost/mattermost-server/commit/cbb25838a61872b624ac512556d7bc932486a64c.",
+			links: []objectLink{
+				{word: "https://github.com/mattermost/mattermost-server/issues/12", owner: "mattermost", repo: "mattermost-server", objectType: linkPreviewTypeIssue, id: "12"},
+				{word: "https://github.com/mattermost/mattermost-server/pull/34/files", owner: "mattermost", repo: "mattermost-server", objectType: linkPreviewTypePull, id: "34"},
+				{word: "https://github.com/mattermost/mattermost-server/commit/cbb25838a61872b624ac512556d7bc932486a64c", owner: "mattermost", repo: "mattermost-server", objectType: linkPreviewTypeCommit, id: "cbb25838a61872b624ac512556d7bc932486a64c"},
+			},
+		},
+		{
+			name:  "duplicates",
+			input: "https://github.com/mattermost/mattermost-server/issues/12 https://github.com/Mattermost/Mattermost-Server/issues/12#issuecomment-1",
+			links: []objectLink{
+				{word: "https://github.com/mattermost/mattermost-server/issues/12", owner: "mattermost", repo: "mattermost-server", objectType: linkPreviewTypeIssue, id: "12"},
+			},
+		},
+		{
+			name:  "discussion",
+			input: "https://github.com/mattermost/mattermost-server/discussions/56#discussioncomment-1",
+			links: []objectLink{
+				{word: "https://github.com/mattermost/mattermost-server/discussions/56#discussioncomment-1", owner: "mattermost", repo: "mattermost-server", objectType: linkPreviewTypeDiscussion, id: "56"},
+			},
+		},
+		{
+			name:  "inside link",
+			input: "[the issue](https://github.com/mattermost/mattermost-server/issues/12)",
+		},
+		{
+			name:  "not a number",
+			input: "https://github.com/mattermost/mattermost-server/issues/new",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.links, getObjectLinks(tc.input))
+		})
+	}
+}
+
+func TestGetReviewStatus(t *testing.T) {
+	p := NewPlugin()
+	l := p.getLocalizer("en")
+	review := func(user, state string) *github.PullRequestReview {
+		return &github.PullRequestReview{User: &github.User{Login: github.String(user)}, State: github.String(state)}
+	}
+
+	assert.Equal(t, "Review required", p.getReviewStatus(l, nil))
+	assert.Equal(t, "Review required", p.getReviewStatus(l, []*github.PullRequestReview{review("alice", "COMMENTED")}))
+	assert.Equal(t, "2 approvals", p.getReviewStatus(l, []*github.PullRequestReview{review("alice", "APPROVED"), review("bob", "APPROVED")}))
+	assert.Equal(t, "Changes requested", p.getReviewStatus(l, []*github.PullRequestReview{review("alice", "APPROVED"), review("bob", "CHANGES_REQUESTED")}))
+	assert.Equal(t, "1 approval", p.getReviewStatus(l, []*github.PullRequestReview{review("bob", "CHANGES_REQUESTED"), review("bob", "APPROVED")}), "only the latest review of a reviewer counts")
+}
+
+func TestCombineCIStates(t *testing.T) {
+	assert.Equal(t, "", combineCIStates(nil))
+	assert.Equal(t, "success", combineCIStates([]string{"success", "success"}))
+	assert.Equal(t, "pending", combineCIStates([]string{"success", "pending"}))
+	assert.Equal(t, "failure", combineCIStates([]string{"pending", "error"}))
+
+	p := NewPlugin()
+	l := p.getLocalizer("en")
+	assert.Equal(t, "", p.formatCIState(l, ""))
+	assert.Equal(t, ":white_check_mark: Passing", p.formatCIState(l, "success"))
+	assert.Equal(t, ":hourglass_flowing_sand: Pending", p.formatCIState(l, "pending"))
+	assert.Equal(t, ":x: Failing", p.formatCIState(l, "failure"))
+}
+
+func TestAddLinkPreviews(t *testing.T) {
+	apiHandler := http.NewServeMux()
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"private": false}`)
+	})
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/secret", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"private": true}`)
+	})
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/pulls/34", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"number": 34, "title": "Add link previews", "state": "open", "html_url": "https://github.com/mattermost/mattermost-server/pull/34",
+			"user": {"login": "alice"}, "labels": [{"name": "2: Dev Review"}], "head": {"sha": "abc"}}`)
+	})
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/pulls/34/reviews", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `[{"user": {"login": "bob"}, "state": "APPROVED"}]`)
+	})
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/commits/abc/status", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"state": "success", "total_count": 1}`)
+	})
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/commits/abc/check-runs", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"status": "in_progress"}]}`)
+	})
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/issues/12", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"number": 12, "title": "Broken link", "state": "closed", "html_url": "https://github.com/mattermost/mattermost-server/issues/12", "user": {"login": "carol"}}`)
+	})
+	apiHandler.HandleFunc("/api/graphql", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"data": {"repository": {"discussion": {"number": 56, "title": "Roadmap", "url": "https://github.com/mattermost/mattermost-server/discussions/56",
+			"author": {"login": "dave"}, "category": {"name": "Ideas"}, "answer": null, "comments": {"totalCount": 3}, "closed": false, "labels": {"nodes": []}}}}}`)
+	})
+	server := httptest.NewServer(apiHandler)
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	baseURL, _ := url.Parse(server.URL + baseURLPath + "/")
+	client.BaseURL = baseURL
+
+	setupPlugin := func(codePreview string) *Plugin {
+		p := NewPlugin()
+		p.setConfiguration(&Configuration{EnableCodePreview: codePreview, EnterpriseBaseURL: server.URL})
+		mockPluginAPI := &plugintest.API{}
+		mockPluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPluginAPI.On("GetUser", "user1").Return(&model.User{Id: "user1", Locale: "en"}, nil)
+		p.SetAPI(mockPluginAPI)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p
+	}
+
+	t.Run("issue and pull request", func(t *testing.T) {
+		p := setupPlugin("public")
+		msg := "https://github.com/mattermost/mattermost-server/pull/34 fixes https://github.com/mattermost/mattermost-server/issues/12"
+		post := &model.Post{UserId: "user1", Message: msg}
+
+		p.addLinkPreviews(context.Background(), post, msg, client)
+
+		attachments := post.Attachments()
+		require.Len(t, attachments, 2)
+
+		pr := attachments[0]
+		assert.Equal(t, "#34 Add link previews", pr.Title)
+		assert.Equal(t, "alice", pr.AuthorName)
+		assert.Equal(t, linkPreviewColorOpen, pr.Color)
+		assert.Equal(t, []*model.SlackAttachmentField{
+			{Title: "State", Value: "Open", Short: true},
+			{Title: "Reviews", Value: "1 approval", Short: true},
+			{Title: "CI", Value: ":hourglass_flowing_sand: Pending", Short: true},
+			{Title: "Labels", Value: "`2: Dev Review`", Short: false},
+		}, pr.Fields)
+
+		issue := attachments[1]
+		assert.Equal(t, "#12 Broken link", issue.Title)
+		assert.Equal(t, linkPreviewColorClosed, issue.Color)
+		assert.Equal(t, []*model.SlackAttachmentField{{Title: "State", Value: "Closed", Short: true}}, issue.Fields)
+	})
+
+	t.Run("discussion", func(t *testing.T) {
+		p := setupPlugin("public")
+		msg := "https://github.com/mattermost/mattermost-server/discussions/56"
+		post := &model.Post{UserId: "user1", Message: msg}
+
+		p.addLinkPreviews(context.Background(), post, msg, client)
+
+		attachments := post.Attachments()
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "#56 Roadmap", attachments[0].Title)
+		assert.Equal(t, "dave", attachments[0].AuthorName)
+		assert.Equal(t, []*model.SlackAttachmentField{
+			{Title: "State", Value: "Open", Short: true},
+			{Title: "Category", Value: "Ideas", Short: true},
+			{Title: "Comments", Value: "3", Short: true},
+		}, attachments[0].Fields)
+	})
+
+	t.Run("private repositories are not previewed by default", func(t *testing.T) {
+		p := setupPlugin("public")
+		msg := "https://github.com/mattermost/secret/issues/1"
+		post := &model.Post{UserId: "user1", Message: msg}
+
+		p.addLinkPreviews(context.Background(), post, msg, client)
+
+		assert.Empty(t, post.Attachments())
+	})
+}
@@ -0,0 +1,55 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiringLRU(t *testing.T) {
+	t.Run("evicts least recently used", func(t *testing.T) {
+		cache := newExpiringLRU(2, 0)
+		cache.Add("a", 1)
+		cache.Add("b", 2)
+
+		_, ok := cache.Get("a")
+		assert.True(t, ok)
+
+		cache.Add("c", 3)
+
+		_, ok = cache.Get("b")
+		assert.False(t, ok, "b was the least recently used entry")
+		value, ok := cache.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, value)
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("updates existing entries", func(t *testing.T) {
+		cache := newExpiringLRU(2, 0)
+		cache.Add("a", 1)
+		cache.Add("a", 2)
+
+		value, ok := cache.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 2, value)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("expires entries", func(t *testing.T) {
+		now := time.Now()
+		cache := newExpiringLRU(2, time.Minute)
+		cache.now = func() time.Time { return now }
+		cache.Add("a", 1)
+
+		now = now.Add(30 * time.Second)
+		_, ok := cache.Get("a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Minute)
+		_, ok = cache.Get("a")
+		assert.False(t, ok)
+		assert.Zero(t, cache.Len())
+	})
+}
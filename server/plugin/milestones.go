@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// MilestoneDueOnChange holds the previous due date of an edited milestone, which is nil if the
+// milestone had none.
+type MilestoneDueOnChange struct {
+	From *time.Time `json:"from,omitempty"`
+}
+
+// MilestoneChanges are the changes of an edited milestone.
+type MilestoneChanges struct {
+	Title *github.EditTitle     `json:"title,omitempty"`
+	DueOn *MilestoneDueOnChange `json:"due_on,omitempty"`
+}
+
+// MilestoneEvent is triggered when a milestone is created, closed, opened, edited or deleted.
+// Unlike github.MilestoneEvent, its changes include the previous due date.
+type MilestoneEvent struct {
+	Action    *string              `json:"action,omitempty"`
+	Milestone *github.Milestone    `json:"milestone,omitempty"`
+	Changes   *MilestoneChanges    `json:"changes,omitempty"`
+	Repo      *github.Repository   `json:"repository,omitempty"`
+	Sender    *github.User         `json:"sender,omitempty"`
+	Org       *github.Organization `json:"organization,omitempty"`
+}
+
+func (c *MilestoneDueOnChange) GetFrom() *time.Time {
+	if c == nil {
+		return nil
+	}
+	return c.From
+}
+
+func (c *MilestoneChanges) GetDueOn() *MilestoneDueOnChange {
+	if c == nil {
+		return nil
+	}
+	return c.DueOn
+}
+
+func (e *MilestoneEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *MilestoneEvent) GetMilestone() *github.Milestone {
+	if e == nil {
+		return nil
+	}
+	return e.Milestone
+}
+
+func (e *MilestoneEvent) GetChanges() *MilestoneChanges {
+	if e == nil {
+		return nil
+	}
+	return e.Changes
+}
+
+func (e *MilestoneEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *MilestoneEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetProgress returns the percentage of closed issues and pull requests of the milestone.
+func (e *MilestoneEvent) GetProgress() int {
+	closed := e.GetMilestone().GetClosedIssues()
+	total := closed + e.GetMilestone().GetOpenIssues()
+	if total == 0 {
+		return 0
+	}
+
+	return closed * 100 / total
+}
+
+func (p *Plugin) postMilestoneEvent(event *MilestoneEvent) {
+	var milestoneTemplate string
+	switch event.GetAction() {
+	case actionCreated:
+		milestoneTemplate = "newMilestone"
+	case actionClosed:
+		milestoneTemplate = "closedMilestone"
+	case actionEdited:
+		if event.GetChanges().GetDueOn() == nil {
+			return
+		}
+		milestoneTemplate = "milestoneDueDateChanged"
+	default:
+		return
+	}
+
+	subs := p.GetSubscribedChannelsForRepository(event.GetRepo())
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Milestones() {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), milestoneTemplate, event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_milestone",
+			Message:   message,
+			ChannelId: sub.ChannelID,
+		}
+		p.createSubscriptionPost(sub, post, event)
+	}
+}
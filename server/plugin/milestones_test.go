@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const milestoneDueDatePayload = `{
+	"action": "edited",
+	"milestone": {
+		"title": "v1.2.0",
+		"html_url": "https://github.com/mattermost/mattermost-plugin-github/milestone/4",
+		"open_issues": 1,
+		"closed_issues": 3,
+		"due_on": "2022-03-01T08:00:00Z"
+	},
+	"changes": {"due_on": {"from": "2022-02-15T08:00:00Z"}},
+	"repository": {"full_name": "mattermost/mattermost-plugin-github", "html_url": "https://github.com/mattermost/mattermost-plugin-github"},
+	"sender": {"login": "panda", "html_url": "https://github.com/panda"}
+}`
+
+func TestMilestoneEventProgress(t *testing.T) {
+	event, err := parseWebhook("milestone", []byte(milestoneDueDatePayload))
+	require.NoError(t, err)
+
+	milestoneEvent, ok := event.(*MilestoneEvent)
+	require.True(t, ok)
+	assert.Equal(t, 75, milestoneEvent.GetProgress())
+	assert.Equal(t, "2022-02-15", milestoneEvent.GetChanges().GetDueOn().GetFrom().Format("2006-01-02"))
+
+	assert.Equal(t, 0, (&MilestoneEvent{}).GetProgress())
+}
+
+func TestPostMilestoneEvent(t *testing.T) {
+	event, err := parseWebhook("milestone", []byte(milestoneDueDatePayload))
+	require.NoError(t, err)
+	milestoneEvent := event.(*MilestoneEvent)
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/mattermost-plugin-github": {
+			{ChannelID: "channel1", Features: "milestones", Repository: "mattermost/mattermost-plugin-github"},
+			{ChannelID: "channel2", Features: "pulls,issues", Repository: "mattermost/mattermost-plugin-github"},
+		},
+	}}
+	p, api := setupSubscriptionTestPlugin(t, subs)
+
+	p.postMilestoneEvent(milestoneEvent)
+
+	// Edits not changing the due date are not posted.
+	milestoneEvent.Changes = &MilestoneChanges{}
+	p.postMilestoneEvent(milestoneEvent)
+
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+	api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "channel1" && post.Type == "custom_git_milestone"
+	}))
+}
@@ -0,0 +1,135 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	mutesKeySuffix = "-mutes"
+	// legacyMutedUsersKeySuffix is the key of the comma separated list of muted users, which
+	// preceded the mutes.
+	legacyMutedUsersKeySuffix = "-muted-users"
+)
+
+// Mutes filter the direct message notifications of a user.
+type Mutes struct {
+	// Users are GitHub users whose comments don't trigger notifications.
+	Users []string `json:"users,omitempty"`
+	// Repositories are owner/name of repositories that don't trigger notifications.
+	Repositories []string `json:"repositories,omitempty"`
+	// Labels are the labels of pull requests and issues that don't trigger notifications.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// IsEmpty reports whether nothing is muted.
+func (m *Mutes) IsEmpty() bool {
+	return len(m.Users) == 0 && len(m.Repositories) == 0 && len(m.Labels) == 0
+}
+
+// MutesRepository reports whether notifications about the repository with the given full name are
+// muted.
+func (m *Mutes) MutesRepository(repository string) bool {
+	return containsFold(m.Repositories, repository)
+}
+
+// MutesLabels reports whether notifications about a pull request or issue with the given labels are
+// muted.
+func (m *Mutes) MutesLabels(labels []*github.Label) bool {
+	for _, label := range labels {
+		if containsFold(m.Labels, label.GetName()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeFold returns values without value, ignoring case.
+func removeFold(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if !strings.EqualFold(v, value) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// getMutes returns the mutes of a user. Users muted before the mutes existed are read from the
+// legacy list.
+func (p *Plugin) getMutes(userID string) (*Mutes, error) {
+	var mutes *Mutes
+	if err := p.client.KV.Get(userID+mutesKeySuffix, &mutes); err != nil {
+		return nil, errors.Wrap(err, "failed to get mutes")
+	}
+	if mutes != nil {
+		return mutes, nil
+	}
+
+	var mutedUsernameBytes []byte
+	if err := p.client.KV.Get(userID+legacyMutedUsersKeySuffix, &mutedUsernameBytes); err != nil {
+		return nil, errors.Wrap(err, "failed to get muted users")
+	}
+
+	mutes = &Mutes{}
+	if len(mutedUsernameBytes) > 0 {
+		// , is a character not allowed in GitHub usernames
+		mutes.Users = strings.Split(string(mutedUsernameBytes), ",")
+	}
+
+	return mutes, nil
+}
+
+// storeMutes stores the mutes of a user, replacing the legacy list of muted users.
+func (p *Plugin) storeMutes(userID string, mutes *Mutes) error {
+	if mutes.IsEmpty() {
+		if err := p.client.KV.Delete(userID + mutesKeySuffix); err != nil {
+			return errors.Wrap(err, "failed to delete mutes")
+		}
+	} else if _, err := p.client.KV.Set(userID+mutesKeySuffix, mutes); err != nil {
+		return errors.Wrap(err, "failed to store mutes")
+	}
+
+	if err := p.client.KV.Delete(userID + legacyMutedUsersKeySuffix); err != nil {
+		return errors.Wrap(err, "failed to delete muted users")
+	}
+
+	return nil
+}
+
+// notificationMutedByReceiver reports whether the user muted notifications about the given
+// repository or labels.
+func (p *Plugin) notificationMutedByReceiver(userID, repository string, labels []*github.Label) bool {
+	mutes, err := p.getMutes(userID)
+	if err != nil {
+		p.client.Log.Warn("Failed to get mutes", "userID", userID, "error", err.Error())
+		return false
+	}
+
+	return mutes.MutesRepository(repository) || mutes.MutesLabels(labels)
+}
+
+// filterMutedReceiver returns userID, or an empty string if the user muted notifications about the
+// given repository or labels.
+func (p *Plugin) filterMutedReceiver(userID, repository string, labels []*github.Label) string {
+	if userID == "" || p.notificationMutedByReceiver(userID, repository, labels) {
+		return ""
+	}
+
+	return userID
+}
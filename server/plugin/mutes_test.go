@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutes(t *testing.T) {
+	mutes := &Mutes{
+		Repositories: []string{"mattermost/mattermost-server"},
+		Labels:       []string{"dependencies"},
+	}
+
+	assert.False(t, mutes.IsEmpty())
+	assert.True(t, (&Mutes{}).IsEmpty())
+
+	assert.True(t, mutes.MutesRepository("Mattermost/mattermost-server"))
+	assert.False(t, mutes.MutesRepository("mattermost/mattermost-plugin-github"))
+
+	assert.True(t, mutes.MutesLabels([]*github.Label{{Name: sToP("bug")}, {Name: sToP("Dependencies")}}))
+	assert.False(t, mutes.MutesLabels([]*github.Label{{Name: sToP("bug")}}))
+	assert.False(t, mutes.MutesLabels(nil))
+}
+
+func TestGetMutes(t *testing.T) {
+	setupPlugin := func(api *plugintest.API) *Plugin {
+		p := NewPlugin()
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p
+	}
+
+	t.Run("stored mutes", func(t *testing.T) {
+		data, err := json.Marshal(&Mutes{Users: []string{"dependabot"}, Labels: []string{"dependencies"}})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("KVGet", "user1"+mutesKeySuffix).Return(data, nil)
+		p := setupPlugin(api)
+
+		mutes, err := p.getMutes("user1")
+		require.NoError(t, err)
+		assert.Equal(t, &Mutes{Users: []string{"dependabot"}, Labels: []string{"dependencies"}}, mutes)
+	})
+
+	t.Run("legacy muted users", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", "user1"+mutesKeySuffix).Return(nil, nil)
+		api.On("KVGet", "user1"+legacyMutedUsersKeySuffix).Return([]byte("dependabot,renovate"), nil)
+		p := setupPlugin(api)
+
+		mutes, err := p.getMutes("user1")
+		require.NoError(t, err)
+		assert.Equal(t, &Mutes{Users: []string{"dependabot", "renovate"}}, mutes)
+	})
+
+	t.Run("nothing muted", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", "user1"+mutesKeySuffix).Return(nil, nil)
+		api.On("KVGet", "user1"+legacyMutedUsersKeySuffix).Return(nil, nil)
+		p := setupPlugin(api)
+
+		mutes, err := p.getMutes("user1")
+		require.NoError(t, err)
+		assert.True(t, mutes.IsEmpty())
+	})
+
+	t.Run("storing replaces legacy muted users", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", "user1"+mutesKeySuffix, mock.Anything, model.PluginKVSetOptions{}).Return(true, nil)
+		api.On("KVSetWithOptions", "user1"+legacyMutedUsersKeySuffix, []byte(nil), model.PluginKVSetOptions{}).Return(true, nil)
+		p := setupPlugin(api)
+
+		require.NoError(t, p.storeMutes("user1", &Mutes{Repositories: []string{"mattermost/mattermost-server"}}))
+
+		api.AssertCalled(t, "KVSetWithOptions", "user1"+mutesKeySuffix, mock.MatchedBy(func(data []byte) bool {
+			var mutes Mutes
+			return json.Unmarshal(data, &mutes) == nil && assert.ObjectsAreEqual(Mutes{Repositories: []string{"mattermost/mattermost-server"}}, mutes)
+		}), model.PluginKVSetOptions{})
+		api.AssertCalled(t, "KVSetWithOptions", "user1"+legacyMutedUsersKeySuffix, []byte(nil), model.PluginKVSetOptions{})
+	})
+
+	t.Run("muted repository and label", func(t *testing.T) {
+		data, err := json.Marshal(&Mutes{Repositories: []string{"mattermost/mattermost-server"}, Labels: []string{"dependencies"}})
+		require.NoError(t, err)
+
+		api := &plugintest.API{}
+		api.On("KVGet", "user1"+mutesKeySuffix).Return(data, nil)
+		p := setupPlugin(api)
+
+		assert.True(t, p.notificationMutedByReceiver("user1", "mattermost/mattermost-server", nil))
+		assert.True(t, p.notificationMutedByReceiver("user1", "mattermost/mattermost-plugin-github", []*github.Label{{Name: sToP("dependencies")}}))
+		assert.False(t, p.notificationMutedByReceiver("user1", "mattermost/mattermost-plugin-github", []*github.Label{{Name: sToP("bug")}}))
+
+		assert.Equal(t, "", p.filterMutedReceiver("user1", "mattermost/mattermost-server", nil))
+		assert.Equal(t, "user1", p.filterMutedReceiver("user1", "mattermost/mattermost-plugin-github", nil))
+		assert.Equal(t, "", p.filterMutedReceiver("", "mattermost/mattermost-plugin-github", nil))
+	})
+}
+
+func TestHandleUnmuteRepository(t *testing.T) {
+	data, err := json.Marshal(&Mutes{Repositories: []string{"mattermost/mattermost-server", "mattermost/mattermost-plugin-github"}})
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("GetUser", "user1").Return(&model.User{Id: "user1", Locale: "en"}, nil)
+	api.On("KVGet", "user1"+mutesKeySuffix).Return(data, nil)
+	api.On("KVSetWithOptions", "user1"+mutesKeySuffix, mock.Anything, model.PluginKVSetOptions{}).Return(true, nil)
+	api.On("KVSetWithOptions", "user1"+legacyMutedUsersKeySuffix, []byte(nil), model.PluginKVSetOptions{}).Return(true, nil)
+	p := NewPlugin()
+	p.setConfiguration(&Configuration{})
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	// The repository is normalized like when muting it.
+	response := p.handleUnmuteRepository(&model.CommandArgs{UserId: "user1"}, "https://github.com/Mattermost/mattermost-server", &GitHubUserInfo{UserID: "user1"})
+	assert.Equal(t, "`Mattermost/mattermost-server` is no longer muted", response)
+
+	api.AssertCalled(t, "KVSetWithOptions", "user1"+mutesKeySuffix, mock.MatchedBy(func(data []byte) bool {
+		var mutes Mutes
+		return json.Unmarshal(data, &mutes) == nil && assert.ObjectsAreEqual(Mutes{Repositories: []string{"mattermost/mattermost-plugin-github"}}, mutes)
+	}), model.PluginKVSetOptions{})
+
+	assert.Equal(t, "Please specify a repository as owner/name.", p.handleUnmuteRepository(&model.CommandArgs{UserId: "user1"}, "mattermost", &GitHubUserInfo{UserID: "user1"}))
+}
+
+func TestParseLabelArgument(t *testing.T) {
+	assert.Equal(t, "dependencies", parseLabelArgument([]string{"dependencies"}))
+	assert.Equal(t, "good first issue", parseLabelArgument([]string{`"good first issue"`}))
+	assert.Equal(t, "good first issue", parseLabelArgument([]string{"good", "first", "issue"}))
+}
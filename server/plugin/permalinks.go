@@ -3,8 +3,10 @@ package plugin
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v41/github"
@@ -14,13 +16,24 @@ import (
 // permalink replacements that can be performed on a single message.
 const maxPermalinkReplacements = 10
 
+// permalinkReqTimeout bounds the time spent fetching the code and link previews of a single message.
 const permalinkReqTimeout = 5 * time.Second
 
-// maxPreviewLines sets the maximum number of preview lines that will be shown
+// permalinkContentCacheSize sets the number of files kept in memory. The contents of a file at a
+// commit never change, so entries are only evicted when the cache is full.
+const permalinkContentCacheSize = 256
+
+// repoPrivacyCacheSize sets the number of repositories whose visibility is kept in memory.
+const repoPrivacyCacheSize = 256
+
+// repoPrivacyTTL sets how long the visibility of a repository is cached.
+const repoPrivacyTTL = 5 * time.Minute
+
+// maxPreviewLines sets the default maximum number of preview lines that will be shown
 // while replacing a permalink.
 const maxPreviewLines = 10
 
-// permalinkLineContext shows the number of lines before and after to show
+// permalinkLineContext sets the default number of lines to show before and after
 // if the link points to a single line.
 const permalinkLineContext = 3
 
@@ -88,82 +101,326 @@ func (p *Plugin) getReplacements(msg string) []replacement {
 
 // makeReplacements perform the given replacements on the msg and returns
 // the new msg. The replacements slice needs to be sorted by the index in ascending order.
-func (p *Plugin) makeReplacements(msg string, replacements []replacement, ghClient *github.Client) string {
+// Repositories and files are fetched concurrently, all within the deadline of ctx.
+func (p *Plugin) makeReplacements(ctx context.Context, msg string, replacements []replacement, ghClient *github.Client) string {
 	config := p.getConfiguration()
 
-	// iterating the slice in reverse to preserve the replacement indices.
-	for i := len(replacements) - 1; i >= 0; i-- {
-		r := replacements[i]
-		// quick bailout if the commit hash is not proper.
-		if _, err := hex.DecodeString(r.permalinkInfo.commit); err != nil {
-			p.client.Log.Warn("Bad git commit hash in permalink", "error", err.Error(), "hash", r.permalinkInfo.commit)
+	private := p.getPermalinkRepoVisibility(ctx, replacements, ghClient)
+	commits := p.resolvePermalinkRefs(ctx, replacements, private, ghClient)
+
+	// Branches and tags are pinned to the commit they currently point to.
+	var valid []replacement
+	var refs []string
+	for _, r := range replacements {
+		ref := r.permalinkInfo.commit
+		sha, ok := commits[permalinkRefKey(r)]
+		if !ok {
 			continue
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), permalinkReqTimeout)
-		defer cancel()
+		r.permalinkInfo.commit = sha
+		valid = append(valid, r)
+		refs = append(refs, ref)
+	}
 
-		// Check if repo is public
-		if config.EnableCodePreview != "privateAndPublic" {
-			repo, _, err := ghClient.Repositories.Get(ctx, r.permalinkInfo.user, r.permalinkInfo.repo)
-			if err != nil {
-				p.client.Log.Warn("Error while fetching repository information",
-					"error", err.Error(),
-					"repo", r.permalinkInfo.repo,
-					"user", r.permalinkInfo.user)
-				continue
-			}
+	files := p.getPermalinkFiles(ctx, valid, private, ghClient)
 
-			if repo.GetPrivate() {
-				continue
-			}
+	// iterating the slice in reverse to preserve the replacement indices.
+	for i := len(valid) - 1; i >= 0; i-- {
+		r := valid[i]
+
+		decoded, ok := files[permalinkFileKey(r)]
+		if !ok {
+			continue
 		}
 
-		// get the file contents
-		opts := github.RepositoryContentGetOptions{
-			Ref: r.permalinkInfo.commit,
+		link := r.word
+		label := ""
+		if !strings.HasPrefix(r.permalinkInfo.commit, refs[i]) {
+			link = strings.Replace(r.word, "/blob/"+refs[i]+"/", "/blob/"+r.permalinkInfo.commit+"/", 1)
+			label = fmt.Sprintf("`%s` at `%s`", refs[i], shortSHA(r.permalinkInfo.commit))
 		}
-		// TODO: make all of these requests concurrently.
-		fileContent, _, _, err := ghClient.Repositories.GetContents(ctx,
-			r.permalinkInfo.user, r.permalinkInfo.repo, r.permalinkInfo.path, &opts)
+
+		// get the required lines.
+		lines, isTruncated, err := getPreviewLines(decoded, r.permalinkInfo.line, config.getCodePreviewMaxLines(), config.getCodePreviewLineContext())
 		if err != nil {
-			p.client.Log.Warn("Error while fetching file contents", "error", err.Error(), "path", r.permalinkInfo.path)
-			continue
+			p.client.Log.Warn("Error while filtering lines", "error", err.Error(), "path", r.permalinkInfo.path)
 		}
-		// this is not a file, ignore.
-		if fileContent == nil {
-			p.client.Log.Warn("Permalink is not a file", "file", r.permalinkInfo.path)
+		if lines == "" {
+			p.client.Log.Warn("No lines to preview. Skipping.", "file", r.permalinkInfo.path, "anchor", r.permalinkInfo.line)
 			continue
 		}
-		decoded, err := fileContent.GetContent()
-		if err != nil {
-			p.client.Log.Warn("Error while decoding file contents", "error", err.Error(), "path", r.permalinkInfo.path)
-			continue
+		final := getCodeMarkdown(r.permalinkInfo.user, r.permalinkInfo.repo, r.permalinkInfo.path, link, label, lines, isTruncated)
+
+		// replace word in msg starting from r.index only once.
+		msg = msg[:r.index] + strings.Replace(msg[r.index:], r.word, final, 1)
+	}
+	return msg
+}
+
+// getPreviewLines returns the lines of content selected by the anchor of a permalink. Each
+// comma-separated range of the anchor is included, separated by an ellipsis, for up to maxLines
+// lines in total. Without an anchor, the whole content is returned if it has at most maxLines
+// lines. An empty string is returned if the anchor is invalid or out of range.
+func getPreviewLines(content, anchor string, maxLines, lineContext int) (string, bool, error) {
+	if anchor == "" {
+		if content == "" || strings.Count(strings.TrimSuffix(content, "\n"), "\n") >= maxLines {
+			return "", false, nil
 		}
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content, false, nil
+	}
 
-		// get the required lines.
-		start, end := getLineNumbers(r.permalinkInfo.line)
+	var preview strings.Builder
+	remaining := maxLines
+	isTruncated := false
+
+	ranges := strings.Split(anchor, ",")
+	for i, lineRange := range ranges {
+		if remaining <= 0 {
+			isTruncated = true
+			break
+		}
+
+		start, end := getLineNumbers(lineRange, lineContext)
 		// bad anchor tag, ignore.
 		if start == -1 || end == -1 {
-			continue
+			return "", false, nil
 		}
-		isTruncated := false
-		if end-start > maxPreviewLines {
-			end = start + maxPreviewLines
+		if end-start > remaining {
+			end = start + remaining
 			isTruncated = true
 		}
-		lines, err := filterLines(decoded, start, end)
+
+		lines, err := filterLines(content, start, end)
 		if err != nil {
-			p.client.Log.Warn("Error while filtering lines", "error", err.Error(), "path", r.permalinkInfo.path)
+			return "", false, err
 		}
 		if lines == "" {
-			p.client.Log.Warn("Line numbers out of range. Skipping.", "file", r.permalinkInfo.path, "start", start, "end", end)
+			return "", false, nil
+		}
+
+		if i > 0 {
+			preview.WriteString("...\n")
+		}
+		preview.WriteString(lines)
+		remaining -= strings.Count(lines, "\n")
+	}
+
+	return preview.String(), isTruncated, nil
+}
+
+func permalinkRepoKey(r replacement) string {
+	return strings.ToLower(r.permalinkInfo.user + "/" + r.permalinkInfo.repo)
+}
+
+func permalinkRefKey(r replacement) string {
+	return permalinkRepoKey(r) + "@" + r.permalinkInfo.commit
+}
+
+func permalinkFileKey(r replacement) string {
+	return permalinkRepoKey(r) + "/" + strings.ToLower(r.permalinkInfo.commit) + "/" + r.permalinkInfo.path
+}
+
+// getPermalinkRepoVisibility returns whether the repositories of the replacements are private,
+// keyed by permalinkRepoKey. Repositories that could not be fetched are missing from the result.
+func (p *Plugin) getPermalinkRepoVisibility(ctx context.Context, replacements []replacement, ghClient *github.Client) map[string]bool {
+	private := map[string]bool{}
+	requested := map[string]bool{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, r := range replacements {
+		key := permalinkRepoKey(r)
+		if requested[key] {
 			continue
 		}
-		final := getCodeMarkdown(r.permalinkInfo.user, r.permalinkInfo.repo, r.permalinkInfo.path, r.word, lines, isTruncated)
+		requested[key] = true
 
-		// replace word in msg starting from r.index only once.
-		msg = msg[:r.index] + strings.Replace(msg[r.index:], r.word, final, 1)
+		wg.Add(1)
+		go func(r replacement, key string) {
+			defer wg.Done()
+
+			isPrivate, ok := p.isRepoPrivate(ctx, r.permalinkInfo.user, r.permalinkInfo.repo, ghClient)
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			private[key] = isPrivate
+			mu.Unlock()
+		}(r, key)
 	}
-	return msg
+
+	wg.Wait()
+
+	return private
+}
+
+// isPermalinkRepoAllowed reports whether the repository of a replacement may be previewed, and
+// whether its contents may be cached.
+func isPermalinkRepoAllowed(config *Configuration, private map[string]bool, r replacement) (bool, bool) {
+	isPrivate, known := private[permalinkRepoKey(r)]
+	if config.EnableCodePreview != "privateAndPublic" && (!known || isPrivate) {
+		return false, false
+	}
+
+	return true, known && !isPrivate
+}
+
+// resolvePermalinkRefs returns the commit SHA each replacement refers to, keyed by
+// permalinkRefKey. Commit hashes map to themselves, while branches and tags are resolved to the
+// commit they currently point to. Refs that could not be resolved are missing from the result.
+func (p *Plugin) resolvePermalinkRefs(ctx context.Context, replacements []replacement, private map[string]bool, ghClient *github.Client) map[string]string {
+	config := p.getConfiguration()
+
+	commits := map[string]string{}
+	requested := map[string]bool{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, r := range replacements {
+		key := permalinkRefKey(r)
+		if requested[key] {
+			continue
+		}
+		requested[key] = true
+
+		if isCommitSHA(r.permalinkInfo.commit) {
+			mu.Lock()
+			commits[key] = r.permalinkInfo.commit
+			mu.Unlock()
+			continue
+		}
+
+		if allowed, _ := isPermalinkRepoAllowed(config, private, r); !allowed {
+			continue
+		}
+
+		wg.Add(1)
+		go func(r replacement, key string) {
+			defer wg.Done()
+
+			sha, _, err := ghClient.Repositories.GetCommitSHA1(ctx, r.permalinkInfo.user, r.permalinkInfo.repo, r.permalinkInfo.commit, "")
+			if err != nil {
+				p.client.Log.Warn("Error while resolving permalink ref", "error", err.Error(), "ref", r.permalinkInfo.commit)
+				return
+			}
+
+			sha = strings.TrimSpace(sha)
+			if !isCommitSHA(sha) {
+				p.client.Log.Warn("Permalink ref did not resolve to a commit", "ref", r.permalinkInfo.commit)
+				return
+			}
+
+			mu.Lock()
+			commits[key] = sha
+			mu.Unlock()
+		}(r, key)
+	}
+
+	wg.Wait()
+
+	return commits
+}
+
+// isCommitSHA reports whether ref is a full commit hash, as opposed to a branch or tag name.
+func isCommitSHA(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+
+	_, err := hex.DecodeString(ref)
+	return err == nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+
+	return sha
+}
+
+// getPermalinkFiles returns the decoded contents of the files linked by the replacements, keyed
+// by permalinkFileKey. Only files of public repositories are cached, as the cache is shared by
+// all users.
+func (p *Plugin) getPermalinkFiles(ctx context.Context, replacements []replacement, private map[string]bool, ghClient *github.Client) map[string]string {
+	config := p.getConfiguration()
+
+	files := map[string]string{}
+	requested := map[string]bool{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, r := range replacements {
+		key := permalinkFileKey(r)
+		if requested[key] {
+			continue
+		}
+		requested[key] = true
+
+		allowed, cacheable := isPermalinkRepoAllowed(config, private, r)
+		if !allowed {
+			continue
+		}
+
+		if cacheable {
+			if cached, ok := p.permalinkContentCache.Get(key); ok {
+				mu.Lock()
+				files[key] = cached.(string)
+				mu.Unlock()
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(r replacement, key string) {
+			defer wg.Done()
+
+			decoded, ok := p.getPermalinkFile(ctx, r, ghClient)
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			files[key] = decoded
+			mu.Unlock()
+
+			if cacheable {
+				p.permalinkContentCache.Add(key, decoded)
+			}
+		}(r, key)
+	}
+
+	wg.Wait()
+
+	return files
+}
+
+func (p *Plugin) getPermalinkFile(ctx context.Context, r replacement, ghClient *github.Client) (string, bool) {
+	// get the file contents
+	opts := github.RepositoryContentGetOptions{
+		Ref: r.permalinkInfo.commit,
+	}
+	fileContent, _, _, err := ghClient.Repositories.GetContents(ctx,
+		r.permalinkInfo.user, r.permalinkInfo.repo, r.permalinkInfo.path, &opts)
+	if err != nil {
+		p.client.Log.Warn("Error while fetching file contents", "error", err.Error(), "path", r.permalinkInfo.path)
+		return "", false
+	}
+	// this is not a file, ignore.
+	if fileContent == nil {
+		p.client.Log.Warn("Permalink is not a file", "file", r.permalinkInfo.path)
+		return "", false
+	}
+	decoded, err := fileContent.GetContent()
+	if err != nil {
+		p.client.Log.Warn("Error while decoding file contents", "error", err.Error(), "path", r.permalinkInfo.path)
+		return "", false
+	}
+
+	return decoded, true
 }
@@ -1,10 +1,13 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-github/v41/github"
@@ -222,6 +225,49 @@ func TestGetReplacements(t *testing.T) {
 					},
 				},
 			},
+		}, {
+			name:            "branch and tag",
+			input:           "branch https://github.com/mattermost/mattermost-server/blob/master/app/authentication.go#L15-L22 tag https://github.com/mattermost/mattermost-server/blob/v5.0.0/app/authentication.go#L15-L22",
+			numReplacements: 2,
+			replacements: []replacement{
+				{
+					index: 7,
+					word:  "https://github.com/mattermost/mattermost-server/blob/master/app/authentication.go#L15-L22",
+					permalinkInfo: struct {
+						haswww string
+						commit string
+						user   string
+						repo   string
+						path   string
+						line   string
+					}{
+						commit: "master",
+						haswww: "",
+						line:   "L15-L22",
+						path:   "app/authentication.go",
+						user:   "mattermost",
+						repo:   "mattermost-server",
+					},
+				}, {
+					index: 101,
+					word:  "https://github.com/mattermost/mattermost-server/blob/v5.0.0/app/authentication.go#L15-L22",
+					permalinkInfo: struct {
+						haswww string
+						commit string
+						user   string
+						repo   string
+						path   string
+						line   string
+					}{
+						commit: "v5.0.0",
+						haswww: "",
+						line:   "L15-L22",
+						path:   "app/authentication.go",
+						user:   "mattermost",
+						repo:   "mattermost-server",
+					},
+				},
+			},
 		}, {
 			name:            "single line",
 			input:           "this is a one line permalink https://github.com/mattermost/mattermost-server/blob/4225977966cf0855c8a5e55f8a0fef702b19dc18/api4/bot.go#L16",
@@ -247,6 +293,56 @@ func TestGetReplacements(t *testing.T) {
 					},
 				},
 			},
+		}, {
+			name:            "single line followed by a comma",
+			input:           "see https://github.com/mattermost/mattermost-server/blob/4225977966cf0855c8a5e55f8a0fef702b19dc18/api4/bot.go#L16, for details",
+			numReplacements: 1,
+			replacements: []replacement{
+				{
+					index: 4,
+					word:  "https://github.com/mattermost/mattermost-server/blob/4225977966cf0855c8a5e55f8a0fef702b19dc18/api4/bot.go#L16",
+					permalinkInfo: struct {
+						haswww string
+						commit string
+						user   string
+						repo   string
+						path   string
+						line   string
+					}{
+						commit: "4225977966cf0855c8a5e55f8a0fef702b19dc18",
+						haswww: "",
+						line:   "L16",
+						path:   "api4/bot.go",
+						user:   "mattermost",
+						repo:   "mattermost-server",
+					},
+				},
+			},
+		}, {
+			name:            "link to a whole file",
+			input:           "see https://github.com/mattermost/mattermost-server/blob/4225977966cf0855c8a5e55f8a0fef702b19dc18/api4/bot.go, for details",
+			numReplacements: 1,
+			replacements: []replacement{
+				{
+					index: 4,
+					word:  "https://github.com/mattermost/mattermost-server/blob/4225977966cf0855c8a5e55f8a0fef702b19dc18/api4/bot.go",
+					permalinkInfo: struct {
+						haswww string
+						commit string
+						user   string
+						repo   string
+						path   string
+						line   string
+					}{
+						commit: "4225977966cf0855c8a5e55f8a0fef702b19dc18",
+						haswww: "",
+						line:   "",
+						path:   "api4/bot.go",
+						user:   "mattermost",
+						repo:   "mattermost-server",
+					},
+				},
+			},
 		},
 	}
 
@@ -353,7 +449,7 @@ func TestMakeReplacements(t *testing.T) {
 			},
 		},
 		{
-			name:   "bad commit hash",
+			name:   "unknown ref",
 			input:  "start https://github.com/mattermost/mattermost-server/blob/badhash/app/authentication.go#L15-L22 lorem ipsum",
 			output: "start https://github.com/mattermost/mattermost-server/blob/badhash/app/authentication.go#L15-L22 lorem ipsum",
 			replacements: []replacement{
@@ -436,24 +532,240 @@ func TestMakeReplacements(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			msg := p.makeReplacements(tc.input, tc.replacements, client)
+			msg := p.makeReplacements(context.Background(), tc.input, tc.replacements, client)
 			assert.Equalf(t, tc.output, msg, "mismatched output")
 		})
 	}
 
-	mockPluginAPI.AssertCalled(t, "LogWarn", "Bad git commit hash in permalink", "error", "encoding/hex: invalid byte: U+0068 'h'", "hash", "badhash")
+	mockPluginAPI.AssertCalled(t, "LogWarn", "Permalink ref did not resolve to a commit", "ref", "badhash")
 	mockPluginAPI.AssertCalled(t, "LogWarn", "Error while fetching file contents", "error", "unmarshalling failed for both file and directory content: unexpected end of JSON input and unexpected end of JSON input", "path", "path/file.go")
 }
 
+func TestGetPreviewLines(t *testing.T) {
+	content := ""
+	for i := 1; i <= 30; i++ {
+		content += fmt.Sprintf("line %d\n", i)
+	}
+	lines := func(from, to int) string {
+		out := ""
+		for i := from; i <= to; i++ {
+			out += fmt.Sprintf("line %d\n", i)
+		}
+		return out
+	}
+
+	tcs := []struct {
+		name        string
+		content     string
+		anchor      string
+		maxLines    int
+		lineContext int
+		expected    string
+		truncated   bool
+	}{
+		{name: "range", content: content, anchor: "L3-L5", maxLines: 10, lineContext: 3, expected: lines(3, 5)},
+		{name: "single line with context", content: content, anchor: "L10", maxLines: 10, lineContext: 2, expected: lines(8, 12)},
+		{name: "truncated range", content: content, anchor: "L1-L20", maxLines: 5, lineContext: 3, expected: lines(1, 6), truncated: true},
+		{name: "multiple ranges", content: content, anchor: "L2-L3,L20", maxLines: 10, lineContext: 1, expected: lines(2, 3) + "...\n" + lines(19, 21)},
+		{name: "multiple ranges over the limit", content: content, anchor: "L1-L4,L10-L15,L25", maxLines: 8, lineContext: 1, expected: lines(1, 4) + "...\n" + lines(10, 14), truncated: true},
+		{name: "bad range", content: content, anchor: "L2-L3,bad", maxLines: 10, lineContext: 1, expected: ""},
+		{name: "out of range", content: content, anchor: "L40-L45", maxLines: 10, lineContext: 1, expected: ""},
+		{name: "small file", content: "package main\n\nfunc main() {}", anchor: "", maxLines: 10, lineContext: 3, expected: "package main\n\nfunc main() {}\n"},
+		{name: "large file", content: content, anchor: "", maxLines: 10, lineContext: 3, expected: ""},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			preview, truncated, err := getPreviewLines(tc.content, tc.anchor, tc.maxLines, tc.lineContext)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, preview)
+			assert.Equal(t, tc.truncated, truncated)
+		})
+	}
+}
+
+func TestMakeReplacementsCaching(t *testing.T) {
+	const (
+		publicLink  = "https://github.com/mattermost/mattermost-server/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go#L15-L22"
+		privateLink = "https://github.com/mattermost/private-repo/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go#L15-L22"
+
+		publicRepoPath     = "/repos/mattermost/mattermost-server"
+		publicContentPath  = "/repos/mattermost/mattermost-server/contents/app/authentication.go"
+		privateRepoPath    = "/repos/mattermost/private-repo"
+		privateContentPath = "/repos/mattermost/private-repo/contents/app/authentication.go"
+	)
+
+	setupPlugin := func(codePreview string) *Plugin {
+		p := NewPlugin()
+		p.setConfiguration(&Configuration{EnableCodePreview: codePreview})
+		mockPluginAPI := &plugintest.API{}
+		mockPluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		p.SetAPI(mockPluginAPI)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p
+	}
+
+	t.Run("duplicate links in a message are fetched once", func(t *testing.T) {
+		p := setupPlugin("public")
+		client, count, close := getCountingClient()
+		defer close()
+
+		msg := "first " + publicLink + " second " + publicLink + " third " + publicLink
+		out := p.makeReplacements(context.Background(), msg, p.getReplacements(msg), client)
+
+		assert.Equal(t, 3, strings.Count(out, "```go"))
+		assert.Equal(t, 1, count(publicRepoPath))
+		assert.Equal(t, 1, count(publicContentPath))
+	})
+
+	t.Run("public files are cached across messages", func(t *testing.T) {
+		p := setupPlugin("public")
+		client, count, close := getCountingClient()
+		defer close()
+
+		for i := 0; i < 3; i++ {
+			msg := "link " + publicLink
+			out := p.makeReplacements(context.Background(), msg, p.getReplacements(msg), client)
+			assert.Contains(t, out, "```go")
+		}
+
+		assert.Equal(t, 1, count(publicRepoPath))
+		assert.Equal(t, 1, count(publicContentPath))
+	})
+
+	t.Run("private repositories are not previewed by default", func(t *testing.T) {
+		p := setupPlugin("public")
+		client, count, close := getCountingClient()
+		defer close()
+
+		for i := 0; i < 2; i++ {
+			msg := "link " + privateLink
+			out := p.makeReplacements(context.Background(), msg, p.getReplacements(msg), client)
+			assert.Equal(t, msg, out)
+		}
+
+		assert.Equal(t, 1, count(privateRepoPath), "the visibility of the repository is cached")
+		assert.Equal(t, 0, count(privateContentPath))
+	})
+
+	t.Run("private files are never cached", func(t *testing.T) {
+		p := setupPlugin("privateAndPublic")
+		client, count, close := getCountingClient()
+		defer close()
+
+		for i := 0; i < 2; i++ {
+			msg := "link " + privateLink
+			out := p.makeReplacements(context.Background(), msg, p.getReplacements(msg), client)
+			assert.Contains(t, out, "```go")
+		}
+
+		assert.Equal(t, 1, count(privateRepoPath))
+		assert.Equal(t, 2, count(privateContentPath))
+	})
+
+	t.Run("links to several repositories", func(t *testing.T) {
+		p := setupPlugin("privateAndPublic")
+		client, count, close := getCountingClient()
+		defer close()
+
+		msg := "first " + publicLink + " second " + privateLink
+		out := p.makeReplacements(context.Background(), msg, p.getReplacements(msg), client)
+
+		assert.Equal(t, 2, strings.Count(out, "```go"))
+		assert.Equal(t, 1, count(publicContentPath))
+		assert.Equal(t, 1, count(privateContentPath))
+	})
+}
+
+func TestMakeReplacementsWithRefs(t *testing.T) {
+	p := NewPlugin()
+	p.setConfiguration(&Configuration{EnableCodePreview: "public"})
+	mockPluginAPI := &plugintest.API{}
+	mockPluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockPluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything)
+	p.SetAPI(mockPluginAPI)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	client, count, close := getCountingClient()
+	defer close()
+
+	preview := "```go\ntype TokenLocation int\n\nconst (\n\tTokenLocationNotFound TokenLocation = iota\n\tTokenLocationHeader\n\tTokenLocationCookie\n\tTokenLocationQueryString\n)\n```\n"
+
+	tcs := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{
+			name:   "branch",
+			input:  "start https://github.com/mattermost/mattermost-server/blob/master/app/authentication.go#L15-L22 lorem ipsum",
+			output: "start \n[mattermost/mattermost-server/app/authentication.go](https://github.com/mattermost/mattermost-server/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go#L15-L22) - `master` at `cbb2583`\n" + preview + " lorem ipsum",
+		},
+		{
+			name:   "tag",
+			input:  "start https://github.com/mattermost/mattermost-server/blob/v5.0.0/app/authentication.go#L15-L22 lorem ipsum",
+			output: "start \n[mattermost/mattermost-server/app/authentication.go](https://github.com/mattermost/mattermost-server/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go#L15-L22) - `v5.0.0` at `cbb2583`\n" + preview + " lorem ipsum",
+		},
+		{
+			name:   "commit followed by a comma",
+			input:  "start https://github.com/mattermost/mattermost-server/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go#L15-L22, lorem ipsum",
+			output: "start \n[mattermost/mattermost-server/app/authentication.go](https://github.com/mattermost/mattermost-server/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go#L15-L22)\n" + preview + ", lorem ipsum",
+		},
+		{
+			name:   "whole file longer than the preview",
+			input:  "start https://github.com/mattermost/mattermost-server/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go lorem ipsum",
+			output: "start https://github.com/mattermost/mattermost-server/blob/cbb25838a61872b624ac512556d7bc932486a64c/app/authentication.go lorem ipsum",
+		},
+		{
+			name:   "unknown branch",
+			input:  "start https://github.com/mattermost/mattermost-server/blob/unknown/app/authentication.go#L15-L22 lorem ipsum",
+			output: "start https://github.com/mattermost/mattermost-server/blob/unknown/app/authentication.go#L15-L22 lorem ipsum",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := p.makeReplacements(context.Background(), tc.input, p.getReplacements(tc.input), client)
+			assert.Equal(t, tc.output, msg)
+		})
+	}
+
+	assert.Equal(t, 1, count("/repos/mattermost/mattermost-server/contents/app/authentication.go"), "resolved refs share the cached commit contents")
+}
+
 const (
 	baseURLPath = "/api-v3"
 )
 
 func getClient() (*github.Client, func()) {
+	client, _, close := getCountingClient()
+	return client, close
+}
+
+// getCountingClient returns a client for a mocked GitHub API, along with a function returning
+// the number of requests received for an API path.
+func getCountingClient() (*github.Client, func(path string) int, func()) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	count := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[path]
+	}
+
 	apiHandler := http.NewServeMux()
 	apiHandler.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		requests[strings.TrimPrefix(req.URL.Path, baseURLPath)]++
+		mu.Unlock()
+
 		switch req.URL.Path {
-		case "/api-v3/repos/mattermost/mattermost-server/contents/app/authentication.go":
+		case "/api-v3/repos/mattermost/mattermost-server/commits/master", "/api-v3/repos/mattermost/mattermost-server/commits/v5.0.0":
+			fmt.Fprint(w, "cbb25838a61872b624ac512556d7bc932486a64c")
+		case "/api-v3/repos/mattermost/private-repo":
+			fmt.Fprintln(w, `{"name": "private-repo", "private": true}`)
+		case "/api-v3/repos/mattermost/mattermost-server/contents/app/authentication.go",
+			"/api-v3/repos/mattermost/private-repo/contents/app/authentication.go":
 			fmt.Fprintln(w, `{
   "name": "authentication.go",
   "path": "app/authentication.go",
@@ -496,5 +808,5 @@ func getClient() (*github.Client, func()) {
 	url, _ := url.Parse(server.URL + baseURLPath + "/")
 	client.BaseURL = url
 	client.UploadURL = url
-	return client, server.Close
+	return client, count, server.Close
 }
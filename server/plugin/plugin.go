@@ -9,19 +9,24 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/go-github/v41/github"
 	"github.com/gorilla/mux"
 	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-plugin-api/cluster"
 	"github.com/mattermost/mattermost-plugin-api/experimental/bot/logger"
 	"github.com/mattermost/mattermost-plugin-api/experimental/bot/poster"
 	"github.com/mattermost/mattermost-plugin-api/experimental/telemetry"
 	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/mattermost/mattermost-server/v6/plugin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
+	"golang.org/x/text/language"
 
 	root "github.com/mattermost/mattermost-plugin-github"
 	"github.com/mattermost/mattermost-plugin-github/server/plugin/graphql"
@@ -52,6 +57,14 @@ const (
 	settingOff           = "off"
 	settingOnChange      = "on-change"
 
+	notificationTypeReviewRequests = "review-requests"
+	notificationTypeMentions       = "mentions"
+	notificationTypeComments       = "comments"
+	notificationTypeAssignments    = "assignments"
+	notificationTypeReviewResults  = "review-results"
+	notificationTypeCIFailures     = "ci-failures"
+	notificationTypeReadyToMerge   = "ready-to-merge"
+
 	notificationReasonSubscribed = "subscribed"
 	dailySummary                 = "_dailySummary"
 
@@ -93,16 +106,31 @@ type Plugin struct {
 	// githubPermalinkRegex is used to parse github permalinks in post messages.
 	githubPermalinkRegex *regexp.Regexp
 
+	// permalinkContentCache holds the contents of files of public repositories previewed in
+	// permalinks, keyed by repository, commit and path.
+	permalinkContentCache *expiringLRU
+	// repoPrivacyCache holds whether repositories linked in permalinks are private.
+	repoPrivacyCache *expiringLRU
+
 	webhookBroker *WebhookBroker
 	oauthBroker   *OAuthBroker
 
+	// throttleJob releases notifications held back by subscription rate limits and quiet hours.
+	throttleJob *cluster.Job
+
 	emojiMap map[string]string
+
+	// bundle holds the translations of the bot messages.
+	bundle *i18n.Bundle
 }
 
 // NewPlugin returns an instance of a Plugin.
 func NewPlugin() *Plugin {
 	p := &Plugin{
-		githubPermalinkRegex: regexp.MustCompile(`https?://(?P<haswww>www\.)?github\.com/(?P<user>[\w-]+)/(?P<repo>[\w-.]+)/blob/(?P<commit>\w+)/(?P<path>[\w-/.]+)#(?P<line>[\w-]+)?`),
+		githubPermalinkRegex:  regexp.MustCompile(`https?://(?P<haswww>www\.)?github\.com/(?P<user>[\w-]+)/(?P<repo>[\w-.]+)/blob/(?P<commit>[\w.-]+)/(?P<path>[\w-/.]+)(?:#(?P<line>L\d+(?:-L\d+)?(?:,L\d+(?:-L\d+)?)*))?`),
+		permalinkContentCache: newExpiringLRU(permalinkContentCacheSize, 0),
+		repoPrivacyCache:      newExpiringLRU(repoPrivacyCacheSize, repoPrivacyTTL),
+		bundle:                i18n.NewBundle(language.English),
 	}
 
 	p.CommandHandlers = map[string]CommandHandleFunc{
@@ -267,9 +295,22 @@ func (p *Plugin) OnActivate() error {
 	p.BotUserID = botID
 
 	p.poster = poster.NewPoster(&p.client.Post, p.BotUserID)
+
+	if err = p.initTranslations(); err != nil {
+		p.client.Log.Warn("Failed to load translations, using English only", "error", err.Error())
+	}
+
 	p.flowManager = p.NewFlowManager()
 
 	registerGitHubToUsernameMappingCallback(p.getGitHubToUsernameMapping)
+	registerTemplateRenderErrorCallback(func(name string, err error) {
+		p.client.Log.Warn("Failed to render custom notification template, using the built-in one", "template", name, "error", err.Error())
+	})
+
+	p.throttleJob, err = cluster.Schedule(p.API, throttleJobKey, cluster.MakeWaitForRoundedInterval(time.Minute), p.flushThrottledNotifications)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule flushing of held back notifications")
+	}
 
 	go func() {
 		resetErr := p.forceResetAllMM34646()
@@ -283,6 +324,11 @@ func (p *Plugin) OnActivate() error {
 func (p *Plugin) OnDeactivate() error {
 	p.webhookBroker.Close()
 	p.oauthBroker.Close()
+	if p.throttleJob != nil {
+		if err := p.throttleJob.Close(); err != nil {
+			p.client.Log.Warn("Failed to close the job flushing held back notifications", "error", err.Error())
+		}
+	}
 	if err := p.telemetryClient.Close(); err != nil {
 		p.API.LogWarn("Telemetry client failed to close", "error", err.Error())
 	}
@@ -506,8 +552,14 @@ func (p *Plugin) MessageWillBePosted(c *plugin.Context, post *model.Post) (*mode
 	// TODO: make this part of the Plugin struct and reuse it.
 	ghClient := p.githubConnectUser(context.Background(), info)
 
+	// Code previews and link previews share a single deadline, so that posting is never delayed
+	// by more than permalinkReqTimeout.
+	ctx, cancel := context.WithTimeout(context.Background(), permalinkReqTimeout)
+	defer cancel()
+
 	replacements := p.getReplacements(msg)
-	post.Message = p.makeReplacements(msg, replacements, ghClient)
+	post.Message = p.makeReplacements(ctx, msg, replacements, ghClient)
+	p.addLinkPreviews(ctx, post, msg, ghClient)
 	return post, ""
 }
 
@@ -584,11 +636,49 @@ type GitHubUserInfo struct {
 	MM34646ResetTokenDone bool
 }
 
+// notificationTypes are the kinds of direct message notifications a user can turn off individually.
+var notificationTypes = []string{
+	notificationTypeReviewRequests,
+	notificationTypeMentions,
+	notificationTypeComments,
+	notificationTypeAssignments,
+	notificationTypeReviewResults,
+	notificationTypeCIFailures,
+	notificationTypeReadyToMerge,
+}
+
 type UserSettings struct {
 	SidebarButtons        string `json:"sidebar_buttons"`
 	DailyReminder         bool   `json:"daily_reminder"`
 	DailyReminderOnChange bool   `json:"daily_reminder_on_change"`
 	Notifications         bool   `json:"notifications"`
+	// DisabledNotifications lists the notification types turned off, so that new types are on by default.
+	DisabledNotifications []string `json:"disabled_notifications,omitempty"`
+}
+
+// IsNotificationEnabled reports whether the user receives notifications of the given type. It
+// does not cover the Notifications setting, which turns off all notifications.
+func (s *UserSettings) IsNotificationEnabled(notificationType string) bool {
+	return !containsValue(s.DisabledNotifications, notificationType)
+}
+
+// SetNotificationEnabled turns notifications of the given type on or off.
+func (s *UserSettings) SetNotificationEnabled(notificationType string, enabled bool) {
+	disabled := []string{}
+	for _, t := range s.DisabledNotifications {
+		if t != notificationType {
+			disabled = append(disabled, t)
+		}
+	}
+	if !enabled {
+		disabled = append(disabled, notificationType)
+	}
+	sort.Strings(disabled)
+
+	if len(disabled) == 0 {
+		disabled = nil
+	}
+	s.DisabledNotifications = disabled
 }
 
 func (p *Plugin) storeGitHubUserInfo(info *GitHubUserInfo) error {
@@ -642,14 +732,23 @@ func (p *Plugin) storeGitHubToUserIDMapping(githubUsername, userID string) error
 
 func (p *Plugin) getGitHubToUserIDMapping(githubUsername string) string {
 	var data []byte
-	_ = p.client.KV.Get(githubUsername+githubUsernameKey, data)
+	_ = p.client.KV.Get(githubUsername+githubUsernameKey, &data)
 
 	return string(data)
 }
 
 // getGitHubToUsernameMapping maps a GitHub username to the corresponding Mattermost username, if any.
+// GitHub users who didn't connect their account are looked up in the mappings imported by an admin.
 func (p *Plugin) getGitHubToUsernameMapping(githubUsername string) string {
-	user, _ := p.client.User.Get(p.getGitHubToUserIDMapping(githubUsername))
+	userID := p.getGitHubToUserIDMapping(githubUsername)
+	if userID == "" {
+		userID = p.getImportedUserMapping(githubUsername)
+	}
+	if userID == "" {
+		return ""
+	}
+
+	user, _ := p.client.User.Get(userID)
 	if user == nil {
 		return ""
 	}
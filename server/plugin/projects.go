@@ -0,0 +1,293 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-github/server/plugin/graphql"
+)
+
+const (
+	// projectFieldTypeSingleSelect is the type of the project fields that make up the columns of a
+	// board, like the status of an item.
+	projectFieldTypeSingleSelect = "single_select"
+
+	projectItemTimeout = 10 * time.Second
+)
+
+// ProjectsV2Item is an item of a project, as sent in projects_v2_item webhook events. The item
+// itself is only referenced by its node ID.
+type ProjectsV2Item struct {
+	ID            *int64  `json:"id,omitempty"`
+	NodeID        *string `json:"node_id,omitempty"`
+	ProjectNodeID *string `json:"project_node_id,omitempty"`
+	ContentNodeID *string `json:"content_node_id,omitempty"`
+	ContentType   *string `json:"content_type,omitempty"`
+}
+
+// ProjectsV2FieldValue is the value of a project field before or after a change. Single select
+// fields hold an option and iteration fields an iteration, while other fields hold a plain value.
+type ProjectsV2FieldValue struct {
+	Name string
+}
+
+// ProjectsV2FieldValueChange is the change of a field value of a project item. Older events only
+// reference the field by its node ID.
+type ProjectsV2FieldValueChange struct {
+	FieldNodeID *string               `json:"field_node_id,omitempty"`
+	FieldType   *string               `json:"field_type,omitempty"`
+	FieldName   *string               `json:"field_name,omitempty"`
+	From        *ProjectsV2FieldValue `json:"from,omitempty"`
+	To          *ProjectsV2FieldValue `json:"to,omitempty"`
+}
+
+// ProjectsV2ItemChanges are the changes of an edited project item.
+type ProjectsV2ItemChanges struct {
+	FieldValue *ProjectsV2FieldValueChange `json:"field_value,omitempty"`
+}
+
+// ProjectsV2ItemEvent is triggered when an item of an organization project is created, edited,
+// archived, restored, converted, reordered or deleted.
+type ProjectsV2ItemEvent struct {
+	Action         *string                `json:"action,omitempty"`
+	ProjectsV2Item *ProjectsV2Item        `json:"projects_v2_item,omitempty"`
+	Changes        *ProjectsV2ItemChanges `json:"changes,omitempty"`
+	Org            *github.Organization   `json:"organization,omitempty"`
+	Sender         *github.User           `json:"sender,omitempty"`
+}
+
+// ProjectItemMove is the data of the notification about an item moved to another column or status
+// of a project.
+type ProjectItemMove struct {
+	Event *ProjectsV2ItemEvent
+	Item  *graphql.ProjectItem
+	Field string
+	From  string
+	To    string
+}
+
+func (v *ProjectsV2FieldValue) UnmarshalJSON(data []byte) error {
+	var option struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(data, &option); err == nil {
+		v.Name = option.Name
+		if v.Name == "" {
+			v.Name = option.Title
+		}
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value != nil {
+		v.Name = fmt.Sprint(value)
+	}
+
+	return nil
+}
+
+func (v *ProjectsV2FieldValue) GetName() string {
+	if v == nil {
+		return ""
+	}
+	return v.Name
+}
+
+func (i *ProjectsV2Item) GetNodeID() string {
+	if i == nil || i.NodeID == nil {
+		return ""
+	}
+	return *i.NodeID
+}
+
+func (c *ProjectsV2FieldValueChange) GetFieldNodeID() string {
+	if c == nil || c.FieldNodeID == nil {
+		return ""
+	}
+	return *c.FieldNodeID
+}
+
+func (c *ProjectsV2FieldValueChange) GetFieldType() string {
+	if c == nil || c.FieldType == nil {
+		return ""
+	}
+	return *c.FieldType
+}
+
+func (c *ProjectsV2FieldValueChange) GetFieldName() string {
+	if c == nil || c.FieldName == nil {
+		return ""
+	}
+	return *c.FieldName
+}
+
+func (c *ProjectsV2FieldValueChange) GetFrom() *ProjectsV2FieldValue {
+	if c == nil {
+		return nil
+	}
+	return c.From
+}
+
+func (c *ProjectsV2FieldValueChange) GetTo() *ProjectsV2FieldValue {
+	if c == nil {
+		return nil
+	}
+	return c.To
+}
+
+func (c *ProjectsV2ItemChanges) GetFieldValue() *ProjectsV2FieldValueChange {
+	if c == nil {
+		return nil
+	}
+	return c.FieldValue
+}
+
+func (e *ProjectsV2ItemEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *ProjectsV2ItemEvent) GetProjectsV2Item() *ProjectsV2Item {
+	if e == nil {
+		return nil
+	}
+	return e.ProjectsV2Item
+}
+
+func (e *ProjectsV2ItemEvent) GetChanges() *ProjectsV2ItemChanges {
+	if e == nil {
+		return nil
+	}
+	return e.Changes
+}
+
+func (e *ProjectsV2ItemEvent) GetOrg() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Org
+}
+
+func (e *ProjectsV2ItemEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// getProjectItemMove fetches the moved item of a project with the GitHub account of the given
+// user, so that only items the user can see are posted. Older events don't name the changed
+// field and its new value, which are then taken from the item.
+func (p *Plugin) getProjectItemMove(userID string, event *ProjectsV2ItemEvent) (*ProjectItemMove, error) {
+	info, apiErr := p.getGitHubUserInfo(userID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	graphQLClient := p.graphQLConnect(info)
+	if graphQLClient == nil {
+		return nil, errors.New("failed to create GraphQL client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), projectItemTimeout)
+	defer cancel()
+
+	item, err := graphQLClient.GetProjectItem(ctx, event.GetProjectsV2Item().GetNodeID())
+	if err != nil {
+		return nil, err
+	}
+
+	change := event.GetChanges().GetFieldValue()
+	move := &ProjectItemMove{
+		Event: event,
+		Item:  item,
+		Field: change.GetFieldName(),
+		From:  change.GetFrom().GetName(),
+		To:    change.GetTo().GetName(),
+	}
+
+	for _, value := range item.SingleSelect {
+		if value.FieldID != change.GetFieldNodeID() {
+			continue
+		}
+		if move.Field == "" {
+			move.Field = value.FieldName
+		}
+		if move.To == "" {
+			move.To = value.Name
+		}
+	}
+
+	return move, nil
+}
+
+// postProjectsV2ItemEvent posts items moved to another column or status of a project. Projects
+// belong to an organization, so the events are delivered to the subscriptions of the organization.
+func (p *Plugin) postProjectsV2ItemEvent(event *ProjectsV2ItemEvent) {
+	if event.GetAction() != actionEdited {
+		return
+	}
+
+	if event.GetChanges().GetFieldValue().GetFieldType() != projectFieldTypeSingleSelect {
+		return
+	}
+
+	org := strings.ToLower(event.GetOrg().GetLogin())
+	if org == "" {
+		return
+	}
+
+	subs, err := p.GetSubscriptions()
+	if err != nil {
+		p.client.Log.Warn("Failed to get subscriptions", "error", err.Error())
+		return
+	}
+
+	config := p.getConfiguration()
+	for _, sub := range subs.Repositories[fullNameFromOwnerAndRepo(org, "")] {
+		if !sub.Projects() {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		move, err := p.getProjectItemMove(sub.CreatorID, event)
+		if err != nil {
+			p.client.Log.Debug("Failed to get project item", "channel_id", sub.ChannelID, "error", err.Error())
+			continue
+		}
+
+		if move.Item.Private && !config.EnablePrivateRepo {
+			continue
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "projectItemMoved", move)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_project_item",
+			Message:   message,
+			ChannelId: sub.ChannelID,
+		}
+		p.createSubscriptionPost(sub, post, event)
+	}
+}
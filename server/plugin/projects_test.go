@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const projectsV2ItemPayload = `{
+	"action": "edited",
+	"projects_v2_item": {
+		"id": 7,
+		"node_id": "PVTI_lADOAQ",
+		"project_node_id": "PVT_kwDOAQ",
+		"content_node_id": "I_kwDOAQ",
+		"content_type": "Issue"
+	},
+	"changes": {
+		"field_value": {
+			"field_node_id": "PVTSSF_lADOAQ",
+			"field_type": "single_select",
+			"field_name": "Status",
+			"from": {"id": "f75ad846", "name": "Todo", "color": "GREEN"},
+			"to": {"id": "47fc9ee4", "name": "In Progress", "color": "YELLOW"}
+		}
+	},
+	"organization": {"login": "mattermost"},
+	"sender": {"login": "panda"}
+}`
+
+func TestParseProjectsV2ItemEvent(t *testing.T) {
+	event, err := parseWebhook("projects_v2_item", []byte(projectsV2ItemPayload))
+	require.NoError(t, err)
+
+	itemEvent, ok := event.(*ProjectsV2ItemEvent)
+	require.True(t, ok)
+	change := itemEvent.GetChanges().GetFieldValue()
+	assert.Equal(t, "PVTI_lADOAQ", itemEvent.GetProjectsV2Item().GetNodeID())
+	assert.Equal(t, projectFieldTypeSingleSelect, change.GetFieldType())
+	assert.Equal(t, "Status", change.GetFieldName())
+	assert.Equal(t, "Todo", change.GetFrom().GetName())
+	assert.Equal(t, "In Progress", change.GetTo().GetName())
+	assert.Equal(t, "mattermost", itemEvent.GetOrg().GetLogin())
+}
+
+func TestProjectsV2FieldValueUnmarshal(t *testing.T) {
+	for name, tc := range map[string]struct {
+		Data     string
+		Expected *ProjectsV2FieldValue
+	}{
+		"option":    {Data: `{"from": {"name": "Done"}}`, Expected: &ProjectsV2FieldValue{Name: "Done"}},
+		"iteration": {Data: `{"from": {"title": "Sprint 3"}}`, Expected: &ProjectsV2FieldValue{Name: "Sprint 3"}},
+		"text":      {Data: `{"from": "Ship it"}`, Expected: &ProjectsV2FieldValue{Name: "Ship it"}},
+		"number":    {Data: `{"from": 3}`, Expected: &ProjectsV2FieldValue{Name: "3"}},
+		"null":      {Data: `{"from": null}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var change ProjectsV2FieldValueChange
+			require.NoError(t, json.Unmarshal([]byte(tc.Data), &change))
+			assert.Equal(t, tc.Expected, change.From)
+		})
+	}
+}
+
+func TestPostProjectsV2ItemEventIgnoresOtherFields(t *testing.T) {
+	event, err := parseWebhook("projects_v2_item", []byte(projectsV2ItemPayload))
+	require.NoError(t, err)
+	itemEvent := event.(*ProjectsV2ItemEvent)
+	itemEvent.Changes.FieldValue.FieldType = sToP("text")
+
+	api := &plugintest.API{}
+	p := NewPlugin()
+	p.SetAPI(api)
+
+	// Only moves between the columns of a board are posted, so no subscriptions are looked up.
+	p.postProjectsV2ItemEvent(itemEvent)
+
+	api.AssertNotCalled(t, "KVGet", SubscriptionsKey)
+}
@@ -0,0 +1,143 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+const (
+	// maxPushDetailCommits is the number of commits listed with their stats in a detailed push
+	// notification. Larger pushes link to the comparison of all changes instead.
+	maxPushDetailCommits = 10
+
+	// pushDetailTimeout bounds the time spent fetching the commit stats and branch protection of
+	// a push.
+	pushDetailTimeout = 10 * time.Second
+)
+
+// PushDetail is the data of the pushedCommitsDetail template.
+type PushDetail struct {
+	Event *github.PushEvent
+	// Commits are the listed commits, at most maxPushDetailCommits.
+	Commits []*PushCommitDetail
+	// TotalCommits is the number of commits in the push, including the ones not listed.
+	TotalCommits int
+	// MoreCommits is the number of commits that are not listed.
+	MoreCommits int
+
+	DefaultBranch   bool
+	ProtectedBranch bool
+
+	// HasStats is false if the stats of a listed commit could not be fetched. The totals only
+	// cover the listed commits, FilesChanged counts every file once.
+	HasStats     bool
+	FilesChanged int
+	Additions    int
+	Deletions    int
+}
+
+// PushCommitDetail is a commit of a push along with its stats.
+type PushCommitDetail struct {
+	*github.HeadCommit
+	HasStats     bool
+	FilesChanged int
+	Additions    int
+	Deletions    int
+}
+
+// getSubscriptionsPushDetail collects the data of a detailed push notification once for all
+// subscriptions. GetSubscribedChannelsForRepository only returns subscriptions whose creator can
+// read the repository, so the account of any creator with a connected GitHub account is used.
+func (p *Plugin) getSubscriptionsPushDetail(event *github.PushEvent, subs []*Subscription) *PushDetail {
+	ctx, cancel := context.WithTimeout(context.Background(), pushDetailTimeout)
+	defer cancel()
+
+	for _, sub := range subs {
+		if !sub.PushDetail() {
+			continue
+		}
+
+		if ghClient := p.getSubscriptionCreatorClient(sub); ghClient != nil {
+			return p.getPushDetail(ctx, event, ghClient)
+		}
+	}
+
+	return p.getPushDetail(ctx, event, nil)
+}
+
+// getPushDetail collects the data of a detailed push notification. The commit stats and branch
+// protection are fetched with ghClient, which may be nil if no GitHub account is available. Data
+// that can't be fetched is left out of the notification.
+func (p *Plugin) getPushDetail(ctx context.Context, event *github.PushEvent, ghClient *github.Client) *PushDetail {
+	branch := strings.TrimPrefix(event.GetRef(), "refs/heads/")
+	isBranch := branch != event.GetRef()
+
+	detail := &PushDetail{
+		Event:         event,
+		TotalCommits:  len(event.Commits),
+		DefaultBranch: isBranch && branch == event.GetRepo().GetDefaultBranch(),
+		HasStats:      ghClient != nil,
+	}
+	// The webhook payload lists at most 20 commits, while size counts all of them.
+	if event.GetSize() > detail.TotalCommits {
+		detail.TotalCommits = event.GetSize()
+	}
+
+	commits := event.Commits
+	if len(commits) > maxPushDetailCommits {
+		commits = commits[:maxPushDetailCommits]
+	}
+	detail.MoreCommits = detail.TotalCommits - len(commits)
+
+	files := map[string]bool{}
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+
+	if ghClient != nil && isBranch {
+		b, _, err := ghClient.Repositories.GetBranch(ctx, owner, repo, branch, false)
+		if err != nil {
+			p.client.Log.Debug("Failed to fetch branch of push", "repo", event.GetRepo().GetFullName(), "branch", branch, "error", err.Error())
+		} else {
+			detail.ProtectedBranch = b.GetProtected()
+		}
+	}
+
+	for _, commit := range commits {
+		commitDetail := &PushCommitDetail{HeadCommit: commit}
+		detail.Commits = append(detail.Commits, commitDetail)
+
+		if ghClient == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			detail.HasStats = false
+			continue
+		}
+
+		repoCommit, _, err := ghClient.Repositories.GetCommit(ctx, owner, repo, commit.GetID(), nil)
+		if err != nil {
+			p.client.Log.Debug("Failed to fetch commit of push", "repo", event.GetRepo().GetFullName(), "sha", commit.GetID(), "error", err.Error())
+			detail.HasStats = false
+			continue
+		}
+
+		commitDetail.HasStats = true
+		commitDetail.FilesChanged = len(repoCommit.Files)
+		commitDetail.Additions = repoCommit.GetStats().GetAdditions()
+		commitDetail.Deletions = repoCommit.GetStats().GetDeletions()
+
+		for _, file := range repoCommit.Files {
+			files[file.GetFilename()] = true
+		}
+		detail.Additions += commitDetail.Additions
+		detail.Deletions += commitDetail.Deletions
+	}
+
+	detail.FilesChanged = len(files)
+
+	return detail
+}
@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPushDetail(t *testing.T) {
+	apiHandler := http.NewServeMux()
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/branches/master", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"name": "master", "protected": true}`)
+	})
+	apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/branches/feature", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"name": "feature", "protected": false}`)
+	})
+	for i := 0; i < 12; i++ {
+		sha := fmt.Sprintf("sha%d", i)
+		apiHandler.HandleFunc("/api-v3/repos/mattermost/mattermost-server/commits/"+sha, func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, `{"sha": "%s", "stats": {"additions": 5, "deletions": 2, "total": 7}, "files": [{"filename": "README.md"}, {"filename": "%s.go"}]}`, sha, sha)
+		})
+	}
+	server := httptest.NewServer(apiHandler)
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	baseURL, _ := url.Parse(server.URL + baseURLPath + "/")
+	client.BaseURL = baseURL
+
+	mockPluginAPI := &plugintest.API{}
+	mockPluginAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	p := NewPlugin()
+	p.SetAPI(mockPluginAPI)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	pushEvent := func(ref string, commits int) *github.PushEvent {
+		event := &github.PushEvent{
+			Ref:  github.String(ref),
+			Size: github.Int(commits),
+			Repo: &github.PushEventRepository{
+				Name:          github.String("mattermost-server"),
+				FullName:      github.String("mattermost/mattermost-server"),
+				Owner:         &github.User{Login: github.String("mattermost")},
+				DefaultBranch: github.String("master"),
+			},
+		}
+		for i := 0; i < commits && i < 20; i++ {
+			event.Commits = append(event.Commits, &github.HeadCommit{ID: github.String(fmt.Sprintf("sha%d", i))})
+		}
+		return event
+	}
+
+	t.Run("stats of a push to the default branch", func(t *testing.T) {
+		detail := p.getPushDetail(context.Background(), pushEvent("refs/heads/master", 2), client)
+
+		assert.True(t, detail.DefaultBranch)
+		assert.True(t, detail.ProtectedBranch)
+		assert.Equal(t, 2, detail.TotalCommits)
+		assert.Zero(t, detail.MoreCommits)
+		require.Len(t, detail.Commits, 2)
+		assert.Equal(t, "sha1", detail.Commits[1].GetID())
+		assert.True(t, detail.Commits[1].HasStats)
+		assert.Equal(t, 2, detail.Commits[1].FilesChanged)
+		assert.Equal(t, 5, detail.Commits[1].Additions)
+		assert.Equal(t, 2, detail.Commits[1].Deletions)
+
+		assert.True(t, detail.HasStats)
+		assert.Equal(t, 3, detail.FilesChanged)
+		assert.Equal(t, 10, detail.Additions)
+		assert.Equal(t, 4, detail.Deletions)
+	})
+
+	t.Run("huge push is truncated", func(t *testing.T) {
+		detail := p.getPushDetail(context.Background(), pushEvent("refs/heads/feature", 25), client)
+
+		assert.False(t, detail.DefaultBranch)
+		assert.False(t, detail.ProtectedBranch)
+		assert.Equal(t, 25, detail.TotalCommits)
+		assert.Equal(t, 15, detail.MoreCommits)
+		assert.Len(t, detail.Commits, maxPushDetailCommits)
+		assert.True(t, detail.HasStats)
+		assert.Equal(t, 11, detail.FilesChanged)
+	})
+
+	t.Run("commit that can't be fetched", func(t *testing.T) {
+		event := pushEvent("refs/heads/feature", 1)
+		event.Commits = append(event.Commits, &github.HeadCommit{ID: github.String("unknown")})
+
+		detail := p.getPushDetail(context.Background(), event, client)
+
+		require.Len(t, detail.Commits, 2)
+		assert.True(t, detail.Commits[0].HasStats)
+		assert.False(t, detail.Commits[1].HasStats)
+		assert.False(t, detail.HasStats)
+	})
+
+	t.Run("without GitHub account", func(t *testing.T) {
+		detail := p.getPushDetail(context.Background(), pushEvent("refs/heads/master", 1), nil)
+
+		assert.True(t, detail.DefaultBranch)
+		assert.False(t, detail.ProtectedBranch)
+		assert.False(t, detail.HasStats)
+		require.Len(t, detail.Commits, 1)
+		assert.False(t, detail.Commits[0].HasStats)
+	})
+
+	t.Run("expired deadline", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		detail := p.getPushDetail(ctx, pushEvent("refs/heads/master", 2), client)
+
+		assert.False(t, detail.ProtectedBranch)
+		assert.False(t, detail.HasStats)
+		require.Len(t, detail.Commits, 2)
+		assert.False(t, detail.Commits[0].HasStats)
+		assert.False(t, detail.Commits[1].HasStats)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		detail := p.getPushDetail(context.Background(), pushEvent("refs/tags/master", 1), client)
+
+		assert.False(t, detail.DefaultBranch)
+		assert.False(t, detail.ProtectedBranch)
+	})
+}
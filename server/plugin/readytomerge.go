@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"context"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// ReadyToMergeNotification is the data of the notification sent to a pull request author once the
+// pull request is approved, free of conflicts and all of its checks passed.
+type ReadyToMergeNotification struct {
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+}
+
+// handleReadyToMergeReview tells the author of a pull request when an approval makes it ready to
+// merge.
+func (p *Plugin) handleReadyToMergeReview(event *github.PullRequestReviewEvent) {
+	if event.GetAction() != actionSubmitted || event.GetReview().GetState() != "approved" {
+		return
+	}
+
+	repo := event.GetRepo()
+	pr := event.GetPullRequest()
+	if pr.GetHead().GetSHA() == "" {
+		return
+	}
+
+	// Pull requests opened before CI results were tracked are picked up here, so that the ready to
+	// merge notification is only sent once.
+	p.trackPullRequest(repo.GetFullName(), pr)
+
+	authorUserID, info := p.getPullRequestAuthorToNotify(pr.GetUser().GetLogin(), repo, pr.Labels)
+	if authorUserID == "" {
+		return
+	}
+
+	if !info.Settings.IsNotificationEnabled(notificationTypeReadyToMerge) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ciStatusTimeout)
+	defer cancel()
+
+	p.notifyReadyToMerge(ctx, authorUserID, info, repo, pr.GetNumber(), pr.GetHead().GetSHA())
+}
+
+// notifyReadyToMerge tells the author of a pull request that it is ready to merge, once per head
+// commit. Nothing is sent if the head commit is no longer sha.
+func (p *Plugin) notifyReadyToMerge(ctx context.Context, authorUserID string, info *GitHubUserInfo, repo *github.Repository, number int, sha string) {
+	graphQLClient := p.graphQLConnect(info)
+	if graphQLClient == nil {
+		return
+	}
+
+	status, err := graphQLClient.GetPullRequestMergeStatus(ctx, repo.GetOwner().GetLogin(), repo.GetName(), number)
+	if err != nil {
+		p.client.Log.Warn("Failed to get merge status of pull request", "repository", repo.GetFullName(), "number", number, "error", err.Error())
+		return
+	}
+
+	if status.HeadSHA != sha || !status.IsReadyToMerge() {
+		return
+	}
+
+	notify, err := p.updateCIPullRequest(ciPullRequestKey(repo.GetFullName(), sha), false, func(tracked *ciPullRequest) bool {
+		if tracked.ReadyToMergeNotified {
+			return false
+		}
+		tracked.ReadyToMergeNotified = true
+		return true
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to update tracked pull request", "repository", repo.GetFullName(), "error", err.Error())
+		return
+	}
+	if !notify {
+		return
+	}
+
+	message, err := p.renderUserTemplate(authorUserID, "readyToMergeNotification", &ReadyToMergeNotification{
+		Repository: repo.GetFullName(),
+		Number:     status.Number,
+		Title:      status.Title,
+		URL:        status.URL,
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to render template", "error", err.Error())
+		return
+	}
+
+	p.CreateBotDMPost(authorUserID, message, "custom_git_ready_to_merge")
+}
@@ -0,0 +1,140 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestHandleReadyToMergeReview(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		action string
+		state  string
+	}{
+		{name: "review with comments", action: actionSubmitted, state: "commented"},
+		{name: "changes requested", action: actionSubmitted, state: "changes_requested"},
+		{name: "dismissed approval", action: "dismissed", state: "approved"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			api := &plugintest.API{}
+			p := NewPlugin()
+			p.SetAPI(api)
+			p.client = pluginapi.NewClient(p.API, p.Driver)
+
+			p.handleReadyToMergeReview(&github.PullRequestReviewEvent{
+				Action: sToP(tc.action),
+				Repo:   &repo,
+				Review: &github.PullRequestReview{State: sToP(tc.state)},
+				PullRequest: &github.PullRequest{
+					Number: iToP(42),
+					Head:   &github.PullRequestBranch{SHA: sToP("head")},
+					User:   &github.User{Login: sToP("panda")},
+				},
+			})
+
+			api.AssertNotCalled(t, "KVGet", mock.Anything)
+			api.AssertNotCalled(t, "CreatePost", mock.Anything)
+		})
+	}
+}
+
+func TestNotifyReadyToMerge(t *testing.T) {
+	mergeStatus := func(headSHA, reviewDecision string) string {
+		return fmt.Sprintf(`{"data": {"repository": {"pullRequest": {"number": 42, "title": "Add ready to merge notifications",
+			"url": "https://github.com/mattermost/mattermost-plugin-github/pull/42", "state": "OPEN", "isDraft": false,
+			"headRefOid": "%s", "reviewDecision": "%s", "mergeable": "MERGEABLE", "latestOpinionatedReviews": {"nodes": []},
+			"commits": {"nodes": [{"commit": {"statusCheckRollup": {"state": "SUCCESS"}}}]}}}}}`, headSHA, reviewDecision)
+	}
+
+	var response string
+	apiHandler := http.NewServeMux()
+	apiHandler.HandleFunc("/api/graphql", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, response)
+	})
+	server := httptest.NewServer(apiHandler)
+	defer server.Close()
+
+	key := ciPullRequestKey(repo.GetFullName(), "head")
+	tracked, err := json.Marshal(&ciPullRequest{Repository: repo.GetFullName(), Number: 42})
+	require.NoError(t, err)
+	notified, err := json.Marshal(&ciPullRequest{Repository: repo.GetFullName(), Number: 42, ReadyToMergeNotified: true})
+	require.NoError(t, err)
+
+	info := &GitHubUserInfo{UserID: "user1", Token: &oauth2.Token{AccessToken: "token"}, Settings: &UserSettings{}}
+
+	setupPlugin := func() (*Plugin, *plugintest.API) {
+		p := NewPlugin()
+		p.setConfiguration(&Configuration{EnterpriseBaseURL: server.URL})
+		p.BotUserID = "bot"
+		api := &plugintest.API{}
+		api.On("GetUser", "user1").Return(&model.User{Id: "user1", Locale: "en"}, nil)
+		api.On("GetDirectChannel", "user1", "bot").Return(&model.Channel{Id: "dm"}, nil)
+		api.On("CreatePost", mock.Anything).Return(func(post *model.Post) *model.Post { return post.Clone() }, nil)
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p, api
+	}
+
+	t.Run("ready to merge", func(t *testing.T) {
+		response = mergeStatus("head", "APPROVED")
+		p, api := setupPlugin()
+		api.On("KVGet", key).Return(tracked, nil)
+		api.On("KVSetWithOptions", key, mock.Anything, mock.Anything).Return(true, nil)
+
+		p.notifyReadyToMerge(context.Background(), "user1", info, &repo, 42, "head")
+
+		api.AssertCalled(t, "KVSetWithOptions", key, mock.MatchedBy(func(data []byte) bool {
+			var stored ciPullRequest
+			return json.Unmarshal(data, &stored) == nil && stored.ReadyToMergeNotified
+		}), mock.Anything)
+		api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.ChannelId == "dm" && post.Type == "custom_git_ready_to_merge"
+		}))
+	})
+
+	t.Run("head commit changed", func(t *testing.T) {
+		response = mergeStatus("newer", "APPROVED")
+		p, api := setupPlugin()
+
+		p.notifyReadyToMerge(context.Background(), "user1", info, &repo, 42, "head")
+
+		api.AssertNotCalled(t, "KVGet", mock.Anything)
+		api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	})
+
+	t.Run("not ready to merge", func(t *testing.T) {
+		response = mergeStatus("head", "REVIEW_REQUIRED")
+		p, api := setupPlugin()
+
+		p.notifyReadyToMerge(context.Background(), "user1", info, &repo, 42, "head")
+
+		api.AssertNotCalled(t, "KVGet", mock.Anything)
+		api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	})
+
+	t.Run("author is notified once", func(t *testing.T) {
+		response = mergeStatus("head", "APPROVED")
+		p, api := setupPlugin()
+		api.On("KVGet", key).Return(tracked, nil).Once()
+		api.On("KVGet", key).Return(notified, nil).Once()
+		api.On("KVSetWithOptions", key, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+		p.notifyReadyToMerge(context.Background(), "user1", info, &repo, 42, "head")
+		p.notifyReadyToMerge(context.Background(), "user1", info, &repo, 42, "head")
+
+		api.AssertNumberOfCalls(t, "KVSetWithOptions", 1)
+		api.AssertNumberOfCalls(t, "CreatePost", 1)
+	})
+}
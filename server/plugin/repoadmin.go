@@ -0,0 +1,141 @@
+package plugin
+
+import (
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// repositoryAdminActions are the actions of repository events posted with the repo_admin feature.
+// Repositories made public are posted from the public event instead, which GitHub sends as well.
+var repositoryAdminActions = map[string]bool{
+	"created":     true,
+	"deleted":     true,
+	"archived":    true,
+	"unarchived":  true,
+	"privatized":  true,
+	"transferred": true,
+}
+
+// MemberPermissionChange is the change of the permission of a collaborator.
+type MemberPermissionChange struct {
+	From *string `json:"from,omitempty"`
+	To   *string `json:"to,omitempty"`
+}
+
+// MemberChanges are the changes of a collaborator of a repository.
+type MemberChanges struct {
+	Permission *MemberPermissionChange `json:"permission,omitempty"`
+}
+
+// MemberEvent is triggered when a collaborator is added to or removed from a repository, or their
+// permission is changed. Unlike github.MemberEvent, it includes the changed permission.
+type MemberEvent struct {
+	Action  *string            `json:"action,omitempty"`
+	Member  *github.User       `json:"member,omitempty"`
+	Changes *MemberChanges     `json:"changes,omitempty"`
+	Repo    *github.Repository `json:"repository,omitempty"`
+	Sender  *github.User       `json:"sender,omitempty"`
+}
+
+func (c *MemberPermissionChange) GetFrom() string {
+	if c == nil || c.From == nil {
+		return ""
+	}
+	return *c.From
+}
+
+func (c *MemberPermissionChange) GetTo() string {
+	if c == nil || c.To == nil {
+		return ""
+	}
+	return *c.To
+}
+
+func (c *MemberChanges) GetPermission() *MemberPermissionChange {
+	if c == nil {
+		return nil
+	}
+	return c.Permission
+}
+
+func (e *MemberEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *MemberEvent) GetMember() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Member
+}
+
+func (e *MemberEvent) GetChanges() *MemberChanges {
+	if e == nil {
+		return nil
+	}
+	return e.Changes
+}
+
+func (e *MemberEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *MemberEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// repoAdminEvent is implemented by the events posted with the repo_admin feature.
+type repoAdminEvent interface {
+	GetRepo() *github.Repository
+	GetSender() *github.User
+}
+
+func (p *Plugin) postRepositoryAdminEvent(event *github.RepositoryEvent) {
+	if !repositoryAdminActions[event.GetAction()] {
+		return
+	}
+
+	p.postRepoAdminEvent(event, "repositoryChanged")
+}
+
+// postRepoAdminEvent posts changes of the access to and the protection of a repository to the
+// channels subscribed to it with the repo_admin feature.
+func (p *Plugin) postRepoAdminEvent(event repoAdminEvent, adminTemplate string) {
+	subs := p.GetSubscribedChannelsForRepository(event.GetRepo())
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.RepoAdmin() {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), adminTemplate, event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_repo_admin",
+			Message:   message,
+			ChannelId: sub.ChannelID,
+		}
+		p.createSubscriptionPost(sub, post, event)
+	}
+}
@@ -0,0 +1,51 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMemberEvent(t *testing.T) {
+	event, err := parseWebhook("member", []byte(`{
+		"action": "edited",
+		"member": {"login": "octocat"},
+		"changes": {"permission": {"from": "write", "to": "admin"}},
+		"repository": {"full_name": "mattermost/mattermost-plugin-github"},
+		"sender": {"login": "panda"}
+	}`))
+	require.NoError(t, err)
+
+	memberEvent, ok := event.(*MemberEvent)
+	require.True(t, ok)
+	assert.Equal(t, "octocat", memberEvent.GetMember().GetLogin())
+	assert.Equal(t, "write", memberEvent.GetChanges().GetPermission().GetFrom())
+	assert.Equal(t, "admin", memberEvent.GetChanges().GetPermission().GetTo())
+}
+
+func TestPostRepositoryAdminEvent(t *testing.T) {
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/mattermost-plugin-github": {
+			{ChannelID: "audit", Features: "repo_admin", Repository: "mattermost/mattermost-plugin-github"},
+			{ChannelID: "dev", Features: "pulls,issues", Repository: "mattermost/mattermost-plugin-github"},
+		},
+	}}
+	p, api := setupSubscriptionTestPlugin(t, subs)
+
+	repo := &github.Repository{FullName: github.String("mattermost/mattermost-plugin-github")}
+
+	p.postRepositoryAdminEvent(&github.RepositoryEvent{Action: github.String("archived"), Repo: repo})
+	// Edits and publicized repositories, which are posted from the public event, are skipped.
+	p.postRepositoryAdminEvent(&github.RepositoryEvent{Action: github.String("edited"), Repo: repo})
+	p.postRepositoryAdminEvent(&github.RepositoryEvent{Action: github.String("publicized"), Repo: repo})
+	p.postRepoAdminEvent(&github.PublicEvent{Repo: repo}, "repositoryPublicized")
+
+	api.AssertNumberOfCalls(t, "CreatePost", 2)
+	api.AssertNotCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId != "audit" || post.Type != "custom_git_repo_admin"
+	}))
+}
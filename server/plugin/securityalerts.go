@@ -0,0 +1,335 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	severityLow      = "low"
+	severityMedium   = "medium"
+	severityHigh     = "high"
+	severityCritical = "critical"
+)
+
+// severityRanks orders the severities of security alerts, from the least to the most severe.
+var severityRanks = map[string]int{
+	severityLow:      1,
+	severityMedium:   2,
+	severityHigh:     3,
+	severityCritical: 4,
+}
+
+// securityAlertActions are the actions of security alert events that are posted to channels: an
+// alert being opened, reopened or resolved.
+var securityAlertActions = map[string]bool{
+	"created":          true,
+	"reopened":         true,
+	"reopened_by_user": true,
+	"reintroduced":     true,
+	"fixed":            true,
+	"dismissed":        true,
+	"closed_by_user":   true,
+	"resolved":         true,
+}
+
+// normalizeSeverity returns the lowercase severity, or an error if it isn't a known severity.
+func normalizeSeverity(severity string) (string, error) {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	if _, ok := severityRanks[severity]; !ok {
+		return "", errors.Errorf("invalid severity %s", severity)
+	}
+
+	return severity, nil
+}
+
+// severityAtLeast returns true if severity is at least as severe as minimum. Every severity
+// passes an empty minimum, while unknown severities only pass an empty minimum.
+func severityAtLeast(severity, minimum string) bool {
+	if minimum == "" {
+		return true
+	}
+
+	return severityRanks[severity] >= severityRanks[minimum]
+}
+
+// The alerts below only hold the fields used by the templates. Unlike the events of the GitHub
+// client library, their fields are values, as a missing field renders the same as an empty one.
+
+// DependabotPackage is the package a Dependabot alert was raised for.
+type DependabotPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// DependabotDependency is the vulnerable dependency of a Dependabot alert.
+type DependabotDependency struct {
+	Package      DependabotPackage `json:"package"`
+	ManifestPath string            `json:"manifest_path"`
+}
+
+// SecurityAdvisory is the advisory a Dependabot alert was raised for.
+type SecurityAdvisory struct {
+	GHSAID   string `json:"ghsa_id"`
+	CVEID    string `json:"cve_id"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+}
+
+// FirstPatchedVersion is the first version of a package without the vulnerability.
+type FirstPatchedVersion struct {
+	Identifier string `json:"identifier"`
+}
+
+// SecurityVulnerability describes the affected versions of a vulnerable package.
+type SecurityVulnerability struct {
+	Severity               string              `json:"severity"`
+	VulnerableVersionRange string              `json:"vulnerable_version_range"`
+	FirstPatchedVersion    FirstPatchedVersion `json:"first_patched_version"`
+}
+
+// DependabotAlert is a vulnerable dependency found by Dependabot.
+type DependabotAlert struct {
+	Number                int                   `json:"number"`
+	State                 string                `json:"state"`
+	HTMLURL               string                `json:"html_url"`
+	Dependency            DependabotDependency  `json:"dependency"`
+	SecurityAdvisory      SecurityAdvisory      `json:"security_advisory"`
+	SecurityVulnerability SecurityVulnerability `json:"security_vulnerability"`
+}
+
+// CodeScanningRule is the rule a code scanning alert violates.
+type CodeScanningRule struct {
+	ID                    string `json:"id"`
+	Name                  string `json:"name"`
+	Description           string `json:"description"`
+	Severity              string `json:"severity"`
+	SecuritySeverityLevel string `json:"security_severity_level"`
+}
+
+// CodeScanningTool is the tool which raised a code scanning alert.
+type CodeScanningTool struct {
+	Name string `json:"name"`
+}
+
+// CodeScanningLocation is the place in the code a code scanning alert points at.
+type CodeScanningLocation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+}
+
+// CodeScanningAlertInstance is an occurrence of a code scanning alert on a branch.
+type CodeScanningAlertInstance struct {
+	Ref      string               `json:"ref"`
+	Location CodeScanningLocation `json:"location"`
+}
+
+// CodeScanningAlert is a problem found by code scanning.
+type CodeScanningAlert struct {
+	Number             int                       `json:"number"`
+	State              string                    `json:"state"`
+	HTMLURL            string                    `json:"html_url"`
+	Rule               CodeScanningRule          `json:"rule"`
+	Tool               CodeScanningTool          `json:"tool"`
+	MostRecentInstance CodeScanningAlertInstance `json:"most_recent_instance"`
+}
+
+// SecretScanningAlert is a secret found in a repository by secret scanning.
+type SecretScanningAlert struct {
+	Number                int    `json:"number"`
+	State                 string `json:"state"`
+	HTMLURL               string `json:"html_url"`
+	SecretType            string `json:"secret_type"`
+	SecretTypeDisplayName string `json:"secret_type_display_name"`
+	Resolution            string `json:"resolution"`
+}
+
+// DependabotAlertEvent is triggered when a Dependabot alert is created, dismissed, fixed or
+// reopened.
+type DependabotAlertEvent struct {
+	Action *string            `json:"action,omitempty"`
+	Alert  DependabotAlert    `json:"alert"`
+	Repo   *github.Repository `json:"repository,omitempty"`
+	Sender *github.User       `json:"sender,omitempty"`
+}
+
+// CodeScanningAlertEvent is triggered when a code scanning alert is created, fixed, closed or
+// reopened.
+type CodeScanningAlertEvent struct {
+	Action *string            `json:"action,omitempty"`
+	Alert  CodeScanningAlert  `json:"alert"`
+	Ref    string             `json:"ref"`
+	Repo   *github.Repository `json:"repository,omitempty"`
+	Sender *github.User       `json:"sender,omitempty"`
+}
+
+// SecretScanningAlertEvent is triggered when a secret scanning alert is created, resolved or
+// reopened.
+type SecretScanningAlertEvent struct {
+	Action *string             `json:"action,omitempty"`
+	Alert  SecretScanningAlert `json:"alert"`
+	Repo   *github.Repository  `json:"repository,omitempty"`
+	Sender *github.User        `json:"sender,omitempty"`
+}
+
+// securityAlertEvent is implemented by the events of all kinds of security alerts.
+type securityAlertEvent interface {
+	GetAction() string
+	GetRepo() *github.Repository
+	GetSender() *github.User
+	GetSeverity() string
+}
+
+func (e *DependabotAlertEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *DependabotAlertEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *DependabotAlertEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetSeverity returns the severity of the advisory, as reported by GitHub.
+func (e *DependabotAlertEvent) GetSeverity() string {
+	if e == nil {
+		return ""
+	}
+
+	severity := e.Alert.SecurityAdvisory.Severity
+	if severity == "" {
+		severity = e.Alert.SecurityVulnerability.Severity
+	}
+
+	severity = strings.ToLower(severity)
+	if severity == "moderate" {
+		return severityMedium
+	}
+	return severity
+}
+
+func (e *CodeScanningAlertEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *CodeScanningAlertEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *CodeScanningAlertEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetSeverity returns the security severity of the violated rule. Rules of tools that don't
+// report one are ranked by their general severity instead.
+func (e *CodeScanningAlertEvent) GetSeverity() string {
+	if e == nil {
+		return ""
+	}
+
+	if level := strings.ToLower(e.Alert.Rule.SecuritySeverityLevel); level != "" {
+		return level
+	}
+
+	switch strings.ToLower(e.Alert.Rule.Severity) {
+	case "error":
+		return severityHigh
+	case "warning":
+		return severityMedium
+	default:
+		return severityLow
+	}
+}
+
+func (e *SecretScanningAlertEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *SecretScanningAlertEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *SecretScanningAlertEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// GetSeverity returns critical, as GitHub doesn't rate leaked secrets and every one of them
+// needs to be revoked.
+func (e *SecretScanningAlertEvent) GetSeverity() string {
+	return severityCritical
+}
+
+// postSecurityAlertEvent posts a security alert to the channels subscribed to its repository with
+// the security feature and a minimum severity the alert reaches.
+func (p *Plugin) postSecurityAlertEvent(event securityAlertEvent, alertTemplate string) {
+	if !securityAlertActions[event.GetAction()] {
+		return
+	}
+
+	// For private repositories, GetSubscribedChannelsForRepository only returns the subscriptions
+	// whose creator can access the repository, so alerts never reach channels of other users.
+	subs := p.GetSubscribedChannelsForRepository(event.GetRepo())
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Security() {
+			continue
+		}
+
+		if !severityAtLeast(event.GetSeverity(), sub.Severity()) {
+			continue
+		}
+
+		if p.excludeConfigOrgMember(event.GetSender(), sub) {
+			continue
+		}
+
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), alertTemplate, event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			Type:      "custom_git_security_alert",
+			Message:   message,
+			ChannelId: sub.ChannelID,
+		}
+		p.createSubscriptionPost(sub, post, event)
+	}
+}
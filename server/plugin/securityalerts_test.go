@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const dependabotAlertPayload = `{
+	"action": "created",
+	"alert": {
+		"number": 3,
+		"state": "open",
+		"html_url": "https://github.com/mattermost/mattermost-plugin-github/security/dependabot/3",
+		"dependency": {"package": {"ecosystem": "npm", "name": "lodash"}, "manifest_path": "webapp/package-lock.json"},
+		"security_advisory": {"ghsa_id": "GHSA-35jh-r3h4-6jhm", "summary": "Command Injection in lodash", "severity": "high"},
+		"security_vulnerability": {"severity": "high", "vulnerable_version_range": "< 4.17.21", "first_patched_version": {"identifier": "4.17.21"}}
+	},
+	"repository": {"full_name": "mattermost/mattermost-plugin-github", "html_url": "https://github.com/mattermost/mattermost-plugin-github"},
+	"sender": {"login": "dependabot[bot]"}
+}`
+
+func TestSeverityAtLeast(t *testing.T) {
+	assert.True(t, severityAtLeast(severityLow, ""))
+	assert.True(t, severityAtLeast("", ""))
+	assert.True(t, severityAtLeast(severityCritical, severityHigh))
+	assert.True(t, severityAtLeast(severityHigh, severityHigh))
+	assert.False(t, severityAtLeast(severityMedium, severityHigh))
+	assert.False(t, severityAtLeast("", severityLow))
+}
+
+func TestSecurityAlertSeverity(t *testing.T) {
+	assert.Equal(t, severityMedium, (&DependabotAlertEvent{Alert: DependabotAlert{SecurityAdvisory: SecurityAdvisory{Severity: "moderate"}}}).GetSeverity())
+	assert.Equal(t, severityLow, (&DependabotAlertEvent{Alert: DependabotAlert{SecurityVulnerability: SecurityVulnerability{Severity: "low"}}}).GetSeverity())
+	assert.Equal(t, severityCritical, (&CodeScanningAlertEvent{Alert: CodeScanningAlert{Rule: CodeScanningRule{Severity: "error", SecuritySeverityLevel: "critical"}}}).GetSeverity())
+	assert.Equal(t, severityHigh, (&CodeScanningAlertEvent{Alert: CodeScanningAlert{Rule: CodeScanningRule{Severity: "error"}}}).GetSeverity())
+	assert.Equal(t, severityLow, (&CodeScanningAlertEvent{Alert: CodeScanningAlert{Rule: CodeScanningRule{Severity: "note"}}}).GetSeverity())
+	assert.Equal(t, severityCritical, (&SecretScanningAlertEvent{}).GetSeverity())
+}
+
+func TestSubscriptionFlagsSeverity(t *testing.T) {
+	flags := SubscriptionFlags{}
+	require.NoError(t, flags.AddFlag(flagSeverity, "High"))
+	assert.Equal(t, severityHigh, flags.Severity)
+	assert.Equal(t, "--severity high", flags.String())
+	assert.Equal(t, map[string]string{flagSeverity: severityHigh}, flags.ToMap())
+
+	assert.Error(t, flags.AddFlag(flagSeverity, "urgent"))
+}
+
+func TestPostSecurityAlertEvent(t *testing.T) {
+	event, err := parseWebhook("dependabot_alert", []byte(dependabotAlertPayload))
+	require.NoError(t, err)
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/mattermost-plugin-github": {
+			{ChannelID: "channel1", Features: "pulls,security", Repository: "mattermost/mattermost-plugin-github"},
+			{ChannelID: "channel2", Features: "security", Flags: SubscriptionFlags{Severity: severityCritical}, Repository: "mattermost/mattermost-plugin-github"},
+			{ChannelID: "channel3", Features: "pulls,issues", Repository: "mattermost/mattermost-plugin-github"},
+		},
+	}}
+	p, api := setupSubscriptionTestPlugin(t, subs)
+
+	p.postSecurityAlertEvent(event.(*DependabotAlertEvent), "dependabotAlert")
+
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+	api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "channel1" && post.Type == "custom_git_security_alert"
+	}))
+}
+
+func TestPostSecurityAlertEventPrivateRepository(t *testing.T) {
+	event, err := parseWebhook("secret_scanning_alert", []byte(`{
+		"action": "created",
+		"alert": {"number": 2, "secret_type": "github_personal_access_token"},
+		"repository": {"full_name": "mattermost/secret-repo", "private": true}
+	}`))
+	require.NoError(t, err)
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/secret-repo": {
+			{ChannelID: "channel1", Features: "security", Repository: "mattermost/secret-repo"},
+		},
+	}}
+	p, api := setupSubscriptionTestPlugin(t, subs)
+
+	// The creator of the subscription can't access the repository, so nothing is posted.
+	p.postSecurityAlertEvent(event.(*SecretScanningAlertEvent), "secretScanningAlert")
+
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
@@ -2,13 +2,21 @@ package plugin
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -16,11 +24,44 @@ const (
 	flagExcludeOrgMember = "exclude-org-member"
 	flagRenderStyle      = "render-style"
 	flagFeatures         = "features"
+	flagTopic            = "topic"
+	flagRateLimit        = "rate-limit"
+	flagQuietHours       = "quiet-hours"
+	flagTimezone         = "timezone"
+	flagLocale           = "locale"
+	flagPushDetail       = "push-detail"
+	flagSeverity         = "severity"
+	flagEnvironments     = "environments"
+
+	// repoTopicsKeyPrefix prefixes the KV keys caching the topics of a repository by its ID.
+	repoTopicsKeyPrefix = "_repotopics_"
+	// repoTopicsTTL bounds how long cached topics are used when no repository event refreshes them.
+	repoTopicsTTL = 24 * time.Hour
+
+	defaultFeatures = "pulls,issues,creates,deletes"
+
+	subscriptionsConfigFormatYAML = "yaml"
+	subscriptionsConfigFormatJSON = "json"
+
+	// subscriptionsConfigMaxLength is the maximum length of a configuration pasted into the import dialog.
+	subscriptionsConfigMaxLength = 100000
+
+	subscriptionChangeAdd    = "add"
+	subscriptionChangeUpdate = "update"
+	subscriptionChangeRemove = "remove"
 )
 
 type SubscriptionFlags struct {
 	ExcludeOrgMembers bool
 	RenderStyle       string
+	Topic             string
+	RateLimit         int
+	QuietHours        string
+	Timezone          string
+	Locale            string
+	PushDetail        bool
+	Severity          string
+	Environments      string
 }
 
 func (s *SubscriptionFlags) AddFlag(flag string, value string) error {
@@ -33,6 +74,55 @@ func (s *SubscriptionFlags) AddFlag(flag string, value string) error {
 		s.ExcludeOrgMembers = parsed
 	case flagRenderStyle:
 		s.RenderStyle = value
+	case flagTopic:
+		topics, err := normalizeTopicFlag(value)
+		if err != nil {
+			return err
+		}
+		s.Topic = topics
+	case flagRateLimit:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if parsed < 0 {
+			return errors.New("rate limit must not be negative")
+		}
+		s.RateLimit = parsed
+	case flagQuietHours:
+		if _, _, err := parseQuietHours(value); err != nil {
+			return err
+		}
+		s.QuietHours = value
+	case flagTimezone:
+		if _, err := time.LoadLocation(value); err != nil {
+			return err
+		}
+		s.Timezone = value
+	case flagLocale:
+		locale, err := normalizeLocale(value)
+		if err != nil {
+			return err
+		}
+		s.Locale = locale
+	case flagPushDetail:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		s.PushDetail = parsed
+	case flagSeverity:
+		severity, err := normalizeSeverity(value)
+		if err != nil {
+			return err
+		}
+		s.Severity = severity
+	case flagEnvironments:
+		environments, err := normalizeEnvironments(value)
+		if err != nil {
+			return err
+		}
+		s.Environments = environments
 	}
 
 	return nil
@@ -51,9 +141,97 @@ func (s SubscriptionFlags) String() string {
 		flags = append(flags, flag)
 	}
 
+	if s.Topic != "" {
+		flag := "--" + flagTopic + " " + s.Topic
+		flags = append(flags, flag)
+	}
+
+	if s.RateLimit != 0 {
+		flag := "--" + flagRateLimit + " " + strconv.Itoa(s.RateLimit)
+		flags = append(flags, flag)
+	}
+
+	if s.QuietHours != "" {
+		flag := "--" + flagQuietHours + " " + s.QuietHours
+		flags = append(flags, flag)
+	}
+
+	if s.Timezone != "" {
+		flag := "--" + flagTimezone + " " + s.Timezone
+		flags = append(flags, flag)
+	}
+
+	if s.Locale != "" {
+		flag := "--" + flagLocale + " " + s.Locale
+		flags = append(flags, flag)
+	}
+
+	if s.PushDetail {
+		flag := "--" + flagPushDetail + " true"
+		flags = append(flags, flag)
+	}
+
+	if s.Severity != "" {
+		flag := "--" + flagSeverity + " " + s.Severity
+		flags = append(flags, flag)
+	}
+
+	if s.Environments != "" {
+		flag := "--" + flagEnvironments + " " + s.Environments
+		flags = append(flags, flag)
+	}
+
 	return strings.Join(flags, ",")
 }
 
+// ToMap returns the flags that are set, keyed by their command line name.
+// The result can be fed back into AddFlag.
+func (s SubscriptionFlags) ToMap() map[string]string {
+	flags := map[string]string{}
+
+	if s.ExcludeOrgMembers {
+		flags[flagExcludeOrgMember] = "true"
+	}
+
+	if s.RenderStyle != "" {
+		flags[flagRenderStyle] = s.RenderStyle
+	}
+
+	if s.Topic != "" {
+		flags[flagTopic] = s.Topic
+	}
+
+	if s.RateLimit != 0 {
+		flags[flagRateLimit] = strconv.Itoa(s.RateLimit)
+	}
+
+	if s.QuietHours != "" {
+		flags[flagQuietHours] = s.QuietHours
+	}
+
+	if s.Timezone != "" {
+		flags[flagTimezone] = s.Timezone
+	}
+
+	if s.Locale != "" {
+		flags[flagLocale] = s.Locale
+	}
+
+	if s.PushDetail {
+		flags[flagPushDetail] = "true"
+	}
+
+	if s.Severity != "" {
+		flags[flagSeverity] = s.Severity
+	}
+
+	if s.Environments != "" {
+		flags[flagEnvironments] = s.Environments
+	}
+
+	return flags
+}
+
 type Subscription struct {
 	ChannelID  string
 	CreatorID  string
@@ -66,6 +244,84 @@ type Subscriptions struct {
 	Repositories map[string][]*Subscription
 }
 
+// SubscriptionInfo describes a subscription along with the resolved names of
+// its team, channel and creator. It is used by the admin tooling.
+type SubscriptionInfo struct {
+	Repository string `json:"repository"`
+	TeamID     string `json:"team_id"`
+	Team       string `json:"team"`
+	ChannelID  string `json:"channel_id"`
+	Channel    string `json:"channel"`
+	CreatorID  string `json:"creator_id"`
+	Creator    string `json:"creator"`
+	Features   string `json:"features"`
+	Flags      string `json:"flags"`
+}
+
+// SubscriptionsConfig is the declarative form of a set of subscriptions. Channels are referenced
+// by team and channel name so that the configuration can be versioned and moved between servers.
+type SubscriptionsConfig struct {
+	Subscriptions []*SubscriptionConfig `json:"subscriptions" yaml:"subscriptions"`
+}
+
+type SubscriptionConfig struct {
+	Repository string            `json:"repository" yaml:"repository"`
+	Team       string            `json:"team" yaml:"team"`
+	Channel    string            `json:"channel" yaml:"channel"`
+	Creator    string            `json:"creator,omitempty" yaml:"creator,omitempty"`
+	Features   string            `json:"features" yaml:"features"`
+	Flags      map[string]string `json:"flags,omitempty" yaml:"flags,omitempty"`
+}
+
+// SubscriptionChange describes a change made, or to be made, by a subscription import.
+type SubscriptionChange struct {
+	Action           string `json:"action"`
+	Repository       string `json:"repository"`
+	Team             string `json:"team"`
+	Channel          string `json:"channel"`
+	Features         string `json:"features"`
+	Flags            string `json:"flags,omitempty"`
+	PreviousFeatures string `json:"previous_features,omitempty"`
+	PreviousFlags    string `json:"previous_flags,omitempty"`
+}
+
+// SubscriptionFilter restricts a list of subscriptions to a repository,
+// organization or team. Empty fields match everything.
+type SubscriptionFilter struct {
+	Repository string
+	Team       string
+}
+
+// IsEmpty reports whether the filter matches every subscription.
+func (f SubscriptionFilter) IsEmpty() bool {
+	return f.Repository == "" && f.Team == ""
+}
+
+// Matches reports whether a subscription passes the filter. A repository
+// filter without a slash matches every subscription of that owner.
+func (f SubscriptionFilter) Matches(info *SubscriptionInfo) bool {
+	if f.Repository != "" {
+		filterRepo := strings.Trim(strings.ToLower(f.Repository), "/")
+		subRepo := strings.Trim(strings.ToLower(info.Repository), "/")
+		if strings.Contains(filterRepo, "/") {
+			if subRepo != filterRepo {
+				return false
+			}
+		} else if strings.Split(subRepo, "/")[0] != filterRepo {
+			return false
+		}
+	}
+
+	if f.Team != "" {
+		team := strings.ToLower(f.Team)
+		if team != strings.ToLower(info.Team) && team != strings.ToLower(info.TeamID) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (s *Subscription) Pulls() bool {
 	return strings.Contains(s.Features, featurePulls)
 }
@@ -106,6 +362,30 @@ func (s *Subscription) Stars() bool {
 	return strings.Contains(s.Features, featureStars)
 }
 
+func (s *Subscription) Discussions() bool {
+	return strings.Contains(s.Features, featureDiscussions)
+}
+
+func (s *Subscription) Security() bool {
+	return strings.Contains(s.Features, featureSecurity)
+}
+
+func (s *Subscription) Deployments() bool {
+	return strings.Contains(s.Features, featureDeployments)
+}
+
+func (s *Subscription) Milestones() bool {
+	return strings.Contains(s.Features, featureMilestones)
+}
+
+func (s *Subscription) Projects() bool {
+	return strings.Contains(s.Features, featureProjects)
+}
+
+func (s *Subscription) RepoAdmin() bool {
+	return strings.Contains(s.Features, featureRepoAdmin)
+}
+
 func (s *Subscription) Label() string {
 	if !strings.Contains(s.Features, "label:") {
 		return ""
@@ -127,6 +407,56 @@ func (s *Subscription) RenderStyle() string {
 	return s.Flags.RenderStyle
 }
 
+// Topic returns the comma-delimited list of topics the repositories of the subscription are
+// filtered by.
+func (s *Subscription) Topic() string {
+	return s.Flags.Topic
+}
+
+// matchesTopics returns true if events of a repository with the given topics are delivered to the
+// subscription. Subscriptions without a topic filter get the events of all repositories.
+func (s *Subscription) matchesTopics(topics []string) bool {
+	if s.Topic() == "" {
+		return true
+	}
+
+	for _, topic := range strings.Split(s.Topic(), ",") {
+		if containsValue(topics, topic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Subscription) RateLimit() int {
+	return s.Flags.RateLimit
+}
+
+func (s *Subscription) QuietHours() string {
+	return s.Flags.QuietHours
+}
+
+func (s *Subscription) Timezone() string {
+	return s.Flags.Timezone
+}
+
+func (s *Subscription) Locale() string {
+	return s.Flags.Locale
+}
+
+func (s *Subscription) PushDetail() bool {
+	return s.Flags.PushDetail
+}
+
+func (s *Subscription) Severity() string {
+	return s.Flags.Severity
+}
+
+func (s *Subscription) Environments() string {
+	return s.Flags.Environments
+}
+
 func (p *Plugin) Subscribe(ctx context.Context, githubClient *github.Client, userID, owner, repo, channelID, features string, flags SubscriptionFlags) error {
 	if owner == "" {
 		return errors.Errorf("invalid repository")
@@ -143,6 +473,10 @@ func (p *Plugin) Subscribe(ctx context.Context, githubClient *github.Client, use
 		return errors.New("Unable to set --exclude-org-member flag. The GitHub plugin is not locked to a single organization.")
 	}
 
+	if flags.Topic != "" && repo != "" && !isRepositoryPattern(repo) {
+		return errors.New("Unable to set --topic flag. It can only be used when subscribing to an organization or a repository pattern.")
+	}
+
 	var err error
 
 	if repo == "" {
@@ -155,6 +489,16 @@ func (p *Plugin) Subscribe(ctx context.Context, githubClient *github.Client, use
 				return errors.Errorf("Unknown organization %s", owner)
 			}
 		}
+	} else if isRepositoryPattern(repo) {
+		if err = validateRepositoryPattern(repo); err != nil {
+			return err
+		}
+
+		var matches []*github.Repository
+		matches, err = p.getRepositoriesMatchingPattern(ctx, githubClient, owner, repo)
+		if err == nil && len(matches) == 0 {
+			return errors.Errorf("pattern %s does not match any repository", fullNameFromOwnerAndRepo(owner, repo))
+		}
 	} else {
 		var ghRepo *github.Repository
 		ghRepo, _, err = githubClient.Repositories.Get(ctx, owner, repo)
@@ -184,6 +528,124 @@ func (p *Plugin) Subscribe(ctx context.Context, githubClient *github.Client, use
 	return nil
 }
 
+// getRepositoriesForOwner lists all repositories of an organization or, if owner is not an
+// organization, of a user.
+func (p *Plugin) getRepositoriesForOwner(ctx context.Context, githubClient *github.Client, owner string) ([]*github.Repository, error) {
+	opt := github.ListOptions{PerPage: 100}
+
+	repos, statusCode, err := getRepositoryListByOrg(ctx, owner, githubClient, opt)
+	if err != nil && statusCode == http.StatusNotFound {
+		return getRepositoryList(ctx, owner, githubClient, opt)
+	}
+
+	return repos, err
+}
+
+// getRepositoriesMatchingPattern returns the repositories of owner whose name matches the pattern.
+func (p *Plugin) getRepositoriesMatchingPattern(ctx context.Context, githubClient *github.Client, owner, pattern string) ([]*github.Repository, error) {
+	repos, err := p.getRepositoriesForOwner(ctx, githubClient, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*github.Repository
+	for _, repo := range repos {
+		if matchRepositoryPattern(pattern, repo.GetName()) {
+			matches = append(matches, repo)
+		}
+	}
+
+	return matches, nil
+}
+
+// getRepositoryTopics returns the lowercased topics of a repository. They are taken from the
+// event payload when included, served from the KV store when cached, or otherwise fetched with the
+// account of the first of userIDs that can read them. A non-nil slice is always returned.
+func (p *Plugin) getRepositoryTopics(repo *github.Repository, userIDs []string) []string {
+	if repo.Topics != nil {
+		return normalizeTopics(repo.Topics)
+	}
+
+	key := fmt.Sprintf("%s%d", repoTopicsKeyPrefix, repo.GetID())
+
+	var topics []string
+	if err := p.client.KV.Get(key, &topics); err != nil {
+		p.client.Log.Warn("Failed to get cached repository topics", "repo", repo.GetFullName(), "error", err.Error())
+	}
+	if topics != nil {
+		return topics
+	}
+
+	ctx := context.Background()
+	for _, userID := range userIDs {
+		info, apiErr := p.getGitHubUserInfo(userID)
+		if apiErr != nil {
+			continue
+		}
+
+		githubClient := p.githubConnectUser(ctx, info)
+		topics, _, err := githubClient.Repositories.ListAllTopics(ctx, repo.GetOwner().GetLogin(), repo.GetName())
+		if err != nil {
+			p.client.Log.Debug("Failed to fetch repository topics", "repo", repo.GetFullName(), "user_id", userID, "error", err.Error())
+			continue
+		}
+
+		return p.storeRepositoryTopics(repo.GetID(), topics)
+	}
+
+	p.client.Log.Warn("Failed to fetch repository topics with the account of any subscriber", "repo", repo.GetFullName())
+	return []string{}
+}
+
+// normalizeTopicFlag returns the comma-delimited list of topics in lowercase, without blanks and
+// empty entries.
+func normalizeTopicFlag(value string) (string, error) {
+	var topics []string
+	for _, topic := range strings.Split(value, ",") {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if topic == "" || containsValue(topics, topic) {
+			continue
+		}
+		topics = append(topics, topic)
+	}
+
+	if len(topics) == 0 {
+		return "", errors.New("no topics given")
+	}
+
+	return strings.Join(topics, ","), nil
+}
+
+// normalizeTopics returns the topics lowercased.
+func normalizeTopics(topics []string) []string {
+	normalized := make([]string, len(topics))
+	for i, topic := range topics {
+		normalized[i] = strings.ToLower(topic)
+	}
+
+	return normalized
+}
+
+// storeRepositoryTopics caches the topics of the repository with the given ID and returns
+// them lowercased.
+func (p *Plugin) storeRepositoryTopics(repoID int64, topics []string) []string {
+	normalized := normalizeTopics(topics)
+
+	key := fmt.Sprintf("%s%d", repoTopicsKeyPrefix, repoID)
+	if _, err := p.client.KV.Set(key, normalized, pluginapi.SetExpiry(repoTopicsTTL)); err != nil {
+		p.client.Log.Warn("Failed to cache repository topics", "error", err.Error())
+	}
+
+	return normalized
+}
+
+// removeRepositoryTopics drops the cached topics of the repository with the given ID.
+func (p *Plugin) removeRepositoryTopics(repoID int64) {
+	if err := p.client.KV.Delete(fmt.Sprintf("%s%d", repoTopicsKeyPrefix, repoID)); err != nil {
+		p.client.Log.Warn("Failed to remove cached repository topics", "error", err.Error())
+	}
+}
+
 func (p *Plugin) SubscribeOrg(ctx context.Context, githubClient *github.Client, userID, org, channelID, features string, flags SubscriptionFlags) error {
 	if org == "" {
 		return errors.New("invalid organization")
@@ -218,6 +680,132 @@ func (p *Plugin) GetSubscriptionsByChannel(channelID string) ([]*Subscription, e
 	return filteredSubs, nil
 }
 
+// GetAllSubscriptions returns every stored subscription across all channels,
+// sorted by repository and then by channel.
+func (p *Plugin) GetAllSubscriptions() ([]*Subscription, error) {
+	var allSubs []*Subscription
+	subs, err := p.GetSubscriptions()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get subscriptions")
+	}
+
+	for repo, v := range subs.Repositories {
+		for _, s := range v {
+			// this is needed to be backwards compatible
+			if len(s.Repository) == 0 {
+				s.Repository = repo
+			}
+			allSubs = append(allSubs, s)
+		}
+	}
+
+	sort.Slice(allSubs, func(i, j int) bool {
+		if allSubs[i].Repository != allSubs[j].Repository {
+			return allSubs[i].Repository < allSubs[j].Repository
+		}
+		return allSubs[i].ChannelID < allSubs[j].ChannelID
+	})
+
+	return allSubs, nil
+}
+
+// GetSubscriptionInfos returns the description of every subscription matching the filter.
+func (p *Plugin) GetSubscriptionInfos(filter SubscriptionFilter) ([]*SubscriptionInfo, error) {
+	subs, err := p.GetAllSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []*SubscriptionInfo{}
+	for _, info := range p.describeSubscriptions(subs) {
+		if filter.Matches(info) {
+			infos = append(infos, info)
+		}
+	}
+
+	return infos, nil
+}
+
+// describeSubscriptions resolves the team, channel and creator names of the given subscriptions.
+// Lookup failures are logged and leave the corresponding names empty.
+func (p *Plugin) describeSubscriptions(subs []*Subscription) []*SubscriptionInfo {
+	channels := map[string]*model.Channel{}
+	teams := map[string]*model.Team{}
+	usernames := map[string]string{}
+
+	infos := make([]*SubscriptionInfo, 0, len(subs))
+	for _, sub := range subs {
+		info := &SubscriptionInfo{
+			Repository: strings.Trim(sub.Repository, "/"),
+			ChannelID:  sub.ChannelID,
+			CreatorID:  sub.CreatorID,
+			Features:   sub.Features,
+			Flags:      sub.Flags.String(),
+		}
+
+		channel, ok := channels[sub.ChannelID]
+		if !ok {
+			var err error
+			channel, err = p.client.Channel.Get(sub.ChannelID)
+			if err != nil {
+				p.client.Log.Warn("Failed to get channel for subscription", "channelID", sub.ChannelID, "error", err.Error())
+			}
+			channels[sub.ChannelID] = channel
+		}
+
+		if channel != nil {
+			info.Channel = channel.Name
+			info.TeamID = channel.TeamId
+
+			team, ok := teams[channel.TeamId]
+			if !ok && channel.TeamId != "" {
+				var err error
+				team, err = p.client.Team.Get(channel.TeamId)
+				if err != nil {
+					p.client.Log.Warn("Failed to get team for subscription", "teamID", channel.TeamId, "error", err.Error())
+				}
+				teams[channel.TeamId] = team
+			}
+			if team != nil {
+				info.Team = team.Name
+			}
+		}
+
+		username, ok := usernames[sub.CreatorID]
+		if !ok && sub.CreatorID != "" {
+			user, err := p.client.User.Get(sub.CreatorID)
+			if err != nil {
+				p.client.Log.Warn("Failed to get creator of subscription", "userID", sub.CreatorID, "error", err.Error())
+			} else {
+				username = user.Username
+			}
+			usernames[sub.CreatorID] = username
+		}
+		info.Creator = username
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// writeSubscriptionInfosCSV writes the given subscription descriptions as CSV, including a header row.
+func writeSubscriptionInfosCSV(w io.Writer, infos []*SubscriptionInfo) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"repository", "team", "channel", "channel_id", "creator", "creator_id", "features", "flags"}); err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		if err := writer.Write([]string{info.Repository, info.Team, info.Channel, info.ChannelID, info.Creator, info.CreatorID, info.Features, info.Flags}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
 func (p *Plugin) AddSubscription(repo string, sub *Subscription) error {
 	subs, err := p.GetSubscriptions()
 	if err != nil {
@@ -231,6 +819,11 @@ func (p *Plugin) AddSubscription(repo string, sub *Subscription) error {
 		exists := false
 		for index, s := range repoSubs {
 			if s.ChannelID == sub.ChannelID {
+				// A channel holds a single subscription per organization, so updating it without
+				// --topic keeps the topics it is filtered by.
+				if sub.Topic() == "" {
+					sub.Flags.Topic = s.Topic()
+				}
 				repoSubs[index] = sub
 				exists = true
 				break
@@ -297,22 +890,438 @@ func (p *Plugin) GetSubscribedChannelsForRepository(repo *github.Repository) []*
 		subsForRepo = append(subsForRepo, subs.Repositories[orgKey]...)
 	}
 
+	// Add subscriptions for repository patterns of the organization
+	repoName := strings.TrimPrefix(name, orgKey)
+	for key, patternSubs := range subs.Repositories {
+		pattern := strings.TrimPrefix(key, orgKey)
+		if !strings.HasPrefix(key, orgKey) || !isRepositoryPattern(pattern) {
+			continue
+		}
+		if matchRepositoryPattern(pattern, repoName) {
+			subsForRepo = append(subsForRepo, patternSubs...)
+		}
+	}
+
 	if len(subsForRepo) == 0 {
 		return nil
 	}
 
-	subsToReturn := []*Subscription{}
-
+	allowedSubs := []*Subscription{}
+	var topicCreatorIDs []string
 	for _, sub := range subsForRepo {
 		if repo.GetPrivate() && !p.permissionToRepo(sub.CreatorID, name) {
 			continue
 		}
+		if sub.Topic() != "" && !containsValue(topicCreatorIDs, sub.CreatorID) {
+			topicCreatorIDs = append(topicCreatorIDs, sub.CreatorID)
+		}
+		allowedSubs = append(allowedSubs, sub)
+	}
+
+	if len(topicCreatorIDs) == 0 {
+		return allowedSubs
+	}
+
+	// The topics are read once for all subscriptions, with the account of any creator who can
+	// access the repository.
+	topics := p.getRepositoryTopics(repo, topicCreatorIDs)
+
+	subsToReturn := []*Subscription{}
+	for _, sub := range allowedSubs {
+		if !sub.matchesTopics(topics) {
+			continue
+		}
 		subsToReturn = append(subsToReturn, sub)
 	}
 
 	return subsToReturn
 }
 
+// RemoveSubscriptions deletes every subscription for which match returns true
+// and returns the number of removed subscriptions.
+func (p *Plugin) RemoveSubscriptions(match func(sub *Subscription) bool) (int, error) {
+	subs, err := p.GetSubscriptions()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get subscriptions")
+	}
+
+	removed := 0
+	for repo, repoSubs := range subs.Repositories {
+		kept := []*Subscription{}
+		for _, sub := range repoSubs {
+			if len(sub.Repository) == 0 {
+				sub.Repository = repo
+			}
+			if match(sub) {
+				removed++
+				continue
+			}
+			kept = append(kept, sub)
+		}
+
+		if len(kept) == 0 {
+			delete(subs.Repositories, repo)
+			continue
+		}
+		subs.Repositories[repo] = kept
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+
+	if err := p.StoreSubscriptions(subs); err != nil {
+		return 0, errors.Wrap(err, "could not store subscriptions")
+	}
+
+	return removed, nil
+}
+
+// RemoveFilteredSubscriptions deletes every subscription matching the filter
+// and returns the number of removed subscriptions.
+func (p *Plugin) RemoveFilteredSubscriptions(filter SubscriptionFilter) (int, error) {
+	infos, err := p.GetSubscriptionInfos(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	toRemove := map[string]bool{}
+	for _, info := range infos {
+		toRemove[info.Repository+" "+info.ChannelID] = true
+	}
+
+	return p.RemoveSubscriptions(func(sub *Subscription) bool {
+		return toRemove[strings.Trim(sub.Repository, "/")+" "+sub.ChannelID]
+	})
+}
+
+// ExportSubscriptionsConfig returns the subscriptions matching the filter in their declarative form.
+// Subscriptions of channels that are not part of a team can't be referenced by name and are skipped.
+func (p *Plugin) ExportSubscriptionsConfig(filter SubscriptionFilter) (*SubscriptionsConfig, error) {
+	subs, err := p.GetAllSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &SubscriptionsConfig{Subscriptions: []*SubscriptionConfig{}}
+	for i, info := range p.describeSubscriptions(subs) {
+		if !filter.Matches(info) {
+			continue
+		}
+
+		if info.Team == "" || info.Channel == "" {
+			p.client.Log.Debug("Skipping subscription of channel without team in export", "channelID", info.ChannelID, "repo", info.Repository)
+			continue
+		}
+
+		flags := subs[i].Flags.ToMap()
+		if len(flags) == 0 {
+			flags = nil
+		}
+
+		config.Subscriptions = append(config.Subscriptions, &SubscriptionConfig{
+			Repository: info.Repository,
+			Team:       info.Team,
+			Channel:    info.Channel,
+			Creator:    info.Creator,
+			Features:   info.Features,
+			Flags:      flags,
+		})
+	}
+
+	return config, nil
+}
+
+// ImportSubscriptionsConfig makes the stored subscriptions match the given configuration and returns the
+// changes that were made. Subscriptions without a creator are attributed to creatorID. Existing
+// subscriptions missing from the configuration are only removed if prune is set, except for those of
+// channels without a team, which are never exported. If dryRun is set, the changes are computed but not
+// stored.
+func (p *Plugin) ImportSubscriptionsConfig(config *SubscriptionsConfig, creatorID string, dryRun, prune bool) ([]*SubscriptionChange, error) {
+	desired, err := p.resolveSubscriptionsConfig(config, creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := p.GetSubscriptions()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get subscriptions")
+	}
+
+	changes := []*SubscriptionChange{}
+	desiredKeys := map[string]bool{}
+	for _, d := range desired {
+		sub := d.sub
+		desiredKeys[sub.Repository+" "+sub.ChannelID] = true
+
+		change := &SubscriptionChange{
+			Action:     subscriptionChangeAdd,
+			Repository: strings.Trim(sub.Repository, "/"),
+			Team:       d.config.Team,
+			Channel:    d.config.Channel,
+			Features:   sub.Features,
+			Flags:      sub.Flags.String(),
+		}
+
+		repoSubs := subs.Repositories[sub.Repository]
+		index := -1
+		for i, s := range repoSubs {
+			if s.ChannelID == sub.ChannelID {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			subs.Repositories[sub.Repository] = append(repoSubs, sub)
+			changes = append(changes, change)
+			continue
+		}
+
+		existing := repoSubs[index]
+		if d.config.Creator == "" && existing.CreatorID != "" {
+			// Keep the original creator unless the configuration explicitly sets one
+			sub.CreatorID = existing.CreatorID
+		}
+
+		if existing.Features == sub.Features && existing.Flags == sub.Flags && existing.CreatorID == sub.CreatorID {
+			continue
+		}
+
+		change.Action = subscriptionChangeUpdate
+		change.PreviousFeatures = existing.Features
+		change.PreviousFlags = existing.Flags.String()
+		repoSubs[index] = sub
+		changes = append(changes, change)
+	}
+
+	if prune {
+		var candidates []*Subscription
+		for repo, repoSubs := range subs.Repositories {
+			for _, sub := range repoSubs {
+				if len(sub.Repository) == 0 {
+					sub.Repository = repo
+				}
+				if !desiredKeys[repo+" "+sub.ChannelID] {
+					candidates = append(candidates, sub)
+				}
+			}
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].Repository != candidates[j].Repository {
+				return candidates[i].Repository < candidates[j].Repository
+			}
+			return candidates[i].ChannelID < candidates[j].ChannelID
+		})
+
+		// Subscriptions of channels without a team, like direct and group messages, are never
+		// exported, so they can't be part of the configuration and are kept.
+		removed := map[*Subscription]bool{}
+		for i, info := range p.describeSubscriptions(candidates) {
+			if info.Team == "" || info.Channel == "" {
+				continue
+			}
+
+			removed[candidates[i]] = true
+			changes = append(changes, &SubscriptionChange{
+				Action:     subscriptionChangeRemove,
+				Repository: info.Repository,
+				Team:       info.Team,
+				Channel:    info.Channel,
+				Features:   info.Features,
+				Flags:      info.Flags,
+			})
+		}
+
+		for repo, repoSubs := range subs.Repositories {
+			kept := []*Subscription{}
+			for _, sub := range repoSubs {
+				if !removed[sub] {
+					kept = append(kept, sub)
+				}
+			}
+
+			if len(kept) == 0 {
+				delete(subs.Repositories, repo)
+				continue
+			}
+			subs.Repositories[repo] = kept
+		}
+	}
+
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	if err := p.StoreSubscriptions(subs); err != nil {
+		return nil, errors.Wrap(err, "could not store subscriptions")
+	}
+
+	return changes, nil
+}
+
+type resolvedSubscriptionConfig struct {
+	config *SubscriptionConfig
+	sub    *Subscription
+}
+
+// resolveSubscriptionsConfig validates the configuration and resolves the referenced channels and users.
+// All problems are collected and returned as a single error.
+func (p *Plugin) resolveSubscriptionsConfig(config *SubscriptionsConfig, creatorID string) ([]*resolvedSubscriptionConfig, error) {
+	baseURL := p.getConfiguration().getBaseURL()
+
+	var problems []string
+	resolved := []*resolvedSubscriptionConfig{}
+	seen := map[string]bool{}
+	for i, c := range config.Subscriptions {
+		if c == nil {
+			problems = append(problems, fmt.Sprintf("entry %d: empty subscription", i+1))
+			continue
+		}
+
+		prefix := fmt.Sprintf("entry %d (%s)", i+1, c.Repository)
+
+		owner, repo := parseOwnerAndRepo(c.Repository, baseURL)
+		owner = strings.ToLower(owner)
+		repo = strings.ToLower(repo)
+		if owner == "" {
+			problems = append(problems, prefix+": invalid repository")
+			continue
+		}
+		if err := p.checkOrg(owner); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", prefix, err.Error()))
+			continue
+		}
+		if isRepositoryPattern(repo) {
+			if err := validateRepositoryPattern(repo); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s", prefix, err.Error()))
+				continue
+			}
+		}
+
+		features := c.Features
+		if features == "" {
+			features = defaultFeatures
+		}
+		if err := validateFeatureList(features); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", prefix, err.Error()))
+			continue
+		}
+
+		flags := SubscriptionFlags{}
+		flagsValid := true
+		for name, value := range c.Flags {
+			if err := flags.AddFlag(name, value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: unsupported value for flag %s", prefix, name))
+				flagsValid = false
+			}
+		}
+		if !flagsValid {
+			continue
+		}
+		if flags.ExcludeOrgMembers && !p.isOrganizationLocked() {
+			problems = append(problems, prefix+": unable to set exclude-org-member flag, the GitHub plugin is not locked to a single organization")
+			continue
+		}
+		if flags.Topic != "" && repo != "" && !isRepositoryPattern(repo) {
+			problems = append(problems, prefix+": the topic flag can only be used with an organization or a repository pattern")
+			continue
+		}
+
+		channel, err := p.client.Channel.GetByNameForTeamName(c.Team, c.Channel, false)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: unknown channel %s in team %s", prefix, c.Channel, c.Team))
+			continue
+		}
+
+		subCreatorID := creatorID
+		if c.Creator != "" {
+			user, err := p.client.User.GetByUsername(c.Creator)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: unknown creator %s", prefix, c.Creator))
+				continue
+			}
+			subCreatorID = user.Id
+		}
+
+		fullName := fullNameFromOwnerAndRepo(owner, repo)
+		key := fullName + " " + channel.Id
+		if seen[key] {
+			problems = append(problems, prefix+": duplicate subscription for the same channel")
+			continue
+		}
+		seen[key] = true
+
+		resolved = append(resolved, &resolvedSubscriptionConfig{
+			config: c,
+			sub: &Subscription{
+				ChannelID:  channel.Id,
+				CreatorID:  subCreatorID,
+				Features:   features,
+				Flags:      flags,
+				Repository: fullName,
+			},
+		})
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Errorf("invalid subscription configuration:\n* %s", strings.Join(problems, "\n* "))
+	}
+
+	return resolved, nil
+}
+
+// marshalSubscriptionsConfig serializes the configuration as YAML or JSON.
+func marshalSubscriptionsConfig(config *SubscriptionsConfig, format string) ([]byte, error) {
+	switch format {
+	case subscriptionsConfigFormatJSON:
+		return json.MarshalIndent(config, "", "  ")
+	case subscriptionsConfigFormatYAML, "":
+		return yaml.Marshal(config)
+	default:
+		return nil, errors.Errorf("unsupported format %s", format)
+	}
+}
+
+// parseSubscriptionsConfig parses a YAML or JSON subscription configuration.
+func parseSubscriptionsConfig(data []byte) (*SubscriptionsConfig, error) {
+	var config *SubscriptionsConfig
+
+	// JSON is a subset of YAML, so the YAML decoder handles both formats
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "could not parse subscription configuration")
+	}
+
+	if config == nil {
+		return &SubscriptionsConfig{}, nil
+	}
+
+	return config, nil
+}
+
+// formatSubscriptionChanges renders a list of changes as markdown.
+func formatSubscriptionChanges(changes []*SubscriptionChange) string {
+	if len(changes) == 0 {
+		return "No changes."
+	}
+
+	txt := ""
+	for _, change := range changes {
+		target := fmt.Sprintf("`%s` in ~%s (%s)", change.Repository, change.Channel, change.Team)
+		settings := strings.TrimSpace(change.Features + " " + change.Flags)
+		switch change.Action {
+		case subscriptionChangeUpdate:
+			previousSettings := strings.TrimSpace(change.PreviousFeatures + " " + change.PreviousFlags)
+			txt += fmt.Sprintf("* **update** %s: `%s` → `%s`\n", target, previousSettings, settings)
+		default:
+			txt += fmt.Sprintf("* **%s** %s: `%s`\n", change.Action, target, settings)
+		}
+	}
+
+	return txt
+}
+
 func (p *Plugin) Unsubscribe(channelID string, repo string) error {
 	config := p.getConfiguration()
 
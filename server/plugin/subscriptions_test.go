@@ -1,12 +1,17 @@
 package plugin
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
 
+	"github.com/google/go-github/v41/github"
 	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func CheckError(t *testing.T, wantErr bool, err error) {
@@ -31,6 +36,28 @@ func pluginWithMockedSubs(subscriptions []*Subscription) *Plugin {
 	return p
 }
 
+// setupSubscriptionTestPlugin returns a plugin with the given subscriptions stored, which posts as
+// the "bot" user. Created posts get the ID "<channel ID>-post".
+func setupSubscriptionTestPlugin(t *testing.T, subs Subscriptions) (*Plugin, *plugintest.API) {
+	data, err := json.Marshal(subs)
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("KVGet", SubscriptionsKey).Return(data, nil)
+	api.On("GetConfig").Return(&model.Config{})
+	api.On("CreatePost", mock.Anything).Return(func(post *model.Post) *model.Post {
+		created := post.Clone()
+		created.Id = post.ChannelId + "-post"
+		return created
+	}, nil)
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+	p.BotUserID = "bot"
+
+	return p, api
+}
+
 // wantedSubscriptions returns what should be returned after sorting by repo names
 func wantedSubscriptions(repoNames []string, chanelID string) []*Subscription {
 	var subs []*Subscription
@@ -116,3 +143,471 @@ func TestPlugin_GetSubscriptionsByChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugin_GetAllSubscriptions(t *testing.T) {
+	p := pluginWithMockedSubs([]*Subscription{
+		{
+			ChannelID:  "2",
+			Repository: "b",
+		},
+		{
+			ChannelID:  "1",
+			Repository: "b",
+		},
+		{
+			ChannelID:  "3",
+			Repository: "a",
+		},
+	})
+
+	got, err := p.GetAllSubscriptions()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Subscription{
+		{ChannelID: "3", Repository: "a"},
+		{ChannelID: "1", Repository: "b"},
+		{ChannelID: "2", Repository: "b"},
+	}, got)
+}
+
+func TestSubscriptionFilter_Matches(t *testing.T) {
+	info := &SubscriptionInfo{
+		Repository: "mattermost/mattermost-server",
+		TeamID:     "teamid",
+		Team:       "core",
+	}
+
+	tests := []struct {
+		name   string
+		filter SubscriptionFilter
+		want   bool
+	}{
+		{
+			name:   "empty filter",
+			filter: SubscriptionFilter{},
+			want:   true,
+		},
+		{
+			name:   "matching repository",
+			filter: SubscriptionFilter{Repository: "Mattermost/Mattermost-Server"},
+			want:   true,
+		},
+		{
+			name:   "other repository",
+			filter: SubscriptionFilter{Repository: "mattermost/mattermost-webapp"},
+			want:   false,
+		},
+		{
+			name:   "matching owner",
+			filter: SubscriptionFilter{Repository: "mattermost"},
+			want:   true,
+		},
+		{
+			name:   "owner prefix only",
+			filter: SubscriptionFilter{Repository: "matter"},
+			want:   false,
+		},
+		{
+			name:   "matching team name",
+			filter: SubscriptionFilter{Team: "core"},
+			want:   true,
+		},
+		{
+			name:   "matching team id",
+			filter: SubscriptionFilter{Team: "teamid"},
+			want:   true,
+		},
+		{
+			name:   "matching repository, other team",
+			filter: SubscriptionFilter{Repository: "mattermost", Team: "other"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(info))
+		})
+	}
+
+	t.Run("organization subscription", func(t *testing.T) {
+		orgInfo := &SubscriptionInfo{Repository: "mattermost"}
+		assert.True(t, SubscriptionFilter{Repository: "mattermost"}.Matches(orgInfo))
+		assert.False(t, SubscriptionFilter{Repository: "mattermost/mattermost-server"}.Matches(orgInfo))
+	})
+}
+
+func TestWriteSubscriptionInfosCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeSubscriptionInfosCSV(&buf, []*SubscriptionInfo{
+		{
+			Repository: "mattermost/mattermost-server",
+			Team:       "core",
+			Channel:    "town-square",
+			ChannelID:  "channelid",
+			Creator:    "admin",
+			CreatorID:  "userid",
+			Features:   "pulls,issues",
+			Flags:      "--render-style collapsed",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "repository,team,channel,channel_id,creator,creator_id,features,flags\n"+
+		"mattermost/mattermost-server,core,town-square,channelid,admin,userid,\"pulls,issues\",--render-style collapsed\n", buf.String())
+}
+
+func TestParseSubscriptionsConfig(t *testing.T) {
+	expected := &SubscriptionsConfig{
+		Subscriptions: []*SubscriptionConfig{
+			{
+				Repository: "mattermost/mattermost-server",
+				Team:       "core",
+				Channel:    "town-square",
+				Features:   "pulls,issues",
+				Flags:      map[string]string{"render-style": "collapsed"},
+			},
+		},
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		config, err := parseSubscriptionsConfig([]byte(`
+subscriptions:
+  - repository: mattermost/mattermost-server
+    team: core
+    channel: town-square
+    features: pulls,issues
+    flags:
+      render-style: collapsed
+`))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, config)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		config, err := parseSubscriptionsConfig([]byte(`{"subscriptions": [{"repository": "mattermost/mattermost-server", "team": "core", "channel": "town-square", "features": "pulls,issues", "flags": {"render-style": "collapsed"}}]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, config)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, format := range []string{subscriptionsConfigFormatYAML, subscriptionsConfigFormatJSON} {
+			data, err := marshalSubscriptionsConfig(expected, format)
+			assert.NoError(t, err)
+
+			config, err := parseSubscriptionsConfig(data)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, config)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseSubscriptionsConfig([]byte("subscriptions: ["))
+		assert.Error(t, err)
+	})
+}
+
+func TestPlugin_ImportSubscriptionsConfig(t *testing.T) {
+	setupPlugin := func() (*Plugin, *plugintest.API) {
+		p := NewPlugin()
+		p.setConfiguration(&Configuration{})
+		api := &plugintest.API{}
+
+		subs := Subscriptions{Repositories: map[string][]*Subscription{
+			"mattermost/mattermost-server": {
+				{ChannelID: "channel1", CreatorID: "user1", Features: "pulls", Repository: "mattermost/mattermost-server"},
+			},
+			"mattermost/old": {
+				{ChannelID: "channel2", CreatorID: "user1", Features: "issues", Repository: "mattermost/old"},
+			},
+		}}
+		jsn, _ := json.Marshal(subs)
+		api.On("KVGet", SubscriptionsKey).Return(jsn, nil)
+		api.On("GetChannelByNameForTeamName", "core", "town-square", false).Return(&model.Channel{Id: "channel1", Name: "town-square", TeamId: "team1"}, nil)
+		api.On("GetChannelByNameForTeamName", "core", "dev", false).Return(&model.Channel{Id: "channel3", Name: "dev", TeamId: "team1"}, nil)
+		api.On("GetChannelByNameForTeamName", "core", "missing", false).Return(nil, &model.AppError{Message: "not found"})
+		api.On("GetChannel", "channel2").Return(&model.Channel{Id: "channel2", Name: "old", TeamId: "team1"}, nil)
+		api.On("GetTeam", "team1").Return(&model.Team{Id: "team1", Name: "core"}, nil)
+		api.On("GetUser", "user1").Return(&model.User{Id: "user1", Username: "admin"}, nil)
+
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p, api
+	}
+
+	config := &SubscriptionsConfig{
+		Subscriptions: []*SubscriptionConfig{
+			{Repository: "mattermost/mattermost-server", Team: "core", Channel: "town-square", Features: "pulls,issues"},
+			{Repository: "mattermost", Team: "core", Channel: "dev", Features: "pushes"},
+		},
+	}
+
+	t.Run("dry run with prune", func(t *testing.T) {
+		p, api := setupPlugin()
+
+		changes, err := p.ImportSubscriptionsConfig(config, "user2", true, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []*SubscriptionChange{
+			{Action: subscriptionChangeUpdate, Repository: "mattermost/mattermost-server", Team: "core", Channel: "town-square", Features: "pulls,issues", PreviousFeatures: "pulls"},
+			{Action: subscriptionChangeAdd, Repository: "mattermost", Team: "core", Channel: "dev", Features: "pushes"},
+			{Action: subscriptionChangeRemove, Repository: "mattermost/old", Team: "core", Channel: "old", Features: "issues"},
+		}, changes)
+		api.AssertNotCalled(t, "KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("apply without prune", func(t *testing.T) {
+		p, api := setupPlugin()
+		api.On("KVSetWithOptions", SubscriptionsKey, mock.Anything, mock.Anything).Return(true, nil)
+
+		changes, err := p.ImportSubscriptionsConfig(config, "user2", false, false)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 2)
+
+		api.AssertCalled(t, "KVSetWithOptions", SubscriptionsKey, mock.MatchedBy(func(data []byte) bool {
+			var stored Subscriptions
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return false
+			}
+			return len(stored.Repositories["mattermost/old"]) == 1 &&
+				stored.Repositories["mattermost/mattermost-server"][0].CreatorID == "user1" &&
+				stored.Repositories["mattermost/"][0].CreatorID == "user2"
+		}), mock.Anything)
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		p, _ := setupPlugin()
+
+		_, err := p.ImportSubscriptionsConfig(&SubscriptionsConfig{
+			Subscriptions: []*SubscriptionConfig{
+				{Repository: "mattermost/mattermost-server", Team: "core", Channel: "missing"},
+				{Repository: "mattermost/mattermost-server", Team: "core", Channel: "town-square", Features: "unknown"},
+			},
+		}, "user2", true, false)
+		assert.EqualError(t, err, "invalid subscription configuration:\n"+
+			"* entry 1 (mattermost/mattermost-server): unknown channel missing in team core\n"+
+			"* entry 2 (mattermost/mattermost-server): Invalid feature(s) provided: unknown")
+	})
+}
+
+func TestPlugin_ExportImportSubscriptionsConfigWithPrune(t *testing.T) {
+	p := NewPlugin()
+	p.setConfiguration(&Configuration{})
+	api := &plugintest.API{}
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/mattermost-server": {
+			{ChannelID: "channel1", CreatorID: "user1", Features: "pulls", Repository: "mattermost/mattermost-server"},
+			{ChannelID: "dm1", CreatorID: "user1", Features: "issues", Repository: "mattermost/mattermost-server"},
+		},
+		"mattermost/": {
+			{ChannelID: "gm1", CreatorID: "user1", Features: "pushes", Repository: "mattermost/"},
+		},
+	}}
+	jsn, _ := json.Marshal(subs)
+	api.On("KVGet", SubscriptionsKey).Return(jsn, nil)
+	api.On("GetChannel", "channel1").Return(&model.Channel{Id: "channel1", Name: "town-square", TeamId: "team1"}, nil)
+	api.On("GetChannel", "dm1").Return(&model.Channel{Id: "dm1", Name: "user1__user2", Type: model.ChannelTypeDirect}, nil)
+	api.On("GetChannel", "gm1").Return(&model.Channel{Id: "gm1", Name: "group", Type: model.ChannelTypeGroup}, nil)
+	api.On("GetChannelByNameForTeamName", "core", "town-square", false).Return(&model.Channel{Id: "channel1", Name: "town-square", TeamId: "team1"}, nil)
+	api.On("GetTeam", "team1").Return(&model.Team{Id: "team1", Name: "core"}, nil)
+	api.On("GetUser", "user1").Return(&model.User{Id: "user1", Username: "admin"}, nil)
+	api.On("GetUserByUsername", "admin").Return(&model.User{Id: "user1", Username: "admin"}, nil)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	config, err := p.ExportSubscriptionsConfig(SubscriptionFilter{})
+	require.NoError(t, err)
+	require.Len(t, config.Subscriptions, 1)
+
+	changes, err := p.ImportSubscriptionsConfig(config, "user2", false, true)
+	assert.NoError(t, err)
+	assert.Empty(t, changes, "subscriptions of direct and group messages are kept")
+	api.AssertNotCalled(t, "KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPlugin_GetSubscribedChannelsForRepository(t *testing.T) {
+	p := NewPlugin()
+	api := &plugintest.API{}
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/mattermost-server": {{ChannelID: "exact", Repository: "mattermost/mattermost-server"}},
+		"mattermost/":                  {{ChannelID: "org", Repository: "mattermost/"}},
+		"mattermost/mattermost-*":      {{ChannelID: "prefix", Repository: "mattermost/mattermost-*"}},
+		"mattermost/*-server":          {{ChannelID: "suffix", Repository: "mattermost/*-server"}},
+		"mattermost/focalboard-*":      {{ChannelID: "other", Repository: "mattermost/focalboard-*"}},
+		"other/mattermost-*":           {{ChannelID: "other-org", Repository: "other/mattermost-*"}},
+	}}
+	jsn, _ := json.Marshal(subs)
+	api.On("KVGet", SubscriptionsKey).Return(jsn, nil)
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	var channelIDs []string
+	for _, sub := range p.GetSubscribedChannelsForRepository(&github.Repository{FullName: sToP("Mattermost/Mattermost-Server")}) {
+		channelIDs = append(channelIDs, sub.ChannelID)
+	}
+
+	assert.ElementsMatch(t, []string{"exact", "org", "prefix", "suffix"}, channelIDs)
+}
+
+func TestPlugin_GetSubscribedChannelsForRepositoryWithTopics(t *testing.T) {
+	p := NewPlugin()
+	api := &plugintest.API{}
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/": {
+			{ChannelID: "payments", Repository: "mattermost/", Flags: SubscriptionFlags{Topic: "team-payments"}},
+			{ChannelID: "search", Repository: "mattermost/", Flags: SubscriptionFlags{Topic: "team-search"}},
+			{ChannelID: "all", Repository: "mattermost/"},
+		},
+	}}
+	jsn, _ := json.Marshal(subs)
+	api.On("KVGet", SubscriptionsKey).Return(jsn, nil)
+	api.On("KVGet", repoTopicsKeyPrefix+"42").Return([]byte(`["team-payments","go"]`), nil)
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	repo := &github.Repository{ID: github.Int64(42), FullName: sToP("mattermost/payments-api")}
+
+	var channelIDs []string
+	for _, sub := range p.GetSubscribedChannelsForRepository(repo) {
+		channelIDs = append(channelIDs, sub.ChannelID)
+	}
+
+	assert.ElementsMatch(t, []string{"payments", "all"}, channelIDs)
+	api.AssertNumberOfCalls(t, "KVGet", 2)
+}
+
+func TestPlugin_GetSubscribedChannelsForRepositoryWithPayloadTopics(t *testing.T) {
+	p := NewPlugin()
+	api := &plugintest.API{}
+
+	subs := Subscriptions{Repositories: map[string][]*Subscription{
+		"mattermost/": {
+			{ChannelID: "payments", Repository: "mattermost/", Flags: SubscriptionFlags{Topic: "team-payments"}},
+			{ChannelID: "search", Repository: "mattermost/", Flags: SubscriptionFlags{Topic: "team-search"}},
+		},
+	}}
+	jsn, _ := json.Marshal(subs)
+	api.On("KVGet", SubscriptionsKey).Return(jsn, nil)
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	repo := &github.Repository{ID: github.Int64(42), FullName: sToP("mattermost/search-api"), Topics: []string{"Team-Search"}}
+
+	var channelIDs []string
+	for _, sub := range p.GetSubscribedChannelsForRepository(repo) {
+		channelIDs = append(channelIDs, sub.ChannelID)
+	}
+
+	assert.ElementsMatch(t, []string{"search"}, channelIDs)
+	api.AssertNumberOfCalls(t, "KVGet", 1)
+}
+
+func TestPlugin_AddSubscriptionWithTopic(t *testing.T) {
+	setupPlugin := func() (*Plugin, *plugintest.API) {
+		p := NewPlugin()
+		api := &plugintest.API{}
+
+		subs := Subscriptions{Repositories: map[string][]*Subscription{
+			"mattermost/": {
+				{ChannelID: "all", Repository: "mattermost/", Features: "pulls"},
+				{ChannelID: "payments", Repository: "mattermost/", Features: "pulls", Flags: SubscriptionFlags{Topic: "team-payments"}},
+			},
+		}}
+		jsn, _ := json.Marshal(subs)
+		api.On("KVGet", SubscriptionsKey).Return(jsn, nil)
+		api.On("KVSetWithOptions", SubscriptionsKey, mock.Anything, mock.Anything).Return(true, nil)
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p, api
+	}
+
+	storedTopics := func(api *plugintest.API, channelID, topic string) {
+		api.AssertCalled(t, "KVSetWithOptions", SubscriptionsKey, mock.MatchedBy(func(data []byte) bool {
+			var stored Subscriptions
+			if err := json.Unmarshal(data, &stored); err != nil || len(stored.Repositories["mattermost/"]) != 2 {
+				return false
+			}
+			for _, sub := range stored.Repositories["mattermost/"] {
+				if sub.ChannelID == channelID {
+					return sub.Topic() == topic
+				}
+			}
+			return false
+		}), mock.Anything)
+	}
+
+	t.Run("topic narrows an organization subscription", func(t *testing.T) {
+		p, api := setupPlugin()
+
+		err := p.AddSubscription("mattermost/", &Subscription{ChannelID: "all", Repository: "mattermost/", Flags: SubscriptionFlags{Topic: "team-search"}})
+		require.NoError(t, err)
+		storedTopics(api, "all", "team-search")
+	})
+
+	t.Run("topic replaces the topics of the subscription", func(t *testing.T) {
+		p, api := setupPlugin()
+
+		err := p.AddSubscription("mattermost/", &Subscription{ChannelID: "payments", Repository: "mattermost/", Flags: SubscriptionFlags{Topic: "team-payments,team-search"}})
+		require.NoError(t, err)
+		storedTopics(api, "payments", "team-payments,team-search")
+	})
+
+	t.Run("subscription without a topic keeps the topics", func(t *testing.T) {
+		p, api := setupPlugin()
+
+		err := p.AddSubscription("mattermost/", &Subscription{ChannelID: "payments", Repository: "mattermost/", Features: "issues"})
+		require.NoError(t, err)
+		storedTopics(api, "payments", "team-payments")
+		api.AssertCalled(t, "KVSetWithOptions", SubscriptionsKey, mock.MatchedBy(func(data []byte) bool {
+			var stored Subscriptions
+			return json.Unmarshal(data, &stored) == nil && stored.Repositories["mattermost/"][1].Features == "issues"
+		}), mock.Anything)
+	})
+}
+
+func TestSubscriptionMatchesTopics(t *testing.T) {
+	sub := &Subscription{Flags: SubscriptionFlags{Topic: "team-payments,team-search"}}
+	assert.True(t, sub.matchesTopics([]string{"go", "team-search"}))
+	assert.False(t, sub.matchesTopics([]string{"go"}))
+	assert.False(t, sub.matchesTopics(nil))
+	assert.True(t, (&Subscription{}).matchesTopics(nil))
+
+	var flags SubscriptionFlags
+	require.NoError(t, flags.AddFlag(flagTopic, " Team-Payments, team-search,,team-payments"))
+	assert.Equal(t, "team-payments,team-search", flags.Topic)
+	assert.Error(t, flags.AddFlag(flagTopic, " , "))
+}
+
+func TestPlugin_HandleRepositoryEvent(t *testing.T) {
+	setupPlugin := func() (*Plugin, *plugintest.API) {
+		p := NewPlugin()
+		api := &plugintest.API{}
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p, api
+	}
+
+	t.Run("topics are cached", func(t *testing.T) {
+		p, api := setupPlugin()
+		api.On("KVSetWithOptions", repoTopicsKeyPrefix+"42", []byte(`["team-payments"]`), mock.Anything).Return(true, nil)
+
+		p.handleRepositoryEvent(&github.RepositoryEvent{
+			Action: sToP(actionEdited),
+			Repo:   &github.Repository{ID: github.Int64(42), Topics: []string{"Team-Payments"}},
+		})
+
+		api.AssertExpectations(t)
+	})
+
+	t.Run("deleted repository is removed from cache", func(t *testing.T) {
+		p, api := setupPlugin()
+		api.On("KVSetWithOptions", repoTopicsKeyPrefix+"42", []byte(nil), mock.Anything).Return(true, nil)
+
+		p.handleRepositoryEvent(&github.RepositoryEvent{
+			Action: sToP(actionDeleted),
+			Repo:   &github.Repository{ID: github.Int64(42), Topics: []string{"team-payments"}},
+		})
+
+		api.AssertExpectations(t)
+	})
+}
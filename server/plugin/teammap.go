@@ -0,0 +1,312 @@
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	teamMappingsKey      = "_team_mappings"
+	teamMembersKeyPrefix = "_team_members_"
+
+	// teamMembersCacheTTL is how long the members of a GitHub team are cached.
+	teamMembersCacheTTL = time.Hour
+	teamMembersTimeout  = 30 * time.Second
+
+	groupMembersPerPage = 100
+)
+
+// TeamMapping routes mentions of and review requests for a GitHub team to Mattermost. Either a
+// channel or a user group is set.
+type TeamMapping struct {
+	// ChannelID is the channel notified instead of the members of the team.
+	ChannelID string `json:"channel_id,omitempty"`
+	// GroupID is the user group whose members are notified instead of the members of the team.
+	GroupID string `json:"group_id,omitempty"`
+}
+
+// CommentTeamMention is the data of the notification about a comment mentioning a GitHub team.
+type CommentTeamMention struct {
+	Team string
+	*github.IssueCommentEvent
+}
+
+// PullRequestTeamMention is the data of the notification about a pull request description
+// mentioning a GitHub team.
+type PullRequestTeamMention struct {
+	Team string
+	*github.PullRequestEvent
+}
+
+// DiscussionCommentTeamMention is the data of the notification about a discussion comment
+// mentioning a GitHub team.
+type DiscussionCommentTeamMention struct {
+	Team string
+	*DiscussionCommentEvent
+}
+
+// normalizeTeamName returns the lowercase org/team-slug form of a GitHub team, as mentioned with
+// or without a leading @, or an empty string if team is not of this form.
+func normalizeTeamName(team string) string {
+	team = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(team), "@"))
+
+	parts := strings.Split(team, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+
+	return team
+}
+
+// getTeamMappings returns the mappings of GitHub teams, keyed by their normalized name.
+func (p *Plugin) getTeamMappings() (map[string]*TeamMapping, error) {
+	var mappings map[string]*TeamMapping
+	if err := p.client.KV.Get(teamMappingsKey, &mappings); err != nil {
+		return nil, errors.Wrap(err, "failed to get team mappings")
+	}
+
+	if mappings == nil {
+		mappings = map[string]*TeamMapping{}
+	}
+
+	return mappings, nil
+}
+
+// getTeamMapping returns the mapping of a GitHub team, or nil if the team isn't mapped.
+func (p *Plugin) getTeamMapping(team string) *TeamMapping {
+	mappings, err := p.getTeamMappings()
+	if err != nil {
+		p.client.Log.Warn("Failed to get team mappings", "error", err.Error())
+		return nil
+	}
+
+	return mappings[normalizeTeamName(team)]
+}
+
+// updateTeamMappings atomically applies update to the mappings of GitHub teams.
+func (p *Plugin) updateTeamMappings(update func(mappings map[string]*TeamMapping)) error {
+	return p.client.KV.SetAtomicWithRetries(teamMappingsKey, func(oldValue []byte) (interface{}, error) {
+		mappings := map[string]*TeamMapping{}
+		if oldValue != nil {
+			if err := json.Unmarshal(oldValue, &mappings); err != nil {
+				return nil, err
+			}
+		}
+
+		update(mappings)
+
+		return mappings, nil
+	})
+}
+
+// getTeamMembers returns the logins of the members of a GitHub team, cached for an hour.
+func (p *Plugin) getTeamMembers(ctx context.Context, ghClient *github.Client, org, slug string) ([]string, error) {
+	hash := sha256.Sum256([]byte(strings.ToLower(org + "/" + slug)))
+	key := teamMembersKeyPrefix + hex.EncodeToString(hash[:16])
+
+	var members []string
+	if err := p.client.KV.Get(key, &members); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached team members")
+	}
+	if members != nil {
+		return members, nil
+	}
+
+	members = []string{}
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		users, resp, err := ghClient.Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list team members")
+		}
+
+		for _, user := range users {
+			members = append(members, user.GetLogin())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if _, err := p.client.KV.Set(key, members, pluginapi.SetExpiry(teamMembersCacheTTL)); err != nil {
+		p.client.Log.Warn("Failed to cache team members", "team", org+"/"+slug, "error", err.Error())
+	}
+
+	return members, nil
+}
+
+// getConnectedClient returns a client for the first of the GitHub users who connected their
+// account, or nil if none did.
+func (p *Plugin) getConnectedClient(logins ...string) *github.Client {
+	for _, login := range logins {
+		userID := p.getGitHubToUserIDMapping(login)
+		if userID == "" {
+			continue
+		}
+
+		info, apiErr := p.getGitHubUserInfo(userID)
+		if apiErr != nil {
+			continue
+		}
+
+		return p.githubConnectUser(context.Background(), info)
+	}
+
+	return nil
+}
+
+// handleTeamReviewRequest notifies about a review requested from a GitHub team. The review
+// request is posted in the channel or sent to the user group mapped to the team, or sent to each
+// member of the team.
+func (p *Plugin) handleTeamReviewRequest(event *github.PullRequestEvent) {
+	repo := event.GetRepo()
+	org := repo.GetOwner().GetLogin()
+	slug := event.GetRequestedTeam().GetSlug()
+	sender := event.GetSender().GetLogin()
+	author := event.GetPullRequest().GetUser().GetLogin()
+
+	if mapping := p.getTeamMapping(org + "/" + slug); mapping != nil {
+		p.notifyTeamMapping(mapping, repo, event.GetPullRequest().Labels, "teamReviewRequest", event, notificationTypeReviewRequests, "custom_git_review_request")
+		return
+	}
+
+	ghClient := p.getConnectedClient(sender, author)
+	if ghClient == nil {
+		p.client.Log.Debug("Unable to expand team review request without a connected GitHub account", "team", org+"/"+slug)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), teamMembersTimeout)
+	defer cancel()
+
+	members, err := p.getTeamMembers(ctx, ghClient, org, slug)
+	if err != nil {
+		p.client.Log.Warn("Failed to get team members", "team", org+"/"+slug, "error", err.Error())
+		return
+	}
+
+	for _, member := range members {
+		if member == sender || member == author {
+			continue
+		}
+
+		userID := p.getGitHubToUserIDMapping(member)
+		if userID == "" {
+			continue
+		}
+
+		if repo.GetPrivate() && !p.permissionToRepo(userID, repo.GetFullName()) {
+			continue
+		}
+
+		if !p.isNotificationEnabled(userID, notificationTypeReviewRequests) {
+			continue
+		}
+
+		if p.filterMutedReceiver(userID, repo.GetFullName(), event.GetPullRequest().Labels) == "" {
+			continue
+		}
+
+		message, err := p.renderUserTemplate(userID, "pullRequestNotification", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			continue
+		}
+
+		p.CreateBotDMPost(userID, message, "custom_git_review_request")
+		p.sendRefreshEvent(userID)
+	}
+}
+
+// notifyMentionedTeams notifies the channels and user groups mapped to the GitHub teams
+// mentioned in text. data returns the template data for a mentioned team.
+func (p *Plugin) notifyMentionedTeams(text string, repo *github.Repository, labels []*github.Label, templateName string, data func(team string) interface{}) {
+	teams := parseGitHubTeamsFromText(text)
+	if len(teams) == 0 {
+		return
+	}
+
+	mappings, err := p.getTeamMappings()
+	if err != nil {
+		p.client.Log.Warn("Failed to get team mappings", "error", err.Error())
+		return
+	}
+
+	for _, team := range teams {
+		if mapping := mappings[team]; mapping != nil {
+			p.notifyTeamMapping(mapping, repo, labels, templateName, data(team), notificationTypeMentions, "custom_git_mention")
+		}
+	}
+}
+
+// notifyTeamMapping posts a notification in the channel mapped to a GitHub team, or sends it to
+// each member of the mapped user group who didn't turn off or mute such notifications.
+func (p *Plugin) notifyTeamMapping(mapping *TeamMapping, repo *github.Repository, labels []*github.Label, templateName string, data interface{}, notificationType, postType string) {
+	if mapping.ChannelID != "" {
+		message, err := renderLocalizedTemplate(p.getServerLocale(), templateName, data)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post := &model.Post{
+			UserId:    p.BotUserID,
+			ChannelId: mapping.ChannelID,
+			Message:   message,
+			Type:      postType,
+		}
+		if err := p.client.Post.CreatePost(post); err != nil {
+			p.client.Log.Warn("Error creating team notification post", "channel_id", mapping.ChannelID, "error", err.Error())
+		}
+		return
+	}
+
+	if mapping.GroupID == "" {
+		return
+	}
+
+	for page := 0; ; page++ {
+		users, err := p.client.Group.GetMemberUsers(mapping.GroupID, page, groupMembersPerPage)
+		if err != nil {
+			p.client.Log.Warn("Failed to get group members", "group_id", mapping.GroupID, "error", err.Error())
+			return
+		}
+
+		for _, user := range users {
+			if repo.GetPrivate() && !p.permissionToRepo(user.Id, repo.GetFullName()) {
+				continue
+			}
+
+			if !p.isNotificationEnabled(user.Id, notificationType) {
+				continue
+			}
+
+			if p.filterMutedReceiver(user.Id, repo.GetFullName(), labels) == "" {
+				continue
+			}
+
+			message, err := p.renderUserTemplate(user.Id, templateName, data)
+			if err != nil {
+				p.client.Log.Warn("Failed to render template", "error", err.Error())
+				continue
+			}
+
+			p.CreateBotDMPost(user.Id, message, postType)
+		}
+
+		if len(users) < groupMembersPerPage {
+			return
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTeamName(t *testing.T) {
+	assert.Equal(t, "mattermost/core", normalizeTeamName("Mattermost/Core"))
+	assert.Equal(t, "mattermost/core", normalizeTeamName("@mattermost/core"))
+	assert.Equal(t, "", normalizeTeamName("core"))
+	assert.Equal(t, "", normalizeTeamName("mattermost/"))
+	assert.Equal(t, "", normalizeTeamName("mattermost/core/extra"))
+}
+
+func TestGetTeamMembers(t *testing.T) {
+	requests := 0
+	apiHandler := http.NewServeMux()
+	apiHandler.HandleFunc("/api-v3/orgs/mattermost/teams/core/members", func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"login": "carol"}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, "http://"+req.Host, req.URL.Path))
+		fmt.Fprint(w, `[{"login": "alice"}, {"login": "bob"}]`)
+	})
+	server := httptest.NewServer(apiHandler)
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	baseURL, _ := url.Parse(server.URL + baseURLPath + "/")
+	client.BaseURL = baseURL
+
+	setupPlugin := func(api *plugintest.API) *Plugin {
+		p := NewPlugin()
+		p.SetAPI(api)
+		p.client = pluginapi.NewClient(p.API, p.Driver)
+		return p
+	}
+
+	t.Run("members are fetched and cached", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", mock.AnythingOfType("string")).Return(nil, nil)
+		api.On("KVSetWithOptions", mock.AnythingOfType("string"), mock.Anything, model.PluginKVSetOptions{ExpireInSeconds: int64(teamMembersCacheTTL.Seconds())}).Return(true, nil)
+		p := setupPlugin(api)
+
+		members, err := p.getTeamMembers(context.Background(), client, "mattermost", "core")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob", "carol"}, members)
+		assert.Equal(t, 2, requests)
+
+		api.AssertCalled(t, "KVSetWithOptions", mock.AnythingOfType("string"), mock.MatchedBy(func(data []byte) bool {
+			var cached []string
+			return json.Unmarshal(data, &cached) == nil && assert.ObjectsAreEqual([]string{"alice", "bob", "carol"}, cached)
+		}), mock.Anything)
+	})
+
+	t.Run("cached members", func(t *testing.T) {
+		requests = 0
+		api := &plugintest.API{}
+		api.On("KVGet", mock.AnythingOfType("string")).Return([]byte(`["dave"]`), nil)
+		p := setupPlugin(api)
+
+		members, err := p.getTeamMembers(context.Background(), client, "mattermost", "core")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dave"}, members)
+		assert.Zero(t, requests)
+	})
+}
+
+func TestNotifyMentionedTeams(t *testing.T) {
+	data, err := json.Marshal(map[string]*TeamMapping{"mattermost/core": {ChannelID: "channel1"}})
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("KVGet", teamMappingsKey).Return(data, nil)
+	api.On("GetConfig").Return(&model.Config{})
+	api.On("CreatePost", mock.Anything).Return(func(post *model.Post) *model.Post { return post.Clone() }, nil)
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+	p.BotUserID = "bot"
+
+	event := &github.IssueCommentEvent{
+		Repo:    &github.Repository{FullName: github.String("mattermost/mattermost-server")},
+		Issue:   &github.Issue{Number: github.Int(1), Title: github.String("Implement git-get-head")},
+		Comment: &github.IssueComment{Body: github.String("@mattermost/core @mattermost/web please review")},
+		Sender:  &github.User{Login: github.String("octocat")},
+	}
+
+	p.notifyMentionedTeams(event.GetComment().GetBody(), event.GetRepo(), nil, "commentTeamMentionNotification", func(team string) interface{} {
+		return &CommentTeamMention{Team: team, IssueCommentEvent: event}
+	})
+
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+	api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "channel1" && post.UserId == "bot" && strings.Contains(post.Message, "mentioned **@mattermost/core**")
+	}))
+}
+
+func TestGetTeamMapping(t *testing.T) {
+	data, err := json.Marshal(map[string]*TeamMapping{"mattermost/core": {ChannelID: "channel1"}})
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("KVGet", teamMappingsKey).Return(data, nil)
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	assert.Equal(t, &TeamMapping{ChannelID: "channel1"}, p.getTeamMapping("Mattermost/Core"))
+	assert.Nil(t, p.getTeamMapping("mattermost/web"))
+}
@@ -54,30 +54,10 @@ func init() {
 	funcMap["lookupMattermostUsername"] = lookupMattermostUsername
 
 	// Trim away markdown comments in the text
-	funcMap["removeComments"] = func(body string) string {
-		if len(strings.TrimSpace(body)) == 0 {
-			return ""
-		}
-		return mdCommentRegex.ReplaceAllString(body, "")
-	}
+	funcMap["removeComments"] = removeComments
 
 	// Replace any GitHub username with its corresponding Mattermost username, if any
-	funcMap["replaceAllGitHubUsernames"] = func(body string) string {
-		return gitHubUsernameRegex.ReplaceAllStringFunc(body, func(matched string) string {
-			// The matched string contains the @ sign, and may contain a single
-			// character prepending the whole thing.
-			gitHubUsernameFirstCharIndex := strings.LastIndex(matched, "@") + 1
-			prefix := matched[:gitHubUsernameFirstCharIndex]
-			gitHubUsername := matched[gitHubUsernameFirstCharIndex:]
-
-			username := lookupMattermostUsername(gitHubUsername)
-			if username == "" {
-				return matched
-			}
-
-			return prefix + username
-		})
-	}
+	funcMap["replaceAllGitHubUsernames"] = replaceAllGitHubUsernames
 
 	// Quote the body
 	funcMap["quote"] = func(body string) string {
@@ -148,6 +128,26 @@ func init() {
 		`[#{{.GetNumber}} {{.GetTitle}}]({{.GetHTMLURL}})`,
 	))
 
+	// The discussion links to the corresponding discussion.
+	template.Must(masterTemplate.New("discussion").Parse(
+		`[#{{.GetNumber}} {{.GetTitle}}]({{.GetHTMLURL}})`,
+	))
+
+	// The deployment names the deployed ref, linked to its commit, and the target environment.
+	template.Must(masterTemplate.New("deployment").Parse(
+		`[` + "`{{.GetDeployment.GetRef}}`" + `]({{.GetRepo.GetHTMLURL}}/commit/{{.GetDeployment.GetSHA}}) to **{{.GetDeployment.GetEnvironment}}**`,
+	))
+
+	// The milestone links to the corresponding milestone.
+	template.Must(masterTemplate.New("milestone").Parse(
+		`[{{.GetTitle}}]({{.GetHTMLURL}})`,
+	))
+
+	// The milestoneProgress shows the share of closed issues and pull requests of a milestone.
+	template.Must(masterTemplate.New("milestoneProgress").Parse(
+		`**{{.GetProgress}}%** complete ({{.GetMilestone.GetClosedIssues}} closed, {{.GetMilestone.GetOpenIssues}} open)`,
+	))
+
 	// The eventRepoIssue links to the corresponding issue. Note that, for some events, the
 	// issue *is* a pull request, and so we still use .GetIssue and this template accordingly.
 	template.Must(masterTemplate.New("eventRepoIssue").Parse(
@@ -234,6 +234,10 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 
 	template.Must(masterTemplate.New("pullRequestMentionNotification").Funcs(funcMap).Parse(`
 {{template "user" .GetSender}} mentioned you on [{{.GetRepo.GetFullName}}#{{.GetPullRequest.GetNumber}}]({{.GetPullRequest.GetHTMLURL}}) - {{.GetPullRequest.GetTitle}}:
+{{.GetPullRequest.GetBody | trimBody | quote | replaceAllGitHubUsernames}}`))
+
+	template.Must(masterTemplate.New("pullRequestTeamMentionNotification").Funcs(funcMap).Parse(`
+{{template "user" .GetSender}} mentioned **@{{.Team}}** on [{{.GetRepo.GetFullName}}#{{.GetPullRequest.GetNumber}}]({{.GetPullRequest.GetHTMLURL}}) - {{.GetPullRequest.GetTitle}}:
 {{.GetPullRequest.GetBody | trimBody | quote | replaceAllGitHubUsernames}}`))
 
 	template.Must(masterTemplate.New("newIssue").Funcs(funcMap).Parse(`
@@ -271,6 +275,19 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 {{range .Commits -}}
 [` + "`{{.GetID | substr 0 6}}`" + `]({{.GetURL}}) {{.GetMessage}} - {{.GetCommitter.GetName}}
 {{end -}}
+`))
+
+	template.Must(masterTemplate.New("pushedCommitsDetail").Funcs(funcMap).Parse(`
+{{if .Event.GetForced}}:warning: **Force push** {{end}}{{template "user" .Event.GetSender}} {{if .Event.GetForced}}force-{{end}}pushed [{{.TotalCommits}} new commit{{if ne .TotalCommits 1}}s{{end}}]({{.Event.GetCompare}}) to [\[{{.Event.GetRepo.GetFullName}}:{{.Event.GetRef | trimRef}}\]]({{.Event.GetRepo.GetHTMLURL}}/tree/{{.Event.GetRef | trimRef}})
+{{- if .ProtectedBranch}} :lock: **protected branch**{{else if .DefaultBranch}} **default branch**{{end}}:
+{{range .Commits -}}
+[` + "`{{.GetID | substr 0 6}}`" + `]({{.GetURL}}) {{.GetMessage}} - {{.GetCommitter.GetName}}
+{{- if .HasStats}} ({{.FilesChanged}} file{{if ne .FilesChanged 1}}s{{end}}, +{{.Additions}} -{{.Deletions}}){{end}}
+{{end -}}
+{{if .MoreCommits}}...and {{.MoreCommits}} more commit{{if ne .MoreCommits 1}}s{{end}}. [Compare all changes]({{.Event.GetCompare}})
+{{end -}}
+{{if .HasStats}}**{{.FilesChanged}} file{{if ne .FilesChanged 1}}s{{end}} changed**, +{{.Additions}} -{{.Deletions}}
+{{end -}}
 `))
 
 	template.Must(masterTemplate.New("newCreateMessage").Funcs(funcMap).Parse(`
@@ -307,6 +324,11 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 	template.Must(masterTemplate.New("commentMentionNotification").Funcs(funcMap).Parse(`
 {{template "user" .GetSender}} mentioned you on [{{.GetRepo.GetFullName}}#{{.Issue.GetNumber}}]({{.GetComment.GetHTMLURL}}) - {{.Issue.GetTitle}}:
 {{.GetComment.GetBody | trimBody | quote | replaceAllGitHubUsernames}}
+`))
+
+	template.Must(masterTemplate.New("commentTeamMentionNotification").Funcs(funcMap).Parse(`
+{{template "user" .GetSender}} mentioned **@{{.Team}}** on [{{.GetRepo.GetFullName}}#{{.Issue.GetNumber}}]({{.GetComment.GetHTMLURL}}) - {{.Issue.GetTitle}}:
+{{.GetComment.GetBody | trimBody | quote | replaceAllGitHubUsernames}}
 `))
 
 	template.Must(masterTemplate.New("commentAuthorPullRequestNotification").Funcs(funcMap).Parse(`
@@ -341,7 +363,10 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 
 	template.Must(masterTemplate.New("pullRequestNotification").Funcs(funcMap).Parse(`
 {{template "user" .GetSender}}
-{{- if eq .GetAction "review_requested" }} requested your review on
+{{- if eq .GetAction "review_requested" }}
+    {{- if .RequestedTeam }} requested a review from your team {{.RequestedTeam.GetName}} on
+    {{- else }} requested your review on
+    {{- end }}
 {{- else if eq .GetAction "closed" }}
     {{- if .GetPullRequest.GetMerged }} merged your pull request
     {{- else }} closed your pull request
@@ -349,6 +374,10 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 {{- else if eq .GetAction "reopened" }} reopened your pull request
 {{- else if eq .GetAction "assigned" }} assigned you to pull request
 {{- end }} {{template "eventRepoPullRequestWithTitle" .}}
+`))
+
+	template.Must(masterTemplate.New("teamReviewRequest").Funcs(funcMap).Parse(`
+{{template "user" .GetSender}} requested a review from team **{{.GetRequestedTeam.GetName}}** on {{template "eventRepoPullRequestWithTitle" .}}
 `))
 
 	template.Must(masterTemplate.New("issueNotification").Funcs(funcMap).Parse(`
@@ -368,6 +397,19 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 {{if .GetReview.GetBody}}{{.Review.GetBody | trimBody | quote | replaceAllGitHubUsernames}}
 {{else}}{{end}}`))
 
+	template.Must(masterTemplate.New("ciStatusNotification").Funcs(funcMap).Parse(`
+{{- if eq .State "failure" }}:x: Checks failed on your pull request
+{{- else }}:white_check_mark: All checks passed on your pull request
+{{- end }} [{{.Repository}}#{{.Number}}]({{.URL}}) - {{.Title}}
+{{- with .FailedCheck }}
+Failed check: {{if .URL}}[{{.Name}}]({{.URL}}){{else}}{{.Name}}{{end}}
+{{- end }}
+`))
+
+	template.Must(masterTemplate.New("readyToMergeNotification").Funcs(funcMap).Parse(`
+:rocket: Your pull request [{{.Repository}}#{{.Number}}]({{.URL}}) - {{.Title}} is approved, all checks passed and it is ready to merge.
+`))
+
 	template.Must(masterTemplate.New("helpText").Parse("" +
 		"* `/github connect{{if .EnablePrivateRepo}}{{if not .ConnectToPrivateByDefault}} [private]{{end}}{{end}}` - Connect your Mattermost account to your GitHub account.\n" +
 		"{{if .EnablePrivateRepo}}{{if not .ConnectToPrivateByDefault}}" +
@@ -382,6 +424,7 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 		"* `/github todo` - Get a list of unread messages and pull requests awaiting your review\n" +
 		"* `/github subscriptions list` - Will list the current channel subscriptions\n" +
 		"* `/github subscriptions add owner[/repo] [flags]` - Subscribe the current channel to receive notifications about opened pull requests and issues for an organization or repository\n" +
+		"  * `repo` may contain `*` wildcards, e.g. `myorg/service-*`, to subscribe to all matching repositories\n" +
 		"  * `flags` currently supported:\n" +
 		"	 * `--features` - a comma-delimited list of one or more of the following:\n" +
 		"    	* `issues` - includes new and closed issues\n" +
@@ -393,20 +436,41 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 		"    	* `issue_comments` - includes new issue comments\n" +
 		"    	* `issue_creations` - includes new issues only \n" +
 		"    	* `pull_reviews` - includes pull request reviews\n" +
+		"    	* `discussions` - includes new discussions, answers and discussion comments\n" +
+		"    	* `security` - includes Dependabot, code scanning and secret scanning alerts\n" +
+		"    	* `deployments` - includes deployments and their status updates\n" +
+		"    	* `milestones` - includes created and closed milestones, and changes of their due date\n" +
+		"    	* `projects` - includes items moved between the columns of organization projects. Requires an organization subscription\n" +
+		"    	* `repo_admin` - includes changes of repository access and protection: created, deleted, archived and transferred repositories, visibility changes, collaborators, branch protection rules and forks\n" +
 		"    	* `label:<labelname>` - limit pull request and issue events to only this label. Must include `pulls` or `issues` in feature list when using a label.\n" +
 		"    	* Defaults to `pulls,issues,creates,deletes`\n\n" +
 		"    * `--exclude-org-member` - events triggered by organization members will not be delivered (the GitHub organization config should be set, otherwise this flag has not effect)\n" +
-		"    * `--render-style` - notifications will be delivered in the specified style (for example, the body of a pull request will not be displayed). Supported values are `collapsed`, `skip-body` or `default` (same as omitting the flag).\n" +
+		"    * `--render-style` - notifications will be delivered in the specified style (for example, the body of a pull request will not be displayed). Supported values are `collapsed`, `skip-body`, `attachment` or `default` (same as omitting the flag).\n" +
+		"    * `--rate-limit` - maximum number of notifications posted per minute. Further events are held back and summarized in a single post.\n" +
+		"    * `--quiet-hours` - notifications are held back during this time range, e.g. `22:00-07:00`, and summarized once it is over.\n" +
+		"    * `--timezone` - timezone of the quiet hours, e.g. `Europe/Berlin`. Defaults to `UTC`.\n" +
+		"    * `--locale` - language of the notifications, e.g. `de`. Defaults to the default language of the server.\n" +
+		"    * `--push-detail` - when `true`, push notifications include the files changed, additions and deletions of each commit, and highlight pushes to the default or protected branches.\n" +
+		"    * `--severity` - minimum severity of the security alerts to deliver: `low`, `medium`, `high` or `critical`.\n" +
+		"    * `--environments` - only deployments to these environments will be delivered, e.g. `production,staging`.\n" +
+		"    * `--topic` - only events from repositories with one of these topics will be delivered, e.g. `team-payments,team-search`. Can only be used with an organization or a repository pattern. Subscribing again without `--topic` keeps the topics, with `--topic` replaces them. Unsubscribe to remove the topic filter.\n" +
 		"* `/github subscriptions delete owner[/repo]` - Unsubscribe the current channel from a repository\n" +
 		"* `/github me` - Display the connected GitHub account\n" +
 		"* `/github settings [setting] [value]` - Update your user settings\n" +
 		"  * `setting` can be `notifications` or `reminders`\n" +
 		"  * `value` can be `on` or `off`\n" +
-		"* `/github mute` - Managed muted GitHub users. You'll not receive notifications for comments in your PRs and issues from those users.\n" +
-		"  * `/github mute list` - list your muted GitHub users\n" +
+		"* `/github settings notifications [type] [value]` - Turn a type of notifications on or off\n" +
+		"  * `type` can be `review-requests`, `mentions`, `comments`, `assignments`, `review-results`, `ci-failures` or `ready-to-merge`\n" +
+		"  * `value` can be `on` or `off`\n" +
+		"* `/github mute` - Managed muted GitHub users, repositories and labels. You'll not receive notifications for comments in your PRs and issues from muted users, nor notifications about muted repositories or PRs and issues with muted labels.\n" +
+		"  * `/github mute list` - list your muted GitHub users, repositories and labels\n" +
 		"  * `/github mute add [username]` - add a GitHub user to your muted list\n" +
+		"  * `/github mute repo [owner/repo]` - mute a repository\n" +
+		"  * `/github mute label [label]` - mute a label\n" +
 		"  * `/github mute delete [username]` - remove a GitHub user from your muted list\n" +
-		"  * `/github mute delete-all` - unmute all GitHub users\n"))
+		"  * `/github mute delete-repo [owner/repo]` - unmute a repository\n" +
+		"  * `/github mute delete-label [label]` - unmute a label\n" +
+		"  * `/github mute delete-all` - unmute all GitHub users, repositories and labels\n"))
 
 	template.Must(masterTemplate.New("newRepoStar").Funcs(funcMap).Parse(`
 {{template "repo" .GetRepo}}
@@ -414,6 +478,175 @@ Assignees: {{range $i, $el := .Assignees -}} {{- if $i}}, {{end}}{{template "use
 {{- else }} unstarred
 {{- end }} by {{template "user" .GetSender}}
 It now has **{{.GetRepo.GetStargazersCount}}** stars.`))
+
+	template.Must(masterTemplate.New("newDiscussion").Funcs(funcMap).Parse(`
+{{ if eq .Config.Style "collapsed" -}}
+{{template "repo" .Event.GetRepo}} New discussion {{template "discussion" .Event.GetDiscussion}} started by {{template "user" .Event.GetSender}}.
+{{- else -}}
+#### {{.Event.GetDiscussion.GetTitle}}
+##### [{{.Event.GetRepo.GetFullName}}#{{.Event.GetDiscussion.GetNumber}}]({{.Event.GetDiscussion.GetHTMLURL}})
+#new-discussion
+{{- with .Event.GetDiscussion.GetCategory}} in {{if .GetEmoji}}{{.GetEmoji}} {{end}}**{{.GetName}}**{{end}} by {{template "user" .Event.GetSender}}
+{{- if ne .Config.Style "skip-body" -}}
+{{- template "labels" dict "Labels" .Event.GetDiscussion.Labels "RepositoryURL" .Event.GetRepo.GetHTMLURL  }}
+
+{{.Event.GetDiscussion.GetBody | removeComments | replaceAllGitHubUsernames}}
+{{- end -}}
+{{- end }}
+`))
+
+	template.Must(masterTemplate.New("discussionAnswered").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}} marked [an answer]({{.GetAnswer.GetHTMLURL}}) by {{template "user" .GetAnswer.GetUser}} on {{template "discussion" .GetDiscussion}}:
+
+{{.GetAnswer.GetBody | trimBody | replaceAllGitHubUsernames}}
+`))
+
+	template.Must(masterTemplate.New("discussionComment").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} New comment by {{template "user" .GetSender}} on {{template "discussion" .GetDiscussion}}:
+
+{{.GetComment.GetBody | trimBody | replaceAllGitHubUsernames}}
+`))
+
+	template.Must(masterTemplate.New("discussionCommentMentionNotification").Funcs(funcMap).Parse(`
+{{template "user" .GetSender}} mentioned you on [{{.GetRepo.GetFullName}}#{{.GetDiscussion.GetNumber}}]({{.GetComment.GetHTMLURL}}) - {{.GetDiscussion.GetTitle}}:
+{{.GetComment.GetBody | trimBody | quote | replaceAllGitHubUsernames}}
+`))
+
+	template.Must(masterTemplate.New("discussionCommentTeamMentionNotification").Funcs(funcMap).Parse(`
+{{template "user" .GetSender}} mentioned **@{{.Team}}** on [{{.GetRepo.GetFullName}}#{{.GetDiscussion.GetNumber}}]({{.GetComment.GetHTMLURL}}) - {{.GetDiscussion.GetTitle}}:
+{{.GetComment.GetBody | trimBody | quote | replaceAllGitHubUsernames}}
+`))
+
+	template.Must(masterTemplate.New("dependabotAlert").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} Dependabot alert [#{{.Alert.Number}} {{.Alert.SecurityAdvisory.Summary}}]({{.Alert.HTMLURL}})
+{{- if eq .GetAction "created"}} opened
+{{- else if eq .GetAction "fixed"}} fixed
+{{- else if eq .GetAction "dismissed"}} dismissed by {{template "user" .GetSender}}
+{{- else}} reopened
+{{- end}} for ` + "`{{.Alert.Dependency.Package.Name}}`" + `
+{{- with .Alert.Dependency.Package.Ecosystem}} ({{.}}){{end}}
+{{- with .Alert.Dependency.ManifestPath}} in ` + "`{{.}}`" + `{{end}}
+Severity: **{{.GetSeverity}}**
+{{- with .Alert.SecurityVulnerability.VulnerableVersionRange}} · Vulnerable versions: ` + "`{{.}}`" + `{{end}}
+{{- with .Alert.SecurityVulnerability.FirstPatchedVersion.Identifier}} · Patched version: ` + "`{{.}}`" + `{{end}}
+`))
+
+	template.Must(masterTemplate.New("codeScanningAlert").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} Code scanning alert [#{{.Alert.Number}} {{.Alert.Rule.Description}}]({{.Alert.HTMLURL}})
+{{- with .Alert.Tool.Name}} from **{{.}}**{{end}}
+{{- if eq .GetAction "created"}} opened
+{{- else if eq .GetAction "fixed"}} fixed
+{{- else if eq .GetAction "closed_by_user"}} closed by {{template "user" .GetSender}}
+{{- else}} reopened
+{{- end}}
+{{- with .Alert.MostRecentInstance.Location}}{{if .Path}} in ` + "`{{.Path}}{{if .StartLine}}:{{.StartLine}}{{end}}`" + `{{end}}{{end}}
+Severity: **{{.GetSeverity}}**
+{{- with .Alert.Rule.ID}} · Rule: ` + "`{{.}}`" + `{{end}}
+`))
+
+	template.Must(masterTemplate.New("secretScanningAlert").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} Secret scanning alert [#{{.Alert.Number}} {{or .Alert.SecretTypeDisplayName .Alert.SecretType}}]({{.Alert.HTMLURL}})
+{{- if eq .GetAction "created"}} opened. The secret should be revoked.
+{{- else if eq .GetAction "resolved"}} resolved by {{template "user" .GetSender}}{{with .Alert.Resolution}} as **{{.}}**{{end}}.
+{{- else}} reopened by {{template "user" .GetSender}}.
+{{- end}}
+`))
+
+	template.Must(masterTemplate.New("newDeployment").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetDeployment.GetCreator}} started a deployment of {{template "deployment" .}}
+{{- with .GetDeployment.GetDescription}}: {{.}}{{end}}
+`))
+
+	template.Must(masterTemplate.New("deploymentPending").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} Deployment of {{template "deployment" .}} is {{if eq .GetDeploymentStatus.GetState "queued"}}queued{{else}}pending{{end}}.
+`))
+
+	template.Must(masterTemplate.New("deploymentInProgress").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} :hourglass_flowing_sand: Deployment of {{template "deployment" .}} is in progress.
+{{- with or .GetDeploymentStatus.GetLogURL .GetDeploymentStatus.GetTargetURL}} [View logs]({{.}}){{end}}
+`))
+
+	template.Must(masterTemplate.New("deploymentSuccess").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} :white_check_mark: Deployment of {{template "deployment" .}} succeeded.
+{{- with .GetDeploymentStatus.GetEnvironmentURL}} [Open environment]({{.}}){{end}}
+{{- with or .GetDeploymentStatus.GetLogURL .GetDeploymentStatus.GetTargetURL}} [View logs]({{.}}){{end}}
+`))
+
+	template.Must(masterTemplate.New("deploymentFailure").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} :x: Deployment of {{template "deployment" .}} {{if eq .GetDeploymentStatus.GetState "error"}}errored{{else}}failed{{end}}
+{{- with .GetDeploymentStatus.GetDescription}}: {{.}}{{else}}.{{end}}
+{{- with or .GetDeploymentStatus.GetLogURL .GetDeploymentStatus.GetTargetURL}} [View logs]({{.}}){{end}}
+`))
+
+	template.Must(masterTemplate.New("deploymentInactive").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} Deployment of {{template "deployment" .}} is no longer active.
+`))
+
+	template.Must(masterTemplate.New("newMilestone").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}} created the milestone {{template "milestone" .GetMilestone}}
+{{- if .GetMilestone.DueOn}}, due on **{{.GetMilestone.GetDueOn.Format "January 2, 2006"}}**{{end}}.
+`))
+
+	template.Must(masterTemplate.New("closedMilestone").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}} closed the milestone {{template "milestone" .GetMilestone}} at {{template "milestoneProgress" .}}.
+`))
+
+	template.Must(masterTemplate.New("milestoneDueDateChanged").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}}
+{{- if .GetMilestone.DueOn}} moved the due date of the milestone {{template "milestone" .GetMilestone}}
+{{- with .GetChanges.GetDueOn.GetFrom}} from **{{.Format "January 2, 2006"}}**{{end}} to **{{.GetMilestone.GetDueOn.Format "January 2, 2006"}}**
+{{- else}} removed the due date of the milestone {{template "milestone" .GetMilestone}}
+{{- end}}. It is {{template "milestoneProgress" .}}.
+`))
+
+	template.Must(masterTemplate.New("projectItemMoved").Funcs(funcMap).Parse(`
+[\[{{.Item.ProjectTitle}}\]]({{.Item.ProjectURL}}) {{template "user" .Event.GetSender}}
+{{- if .To}} moved{{else}} cleared the **{{.Field}}** of{{end}}
+{{- if .Item.URL}} [{{.Item.Repository}}#{{.Item.Number}} {{.Item.Title}}]({{.Item.URL}}){{else}} the draft **{{.Item.Title}}**{{end}}
+{{- if .To}}{{with .From}} from **{{.}}**{{end}} to **{{.To}}**{{if ne .Field "Status"}} in **{{.Field}}**{{end}}{{end}}.
+`))
+
+	template.Must(masterTemplate.New("repositoryChanged").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}}
+{{- if eq .GetAction "created"}} created the repository{{with .GetRepo.GetVisibility}} with **{{.}}** visibility{{end}}
+{{- else if eq .GetAction "deleted"}} deleted the repository
+{{- else if eq .GetAction "archived"}} archived the repository
+{{- else if eq .GetAction "unarchived"}} unarchived the repository
+{{- else if eq .GetAction "privatized"}} made the repository **private**
+{{- else if eq .GetAction "transferred"}} transferred the repository to **{{.GetRepo.GetOwner.GetLogin}}**
+{{- else}} changed the repository
+{{- end}}.
+`))
+
+	template.Must(masterTemplate.New("repositoryMemberChanged").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}}
+{{- if eq .GetAction "added"}} added {{template "user" .GetMember}} as a collaborator{{with .GetChanges.GetPermission.GetTo}} with **{{.}}** permission{{end}}
+{{- else if eq .GetAction "removed"}} removed {{template "user" .GetMember}} as a collaborator
+{{- else}} changed the permission of {{template "user" .GetMember}}
+{{- with .GetChanges.GetPermission.GetFrom}} from **{{.}}**{{end}}
+{{- with .GetChanges.GetPermission.GetTo}} to **{{.}}**{{end}}
+{{- end}}.
+`))
+
+	template.Must(masterTemplate.New("branchProtectionRuleChanged").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}}
+{{- if eq .GetAction "created"}} added
+{{- else if eq .GetAction "deleted"}} deleted
+{{- else}} changed
+{{- end}} the branch protection rule for ` + "`{{.GetRule.GetName}}`" + `
+{{- if ne .GetAction "deleted"}}{{with .GetRule}}
+Required approving reviews: **{{if and .GetRequiredApprovingReviewCount (ne .GetPullRequestReviewsEnforcementLevel "off")}}{{.GetRequiredApprovingReviewCount}}{{else}}none{{end}}**
+{{- if and .RequiredStatusChecks (ne .GetRequiredStatusChecksEnforcementLevel "off")}} · Required status checks: {{range $i, $check := .RequiredStatusChecks}}{{if $i}}, {{end}}` + "`{{$check}}`" + `{{end}}{{end}} · Enforced for administrators: **{{if .GetAdminEnforced}}yes{{else}}no{{end}}**
+{{- end}}{{end}}
+`))
+
+	template.Must(masterTemplate.New("repositoryPublicized").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}} made the repository **public**.
+`))
+
+	template.Must(masterTemplate.New("repositoryForked").Funcs(funcMap).Parse(`
+{{template "repo" .GetRepo}} {{template "user" .GetSender}} forked the repository to [{{.GetForkee.GetFullName}}]({{.GetForkee.GetHTMLURL}}).
+`))
 }
 
 func registerGitHubToUsernameMappingCallback(callback func(string) string) {
@@ -428,7 +661,45 @@ func lookupMattermostUsername(githubUsername string) string {
 	return gitHubToUsernameMappingCallback(githubUsername)
 }
 
+func removeComments(body string) string {
+	if len(strings.TrimSpace(body)) == 0 {
+		return ""
+	}
+	return mdCommentRegex.ReplaceAllString(body, "")
+}
+
+func replaceAllGitHubUsernames(body string) string {
+	return gitHubUsernameRegex.ReplaceAllStringFunc(body, func(matched string) string {
+		// The matched string contains the @ sign, and may contain a single
+		// character prepending the whole thing.
+		gitHubUsernameFirstCharIndex := strings.LastIndex(matched, "@") + 1
+		prefix := matched[:gitHubUsernameFirstCharIndex]
+		gitHubUsername := matched[gitHubUsernameFirstCharIndex:]
+
+		username := lookupMattermostUsername(gitHubUsername)
+		if username == "" {
+			return matched
+		}
+
+		return prefix + username
+	})
+}
+
+// renderTemplate renders the template with the given name, preferring an admin customized
+// version of it. If the customized template fails to render, the built-in one is used instead.
 func renderTemplate(name string, data interface{}) (string, error) {
+	if custom := getCustomTemplate(name); custom != nil {
+		var output bytes.Buffer
+		err := custom.Execute(&output, data)
+		if err == nil {
+			return output.String(), nil
+		}
+
+		if templateRenderErrorCallback != nil {
+			templateRenderErrorCallback(name, err)
+		}
+	}
+
 	var output bytes.Buffer
 	t := masterTemplate.Lookup(name)
 	if t == nil {
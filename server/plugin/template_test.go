@@ -7,6 +7,8 @@ import (
 
 	"github.com/google/go-github/v41/github"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-github/server/plugin/graphql"
 )
 
 var repo = github.Repository{
@@ -736,6 +738,87 @@ func TestPushedCommitsTemplate(t *testing.T) {
 	})
 }
 
+func TestPushedCommitsDetailTemplate(t *testing.T) {
+	commit := &github.HeadCommit{
+		ID:      sToP("a10867b14bb761a232cd80139fbd4c0d33264240"),
+		URL:     sToP("https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240"),
+		Message: sToP("Leverage git-get-head"),
+		Committer: &github.CommitAuthor{
+			Name: sToP("panda"),
+		},
+	}
+	pushEvent := func(forced bool) *github.PushEvent {
+		return &github.PushEvent{
+			Repo:    &pushEventRepository,
+			Sender:  &user,
+			Forced:  bToP(forced),
+			Commits: []*github.HeadCommit{commit},
+			Compare: sToP("https://github.com/mattermost/mattermost-plugin-github/compare/master...branch"),
+			Ref:     sToP("refs/heads/branch"),
+		}
+	}
+
+	t.Run("with stats", func(t *testing.T) {
+		expected := `
+[panda](https://github.com/panda) pushed [1 new commit](https://github.com/mattermost/mattermost-plugin-github/compare/master...branch) to [\[mattermost-plugin-github:branch\]](https://github.com/mattermost/mattermost-plugin-github/tree/branch):
+[` + "`a10867`" + `](https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240) Leverage git-get-head - panda (1 file, +10 -3)
+**1 file changed**, +10 -3
+`
+
+		actual, err := renderTemplate("pushedCommitsDetail", &PushDetail{
+			Event:        pushEvent(false),
+			Commits:      []*PushCommitDetail{{HeadCommit: commit, HasStats: true, FilesChanged: 1, Additions: 10, Deletions: 3}},
+			TotalCommits: 1,
+			HasStats:     true,
+			FilesChanged: 1,
+			Additions:    10,
+			Deletions:    3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("forced to default branch without stats", func(t *testing.T) {
+		expected := `
+:warning: **Force push** [panda](https://github.com/panda) force-pushed [1 new commit](https://github.com/mattermost/mattermost-plugin-github/compare/master...branch) to [\[mattermost-plugin-github:branch\]](https://github.com/mattermost/mattermost-plugin-github/tree/branch) **default branch**:
+[` + "`a10867`" + `](https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240) Leverage git-get-head - panda
+`
+
+		actual, err := renderTemplate("pushedCommitsDetail", &PushDetail{
+			Event:         pushEvent(true),
+			Commits:       []*PushCommitDetail{{HeadCommit: commit}},
+			TotalCommits:  1,
+			DefaultBranch: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("truncated push to protected branch", func(t *testing.T) {
+		expected := `
+[panda](https://github.com/panda) pushed [25 new commits](https://github.com/mattermost/mattermost-plugin-github/compare/master...branch) to [\[mattermost-plugin-github:branch\]](https://github.com/mattermost/mattermost-plugin-github/tree/branch) :lock: **protected branch**:
+[` + "`a10867`" + `](https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240) Leverage git-get-head - panda (2 files, +10 -3)
+...and 24 more commits. [Compare all changes](https://github.com/mattermost/mattermost-plugin-github/compare/master...branch)
+**2 files changed**, +10 -3
+`
+
+		actual, err := renderTemplate("pushedCommitsDetail", &PushDetail{
+			Event:           pushEvent(false),
+			Commits:         []*PushCommitDetail{{HeadCommit: commit, HasStats: true, FilesChanged: 2, Additions: 10, Deletions: 3}},
+			TotalCommits:    25,
+			MoreCommits:     24,
+			DefaultBranch:   true,
+			ProtectedBranch: true,
+			HasStats:        true,
+			FilesChanged:    2,
+			Additions:       10,
+			Deletions:       3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
 func TestCreateMessageTemplate(t *testing.T) {
 	expected := `
 [\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) branch [branchname](https://github.com/mattermost/mattermost-plugin-github/tree/branchname) created by [panda](https://github.com/panda)
@@ -1394,6 +1477,84 @@ func TestGitHubUsernameRegex(t *testing.T) {
 	}
 }
 
+func TestCIStatusNotificationTemplate(t *testing.T) {
+	notification := &CIStatusNotification{
+		Repository: "mattermost/mattermost-plugin-github",
+		Number:     42,
+		Title:      "Leverage git-get-head",
+		URL:        "https://github.com/mattermost/mattermost-plugin-github/pull/42",
+		State:      ciStateSuccess,
+	}
+
+	t.Run("passed", func(t *testing.T) {
+		expected := `:white_check_mark: All checks passed on your pull request [mattermost/mattermost-plugin-github#42](https://github.com/mattermost/mattermost-plugin-github/pull/42) - Leverage git-get-head
+`
+
+		actual, err := renderTemplate("ciStatusNotification", notification)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		failed := *notification
+		failed.State = ciStateFailure
+		failed.FailedCheck = &CICheck{Name: "build", URL: "https://github.com/mattermost/mattermost-plugin-github/runs/1"}
+
+		expected := `:x: Checks failed on your pull request [mattermost/mattermost-plugin-github#42](https://github.com/mattermost/mattermost-plugin-github/pull/42) - Leverage git-get-head
+Failed check: [build](https://github.com/mattermost/mattermost-plugin-github/runs/1)
+`
+
+		actual, err := renderTemplate("ciStatusNotification", &failed)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestTeamReviewRequestTemplates(t *testing.T) {
+	event := &github.PullRequestEvent{
+		Action:        sToP("review_requested"),
+		Repo:          &repo,
+		PullRequest:   &pullRequest,
+		Sender:        &user,
+		RequestedTeam: &github.Team{Name: sToP("Core"), Slug: sToP("core")},
+	}
+
+	t.Run("channel", func(t *testing.T) {
+		expected := `
+[panda](https://github.com/panda) requested a review from team **Core** on [mattermost-plugin-github#42](https://github.com/mattermost/mattermost-plugin-github/pull/42) - Leverage git-get-head
+`
+
+		actual, err := renderTemplate("teamReviewRequest", event)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("team member", func(t *testing.T) {
+		expected := `
+[panda](https://github.com/panda) requested a review from your team Core on [mattermost-plugin-github#42](https://github.com/mattermost/mattermost-plugin-github/pull/42) - Leverage git-get-head
+`
+
+		actual, err := renderTemplate("pullRequestNotification", event)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestReadyToMergeNotificationTemplate(t *testing.T) {
+	expected := `
+:rocket: Your pull request [mattermost/mattermost-plugin-github#42](https://github.com/mattermost/mattermost-plugin-github/pull/42) - Leverage git-get-head is approved, all checks passed and it is ready to merge.
+`
+
+	actual, err := renderTemplate("readyToMergeNotification", &ReadyToMergeNotification{
+		Repository: "mattermost/mattermost-plugin-github",
+		Number:     42,
+		Title:      "Leverage git-get-head",
+		URL:        "https://github.com/mattermost/mattermost-plugin-github/pull/42",
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
 func sToP(s string) *string {
 	return &s
 }
@@ -1409,3 +1570,379 @@ func tToP(t time.Time) *time.Time {
 func bToP(b bool) *bool {
 	return &b
 }
+
+func TestDiscussionTemplates(t *testing.T) {
+	discussion := &Discussion{
+		Number:   github.Int(7),
+		Title:    sToP("How to subscribe?"),
+		Body:     sToP("Is there a feature? cc @panda"),
+		HTMLURL:  sToP("https://github.com/mattermost/mattermost-plugin-github/discussions/7"),
+		Category: &DiscussionCategory{Name: sToP("Q&A"), Emoji: sToP(":pray:")},
+		Labels:   singleLabel,
+	}
+	comment := &DiscussionComment{
+		Body:    sToP("Use the discussions feature"),
+		HTMLURL: sToP("https://github.com/mattermost/mattermost-plugin-github/discussions/7#discussioncomment-1"),
+		User:    &user,
+	}
+	discussionEvent := &DiscussionEvent{Action: sToP("created"), Repo: &repo, Discussion: discussion, Sender: &user}
+	commentEvent := &DiscussionCommentEvent{Action: sToP("created"), Repo: &repo, Discussion: discussion, Comment: comment, Sender: &user}
+
+	t.Run("new discussion", func(t *testing.T) {
+		expected := `
+#### How to subscribe?
+##### [mattermost-plugin-github#7](https://github.com/mattermost/mattermost-plugin-github/discussions/7)
+#new-discussion in :pray: **Q&A** by [panda](https://github.com/panda)
+Labels: [` + "`Help Wanted`" + `](https://github.com/mattermost/mattermost-plugin-github/labels/Help%20Wanted)
+
+Is there a feature? cc @panda
+`
+
+		actual, err := renderTemplate("newDiscussion", GetEventWithRenderConfig(discussionEvent, nil))
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("new discussion, collapsed", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) New discussion [#7 How to subscribe?](https://github.com/mattermost/mattermost-plugin-github/discussions/7) started by [panda](https://github.com/panda).
+`
+
+		actual, err := renderTemplate("newDiscussion", &EventWithRenderConfig{Event: discussionEvent, Config: RenderConfig{Style: "collapsed"}})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("answered", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) marked [an answer](https://github.com/mattermost/mattermost-plugin-github/discussions/7#discussioncomment-1) by [panda](https://github.com/panda) on [#7 How to subscribe?](https://github.com/mattermost/mattermost-plugin-github/discussions/7):
+
+Use the discussions feature
+`
+
+		actual, err := renderTemplate("discussionAnswered", &DiscussionEvent{Action: sToP("answered"), Repo: &repo, Discussion: discussion, Answer: comment, Sender: &user})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("comment", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) New comment by [panda](https://github.com/panda) on [#7 How to subscribe?](https://github.com/mattermost/mattermost-plugin-github/discussions/7):
+
+Use the discussions feature
+`
+
+		actual, err := renderTemplate("discussionComment", commentEvent)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("mention", func(t *testing.T) {
+		expected := `
+[panda](https://github.com/panda) mentioned you on [mattermost-plugin-github#7](https://github.com/mattermost/mattermost-plugin-github/discussions/7#discussioncomment-1) - How to subscribe?:
+>Use the discussions feature
+`
+
+		actual, err := renderTemplate("discussionCommentMentionNotification", commentEvent)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestSecurityAlertTemplates(t *testing.T) {
+	t.Run("dependabot alert", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) Dependabot alert [#3 Command Injection in lodash](https://github.com/mattermost/mattermost-plugin-github/security/dependabot/3) opened for ` + "`lodash`" + ` (npm) in ` + "`webapp/package-lock.json`" + `
+Severity: **high** · Vulnerable versions: ` + "`< 4.17.21`" + ` · Patched version: ` + "`4.17.21`" + `
+`
+
+		actual, err := renderTemplate("dependabotAlert", &DependabotAlertEvent{
+			Action: sToP("created"),
+			Alert: DependabotAlert{
+				Number:                3,
+				HTMLURL:               "https://github.com/mattermost/mattermost-plugin-github/security/dependabot/3",
+				Dependency:            DependabotDependency{Package: DependabotPackage{Ecosystem: "npm", Name: "lodash"}, ManifestPath: "webapp/package-lock.json"},
+				SecurityAdvisory:      SecurityAdvisory{Summary: "Command Injection in lodash", Severity: "high"},
+				SecurityVulnerability: SecurityVulnerability{VulnerableVersionRange: "< 4.17.21", FirstPatchedVersion: FirstPatchedVersion{Identifier: "4.17.21"}},
+			},
+			Repo:   &repo,
+			Sender: &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("code scanning alert closed", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) Code scanning alert [#5 Database query built from user-controlled sources](https://github.com/mattermost/mattermost-plugin-github/security/code-scanning/5) from **CodeQL** closed by [panda](https://github.com/panda) in ` + "`server/plugin/api.go:42`" + `
+Severity: **medium** · Rule: ` + "`go/sql-injection`" + `
+`
+
+		actual, err := renderTemplate("codeScanningAlert", &CodeScanningAlertEvent{
+			Action: sToP("closed_by_user"),
+			Alert: CodeScanningAlert{
+				Number:             5,
+				HTMLURL:            "https://github.com/mattermost/mattermost-plugin-github/security/code-scanning/5",
+				Rule:               CodeScanningRule{ID: "go/sql-injection", Description: "Database query built from user-controlled sources", Severity: "warning"},
+				Tool:               CodeScanningTool{Name: "CodeQL"},
+				MostRecentInstance: CodeScanningAlertInstance{Location: CodeScanningLocation{Path: "server/plugin/api.go", StartLine: 42}},
+			},
+			Repo:   &repo,
+			Sender: &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("secret scanning alert", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) Secret scanning alert [#2 GitHub Personal Access Token](https://github.com/mattermost/mattermost-plugin-github/security/secret-scanning/2) opened. The secret should be revoked.
+`
+
+		actual, err := renderTemplate("secretScanningAlert", &SecretScanningAlertEvent{
+			Action: sToP("created"),
+			Alert: SecretScanningAlert{
+				Number:                2,
+				HTMLURL:               "https://github.com/mattermost/mattermost-plugin-github/security/secret-scanning/2",
+				SecretType:            "github_personal_access_token",
+				SecretTypeDisplayName: "GitHub Personal Access Token",
+			},
+			Repo: &repo,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestDeploymentTemplates(t *testing.T) {
+	deployment := &github.Deployment{
+		ID:          github.Int64(11),
+		SHA:         sToP("a10867b14bb761a232cd80139fbd4c0d33264240"),
+		Ref:         sToP("v1.2.0"),
+		Environment: sToP("production"),
+		Description: sToP("Release 1.2.0"),
+		Creator:     &user,
+	}
+	statusEvent := func(status *github.DeploymentStatus) *github.DeploymentStatusEvent {
+		return &github.DeploymentStatusEvent{Deployment: deployment, DeploymentStatus: status, Repo: &repo, Sender: &user}
+	}
+
+	t.Run("new deployment", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) started a deployment of [` + "`v1.2.0`" + `](https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240) to **production**: Release 1.2.0
+`
+
+		actual, err := renderTemplate("newDeployment", &github.DeploymentEvent{Deployment: deployment, Repo: &repo, Sender: &user})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("succeeded", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) :white_check_mark: Deployment of [` + "`v1.2.0`" + `](https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240) to **production** succeeded. [Open environment](https://example.com) [View logs](https://example.com/logs)
+`
+
+		actual, err := renderTemplate("deploymentSuccess", statusEvent(&github.DeploymentStatus{
+			State:          sToP("success"),
+			EnvironmentURL: sToP("https://example.com"),
+			TargetURL:      sToP("https://example.com/logs"),
+		}))
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("errored", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) :x: Deployment of [` + "`v1.2.0`" + `](https://github.com/mattermost/mattermost-plugin-github/commit/a10867b14bb761a232cd80139fbd4c0d33264240) to **production** errored.
+`
+
+		actual, err := renderTemplate("deploymentFailure", statusEvent(&github.DeploymentStatus{State: sToP("error")}))
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestMilestoneTemplates(t *testing.T) {
+	dueOn := time.Date(2022, time.March, 1, 8, 0, 0, 0, time.UTC)
+	previousDueOn := time.Date(2022, time.February, 15, 8, 0, 0, 0, time.UTC)
+	milestone := &github.Milestone{
+		Title:        sToP("v1.2.0"),
+		HTMLURL:      sToP("https://github.com/mattermost/mattermost-plugin-github/milestone/4"),
+		OpenIssues:   github.Int(1),
+		ClosedIssues: github.Int(3),
+		DueOn:        &dueOn,
+	}
+
+	t.Run("created", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) created the milestone [v1.2.0](https://github.com/mattermost/mattermost-plugin-github/milestone/4), due on **March 1, 2022**.
+`
+
+		actual, err := renderTemplate("newMilestone", &MilestoneEvent{Action: sToP("created"), Milestone: milestone, Repo: &repo, Sender: &user})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) closed the milestone [v1.2.0](https://github.com/mattermost/mattermost-plugin-github/milestone/4) at **75%** complete (3 closed, 1 open).
+`
+
+		actual, err := renderTemplate("closedMilestone", &MilestoneEvent{Action: sToP("closed"), Milestone: milestone, Repo: &repo, Sender: &user})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("due date changed", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) moved the due date of the milestone [v1.2.0](https://github.com/mattermost/mattermost-plugin-github/milestone/4) from **February 15, 2022** to **March 1, 2022**. It is **75%** complete (3 closed, 1 open).
+`
+
+		actual, err := renderTemplate("milestoneDueDateChanged", &MilestoneEvent{
+			Action:    sToP("edited"),
+			Milestone: milestone,
+			Changes:   &MilestoneChanges{DueOn: &MilestoneDueOnChange{From: &previousDueOn}},
+			Repo:      &repo,
+			Sender:    &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("due date removed", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) removed the due date of the milestone [v1.2.0](https://github.com/mattermost/mattermost-plugin-github/milestone/4). It is **75%** complete (3 closed, 1 open).
+`
+
+		actual, err := renderTemplate("milestoneDueDateChanged", &MilestoneEvent{
+			Action:    sToP("edited"),
+			Milestone: &github.Milestone{Title: milestone.Title, HTMLURL: milestone.HTMLURL, OpenIssues: milestone.OpenIssues, ClosedIssues: milestone.ClosedIssues},
+			Changes:   &MilestoneChanges{DueOn: &MilestoneDueOnChange{From: &previousDueOn}},
+			Repo:      &repo,
+			Sender:    &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestProjectItemMovedTemplate(t *testing.T) {
+	event := &ProjectsV2ItemEvent{Action: sToP("edited"), Sender: &user}
+
+	t.Run("issue moved", func(t *testing.T) {
+		expected := `
+[\[Roadmap\]](https://github.com/orgs/mattermost/projects/1) [panda](https://github.com/panda) moved [mattermost/mattermost-plugin-github#42 Subscribe to projects](https://github.com/mattermost/mattermost-plugin-github/issues/42) from **Todo** to **In Progress**.
+`
+
+		actual, err := renderTemplate("projectItemMoved", &ProjectItemMove{
+			Event: event,
+			Item: &graphql.ProjectItem{
+				ProjectTitle: "Roadmap",
+				ProjectURL:   "https://github.com/orgs/mattermost/projects/1",
+				Title:        "Subscribe to projects",
+				URL:          "https://github.com/mattermost/mattermost-plugin-github/issues/42",
+				Number:       42,
+				Repository:   "mattermost/mattermost-plugin-github",
+			},
+			Field: "Status",
+			From:  "Todo",
+			To:    "In Progress",
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("draft cleared", func(t *testing.T) {
+		expected := `
+[\[Roadmap\]](https://github.com/orgs/mattermost/projects/1) [panda](https://github.com/panda) cleared the **Priority** of the draft **Plan the release**.
+`
+
+		actual, err := renderTemplate("projectItemMoved", &ProjectItemMove{
+			Event: event,
+			Item:  &graphql.ProjectItem{ProjectTitle: "Roadmap", ProjectURL: "https://github.com/orgs/mattermost/projects/1", Title: "Plan the release"},
+			Field: "Priority",
+			From:  "High",
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestRepoAdminTemplates(t *testing.T) {
+	t.Run("repository archived", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) archived the repository.
+`
+
+		actual, err := renderTemplate("repositoryChanged", &github.RepositoryEvent{Action: sToP("archived"), Repo: &repo, Sender: &user})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("member permission changed", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) changed the permission of [panda](https://github.com/panda) from **write** to **admin**.
+`
+
+		actual, err := renderTemplate("repositoryMemberChanged", &MemberEvent{
+			Action:  sToP("edited"),
+			Member:  &user,
+			Changes: &MemberChanges{Permission: &MemberPermissionChange{From: sToP("write"), To: sToP("admin")}},
+			Repo:    &repo,
+			Sender:  &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("branch protection rule created", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) added the branch protection rule for ` + "`master`" + `
+Required approving reviews: **2** · Required status checks: ` + "`build`, `test`" + ` · Enforced for administrators: **no**
+`
+
+		actual, err := renderTemplate("branchProtectionRuleChanged", &github.BranchProtectionRuleEvent{
+			Action: sToP("created"),
+			Rule: &github.BranchProtectionRule{
+				Name:                                 sToP("master"),
+				PullRequestReviewsEnforcementLevel:   sToP("everyone"),
+				RequiredApprovingReviewCount:         iToP(2),
+				RequiredStatusChecks:                 []string{"build", "test"},
+				RequiredStatusChecksEnforcementLevel: sToP("non_admins"),
+			},
+			Repo:   &repo,
+			Sender: &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("branch protection rule deleted", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) deleted the branch protection rule for ` + "`release-*`" + `
+`
+
+		actual, err := renderTemplate("branchProtectionRuleChanged", &github.BranchProtectionRuleEvent{
+			Action: sToP("deleted"),
+			Rule:   &github.BranchProtectionRule{Name: sToP("release-*")},
+			Repo:   &repo,
+			Sender: &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("forked", func(t *testing.T) {
+		expected := `
+[\[mattermost-plugin-github\]](https://github.com/mattermost/mattermost-plugin-github) [panda](https://github.com/panda) forked the repository to [panda/mattermost-plugin-github](https://github.com/panda/mattermost-plugin-github).
+`
+
+		actual, err := renderTemplate("repositoryForked", &github.ForkEvent{
+			Forkee: &github.Repository{FullName: sToP("panda/mattermost-plugin-github"), HTMLURL: sToP("https://github.com/panda/mattermost-plugin-github")},
+			Repo:   &repo,
+			Sender: &user,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+}
@@ -0,0 +1,371 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+)
+
+const (
+	throttleKeyPrefix = "_throttle_"
+	throttleIndexKey  = "_throttle_index"
+	throttleJobKey    = "flush_throttled_notifications"
+
+	// throttleTTL is how long the state of a subscription without held back events is kept.
+	throttleTTL = 2 * time.Minute
+	// throttleHeldTTL is how long held back events are kept. It covers the longest quiet hours.
+	throttleHeldTTL = 25 * time.Hour
+
+	throttleUpdateRetries = 5
+
+	// maxThrottledEventLines is the number of held back events listed in a summary.
+	maxThrottledEventLines = 10
+	// maxThrottledEventLength is the maximum number of characters kept for each held back event.
+	maxThrottledEventLength = 200
+)
+
+// subscriptionThrottle tracks the notifications of a subscription posted in the current
+// one minute window, and the ones held back by its rate limit or quiet hours.
+type subscriptionThrottle struct {
+	ChannelID   string
+	Repository  string
+	RateLimit   int
+	QuietHours  string
+	Timezone    string
+	Locale      string
+	WindowStart int64
+	Posted      int
+	HeldBack    int
+	Events      []string
+}
+
+func throttleKey(sub *Subscription) string {
+	hash := sha256.Sum256([]byte(sub.ChannelID + "/" + sub.Repository))
+	return throttleKeyPrefix + hex.EncodeToString(hash[:16])
+}
+
+// parseQuietHours parses a HH:MM-HH:MM range into minutes since midnight.
+func parseQuietHours(value string) (int, int, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.Errorf("invalid quiet hours %s, expected HH:MM-HH:MM", value)
+	}
+
+	var bounds [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, errors.Errorf("invalid quiet hours %s, expected HH:MM-HH:MM", value)
+		}
+		bounds[i] = t.Hour()*60 + t.Minute()
+	}
+
+	if bounds[0] == bounds[1] {
+		return 0, 0, errors.Errorf("invalid quiet hours %s, start and end must differ", value)
+	}
+
+	return bounds[0], bounds[1], nil
+}
+
+// isQuietTime reports whether now falls within the quiet hours in the given timezone.
+// Ranges ending before they start span midnight.
+func isQuietTime(quietHours, timezone string, now time.Time) bool {
+	if quietHours == "" {
+		return false
+	}
+
+	start, end, err := parseQuietHours(quietHours)
+	if err != nil {
+		return false
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return minute >= start && minute < end
+	}
+
+	return minute >= start || minute < end
+}
+
+// hold records an event at now and reports whether it has to be held back instead of posted.
+func (t *subscriptionThrottle) hold(now time.Time, event string) bool {
+	window := now.Truncate(time.Minute).Unix()
+	if t.WindowStart != window {
+		t.WindowStart = window
+		t.Posted = 0
+	}
+
+	if !isQuietTime(t.QuietHours, t.Timezone, now) && (t.RateLimit == 0 || t.Posted < t.RateLimit) {
+		t.Posted++
+		return false
+	}
+
+	t.HeldBack++
+	if len(t.Events) < maxThrottledEventLines {
+		t.Events = append(t.Events, event)
+	}
+
+	return true
+}
+
+// release hands out the held back events once the window they were held back in has passed and
+// quiet hours are over.
+func (t *subscriptionThrottle) release(now time.Time) (int, []string) {
+	if t.HeldBack == 0 || isQuietTime(t.QuietHours, t.Timezone, now) || t.WindowStart >= now.Truncate(time.Minute).Unix() {
+		return 0, nil
+	}
+
+	count, events := t.HeldBack, t.Events
+	t.HeldBack = 0
+	t.Events = nil
+
+	return count, events
+}
+
+// createSubscriptionPost posts a notification about event for a subscription, holding it back when
+// the subscription is rate limited or in its quiet hours. It returns the ID of the created post, or
+// an empty string if the notification was held back or failed to post.
+func (p *Plugin) createSubscriptionPost(sub *Subscription, post *model.Post, event interface{}) string {
+	if sub.RateLimit() == 0 && sub.QuietHours() == "" {
+		return p.createStyledSubscriptionPost(sub, post, event)
+	}
+
+	now := time.Now()
+	var held bool
+	var releasedCount int
+	var releasedEvents []string
+	var firstHeld bool
+
+	err := p.updateSubscriptionThrottle(throttleKey(sub), func(state *subscriptionThrottle) {
+		state.ChannelID = sub.ChannelID
+		state.Repository = sub.Repository
+		state.RateLimit = sub.RateLimit()
+		state.QuietHours = sub.QuietHours()
+		state.Timezone = sub.Timezone()
+		state.Locale = sub.Locale()
+
+		releasedCount, releasedEvents = state.release(now)
+		held = state.hold(now, summarizePost(post))
+		firstHeld = held && state.HeldBack == 1
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to update notification throttle, posting anyway", "channel_id", sub.ChannelID, "error", err.Error())
+		held = false
+	}
+
+	if releasedCount > 0 {
+		p.postThrottleSummary(sub.ChannelID, sub.Repository, sub.Locale(), releasedCount, releasedEvents)
+	}
+
+	if firstHeld {
+		p.addToThrottleIndex(throttleKey(sub))
+	}
+
+	if held {
+		return ""
+	}
+
+	return p.createStyledSubscriptionPost(sub, post, event)
+}
+
+// createStyledSubscriptionPost creates post in the render style of the subscription and returns
+// the ID of the created post.
+func (p *Plugin) createStyledSubscriptionPost(sub *Subscription, post *model.Post, event interface{}) string {
+	if sub.RenderStyle() == renderStyleAttachment {
+		post = p.getAttachmentPost(p.getLocalizer(p.getSubscriptionLocale(sub)), post, event)
+	}
+
+	if err := p.client.Post.CreatePost(post); err != nil {
+		p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+		return ""
+	}
+
+	return post.Id
+}
+
+// updateSubscriptionThrottle atomically applies update to the throttle state stored at key.
+func (p *Plugin) updateSubscriptionThrottle(key string, update func(state *subscriptionThrottle)) error {
+	for i := 0; i < throttleUpdateRetries; i++ {
+		var oldValue []byte
+		if err := p.client.KV.Get(key, &oldValue); err != nil {
+			return errors.Wrap(err, "failed to get throttle state")
+		}
+
+		state := &subscriptionThrottle{}
+		if oldValue != nil {
+			if err := json.Unmarshal(oldValue, state); err != nil {
+				return errors.Wrap(err, "failed to decode throttle state")
+			}
+		}
+
+		update(state)
+
+		ttl := throttleTTL
+		if state.HeldBack > 0 {
+			ttl = throttleHeldTTL
+		}
+
+		saved, err := p.client.KV.Set(key, state, pluginapi.SetAtomic(oldValue), pluginapi.SetExpiry(ttl))
+		if err != nil {
+			return errors.Wrap(err, "failed to store throttle state")
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return errors.Errorf("failed to store throttle state after %d retries", throttleUpdateRetries)
+}
+
+func (p *Plugin) addToThrottleIndex(key string) {
+	err := p.client.KV.SetAtomicWithRetries(throttleIndexKey, func(oldValue []byte) (interface{}, error) {
+		var keys []string
+		if oldValue != nil {
+			if err := json.Unmarshal(oldValue, &keys); err != nil {
+				return nil, err
+			}
+		}
+
+		if containsValue(keys, key) {
+			return keys, nil
+		}
+
+		return append(keys, key), nil
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to index held back notifications", "error", err.Error())
+	}
+}
+
+// flushThrottledNotifications posts a summary for every subscription whose held back events
+// can be released. It runs as a cluster wide job once a minute.
+func (p *Plugin) flushThrottledNotifications() {
+	var keys []string
+	if err := p.client.KV.Get(throttleIndexKey, &keys); err != nil {
+		p.client.Log.Warn("Failed to get held back notifications", "error", err.Error())
+		return
+	}
+
+	now := time.Now()
+	var done []string
+
+	for _, key := range keys {
+		var state subscriptionThrottle
+		var count int
+		var events []string
+
+		err := p.updateSubscriptionThrottle(key, func(s *subscriptionThrottle) {
+			count, events = s.release(now)
+			state = *s
+		})
+		if err != nil {
+			p.client.Log.Warn("Failed to release held back notifications", "error", err.Error())
+			continue
+		}
+
+		if count > 0 {
+			p.postThrottleSummary(state.ChannelID, state.Repository, state.Locale, count, events)
+		}
+
+		if state.HeldBack == 0 {
+			done = append(done, key)
+		}
+	}
+
+	if len(done) == 0 {
+		return
+	}
+
+	err := p.client.KV.SetAtomicWithRetries(throttleIndexKey, func(oldValue []byte) (interface{}, error) {
+		var current []string
+		if oldValue != nil {
+			if err := json.Unmarshal(oldValue, &current); err != nil {
+				return nil, err
+			}
+		}
+
+		remaining := []string{}
+		for _, key := range current {
+			if !containsValue(done, key) {
+				remaining = append(remaining, key)
+			}
+		}
+
+		return remaining, nil
+	})
+	if err != nil {
+		p.client.Log.Warn("Failed to update held back notifications", "error", err.Error())
+	}
+}
+
+// postThrottleSummary posts the summary of held back events. An empty locale stands for the
+// default locale of the server.
+func (p *Plugin) postThrottleSummary(channelID, repository, locale string, count int, events []string) {
+	if locale == "" {
+		locale = p.getServerLocale()
+	}
+
+	post := &model.Post{
+		UserId:    p.BotUserID,
+		ChannelId: channelID,
+		Type:      "custom_git_summary",
+		Message:   p.formatThrottleSummary(p.getLocalizer(locale), repository, count, events),
+	}
+
+	if err := p.client.Post.CreatePost(post); err != nil {
+		p.client.Log.Warn("Error posting summary of held back notifications", "channel_id", channelID, "error", err.Error())
+	}
+}
+
+func (p *Plugin) formatThrottleSummary(l *i18n.Localizer, repository string, count int, events []string) string {
+	txt := p.localizePlural(l, &i18n.Message{
+		ID:    "throttle.summary",
+		One:   "**{{.Count}} more event** from `{{.Repository}}` was held back by the rate limit or quiet hours of this subscription:",
+		Other: "**{{.Count}} more events** from `{{.Repository}}` were held back by the rate limit or quiet hours of this subscription:",
+	}, count, map[string]interface{}{"Repository": strings.Trim(repository, "/")}) + "\n"
+	for _, event := range events {
+		txt += "* " + event + "\n"
+	}
+	if count > len(events) {
+		txt += "* " + p.localizePlural(l, &i18n.Message{
+			ID:    "throttle.summary.more",
+			One:   "and {{.Count}} more",
+			Other: "and {{.Count}} more",
+		}, count-len(events), nil) + "\n"
+	}
+
+	return txt
+}
+
+// summarizePost returns the first non-empty line of the post message, without markdown headings.
+func summarizePost(post *model.Post) string {
+	for _, line := range strings.Split(post.Message, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "# "))
+		if line == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(line) > maxThrottledEventLength {
+			line = string([]rune(line)[:maxThrottledEventLength]) + "…"
+		}
+
+		return line
+	}
+
+	return "(no message)"
+}
@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tcs := []struct {
+		Value string
+		Start int
+		End   int
+		Error bool
+	}{
+		{Value: "22:00-07:00", Start: 22 * 60, End: 7 * 60},
+		{Value: "09:30 - 17:45", Start: 9*60 + 30, End: 17*60 + 45},
+		{Value: "22:00", Error: true},
+		{Value: "25:00-07:00", Error: true},
+		{Value: "08:00-08:00", Error: true},
+	}
+
+	for _, tc := range tcs {
+		start, end, err := parseQuietHours(tc.Value)
+		if tc.Error {
+			assert.Error(t, err, tc.Value)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Value)
+		assert.Equal(t, tc.Start, start, tc.Value)
+		assert.Equal(t, tc.End, end, tc.Value)
+	}
+}
+
+func TestIsQuietTime(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2023, 5, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tcs := []struct {
+		Name       string
+		QuietHours string
+		Timezone   string
+		Now        time.Time
+		Expected   bool
+	}{
+		{Name: "no quiet hours", Now: at(23, 0), Expected: false},
+		{Name: "inside range", QuietHours: "09:00-17:00", Now: at(12, 0), Expected: true},
+		{Name: "end is exclusive", QuietHours: "09:00-17:00", Now: at(17, 0), Expected: false},
+		{Name: "spanning midnight before", QuietHours: "22:00-07:00", Now: at(23, 30), Expected: true},
+		{Name: "spanning midnight after", QuietHours: "22:00-07:00", Now: at(6, 59), Expected: true},
+		{Name: "spanning midnight outside", QuietHours: "22:00-07:00", Now: at(12, 0), Expected: false},
+		{Name: "timezone", QuietHours: "22:00-07:00", Timezone: "Asia/Tokyo", Now: at(14, 0), Expected: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, isQuietTime(tc.QuietHours, tc.Timezone, tc.Now))
+		})
+	}
+}
+
+func TestSubscriptionThrottle(t *testing.T) {
+	start := time.Date(2023, 5, 10, 12, 0, 10, 0, time.UTC)
+
+	t.Run("rate limit", func(t *testing.T) {
+		throttle := &subscriptionThrottle{RateLimit: 2}
+
+		assert.False(t, throttle.hold(start, "first"))
+		assert.False(t, throttle.hold(start.Add(time.Second), "second"))
+		assert.True(t, throttle.hold(start.Add(2*time.Second), "third"))
+		assert.True(t, throttle.hold(start.Add(3*time.Second), "fourth"))
+
+		count, events := throttle.release(start.Add(4 * time.Second))
+		assert.Zero(t, count, "events are not released within the same window")
+		assert.Nil(t, events)
+
+		count, events = throttle.release(start.Add(time.Minute))
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{"third", "fourth"}, events)
+
+		assert.False(t, throttle.hold(start.Add(time.Minute), "fifth"), "a new window allows posting again")
+	})
+
+	t.Run("quiet hours", func(t *testing.T) {
+		throttle := &subscriptionThrottle{QuietHours: "12:00-13:00"}
+
+		assert.True(t, throttle.hold(start, "first"))
+		assert.True(t, throttle.hold(start.Add(10*time.Minute), "second"))
+
+		count, _ := throttle.release(start.Add(30 * time.Minute))
+		assert.Zero(t, count, "events are not released during quiet hours")
+
+		count, events := throttle.release(start.Add(time.Hour))
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{"first", "second"}, events)
+	})
+
+	t.Run("held back events are capped", func(t *testing.T) {
+		throttle := &subscriptionThrottle{QuietHours: "12:00-13:00"}
+
+		for i := 0; i < maxThrottledEventLines+5; i++ {
+			assert.True(t, throttle.hold(start, "event"))
+		}
+
+		assert.Equal(t, maxThrottledEventLines+5, throttle.HeldBack)
+		assert.Len(t, throttle.Events, maxThrottledEventLines)
+	})
+}
+
+func TestFormatThrottleSummary(t *testing.T) {
+	p := NewPlugin()
+	summary := p.formatThrottleSummary(p.getLocalizer(defaultLocale), "mattermost/", 3, []string{"first", "second"})
+
+	assert.Equal(t, "**3 more events** from `mattermost` were held back by the rate limit or quiet hours of this subscription:\n"+
+		"* first\n"+
+		"* second\n"+
+		"* and 1 more\n", summary)
+
+	summary = p.formatThrottleSummary(p.getLocalizer(defaultLocale), "mattermost/mattermost-server", 1, []string{"first"})
+	assert.Equal(t, "**1 more event** from `mattermost/mattermost-server` was held back by the rate limit or quiet hours of this subscription:\n"+
+		"* first\n", summary)
+}
+
+func TestSummarizePost(t *testing.T) {
+	assert.Equal(t, "[mattermost/mattermost-server] New pull request", summarizePost(&model.Post{Message: "\n#### [mattermost/mattermost-server] New pull request\nbody"}))
+	assert.Equal(t, "(no message)", summarizePost(&model.Post{}))
+
+	long := summarizePost(&model.Post{Message: strings.Repeat("a", maxThrottledEventLength+10)})
+	require.True(t, strings.HasSuffix(long, "…"))
+	assert.Equal(t, maxThrottledEventLength+1, len([]rune(long)))
+}
@@ -0,0 +1,235 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-github/server/plugin/graphql"
+)
+
+const (
+	userMappingsKey = "_user_mappings"
+
+	userMappingsImportMaxLength = 1024 * 1024
+)
+
+// UserMappingRow is a row of an imported user mapping. User is the username or email address of
+// the Mattermost user.
+type UserMappingRow struct {
+	Login string `json:"login"`
+	User  string `json:"user"`
+}
+
+// UserMappingImport is the result of importing a user mapping.
+type UserMappingImport struct {
+	// Mapped is the number of GitHub users mapped to a Mattermost user.
+	Mapped int `json:"mapped"`
+	// Unresolved lists the rows whose Mattermost user wasn't found.
+	Unresolved []*UserMappingRow `json:"unresolved"`
+}
+
+// normalizeGitHubLogin returns the lowercase GitHub login, as given with or without a leading @,
+// or an empty string if login isn't a valid GitHub login.
+func normalizeGitHubLogin(login string) string {
+	login = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(login), "@"))
+	if login == "" || strings.ContainsAny(login, "/ \t") {
+		return ""
+	}
+
+	return login
+}
+
+// getUserMappings returns the Mattermost user IDs of the GitHub users imported by an admin, keyed
+// by their normalized login. Unlike connected accounts, these only render mentions of GitHub
+// users as Mattermost users and never grant access to GitHub or notifications.
+func (p *Plugin) getUserMappings() (map[string]string, error) {
+	var mappings map[string]string
+	if err := p.client.KV.Get(userMappingsKey, &mappings); err != nil {
+		return nil, errors.Wrap(err, "failed to get user mappings")
+	}
+
+	if mappings == nil {
+		mappings = map[string]string{}
+	}
+
+	return mappings, nil
+}
+
+// getImportedUserMapping returns the Mattermost user ID imported for a GitHub user, if any.
+func (p *Plugin) getImportedUserMapping(login string) string {
+	mappings, err := p.getUserMappings()
+	if err != nil {
+		p.client.Log.Warn("Failed to get user mappings", "error", err.Error())
+		return ""
+	}
+
+	return mappings[normalizeGitHubLogin(login)]
+}
+
+// updateUserMappings atomically applies update to the imported mappings of GitHub users.
+func (p *Plugin) updateUserMappings(update func(mappings map[string]string)) error {
+	return p.client.KV.SetAtomicWithRetries(userMappingsKey, func(oldValue []byte) (interface{}, error) {
+		mappings := map[string]string{}
+		if oldValue != nil {
+			if err := json.Unmarshal(oldValue, &mappings); err != nil {
+				return nil, err
+			}
+		}
+
+		update(mappings)
+
+		return mappings, nil
+	})
+}
+
+// parseUserMappingRows parses a CSV file with the GitHub login in the first column and the
+// Mattermost username or email address in the second one. A header row is skipped.
+func parseUserMappingRows(data []byte) ([]*UserMappingRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var rows []*UserMappingRow
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid CSV")
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		if line == 1 && len(record) > 0 {
+			header := strings.ToLower(strings.TrimSpace(record[0]))
+			if header == "login" || header == "github_login" || header == "github" {
+				continue
+			}
+		}
+
+		if len(record) != 2 {
+			return nil, errors.Errorf("line %d: expected a GitHub login and a Mattermost user", line)
+		}
+
+		login := normalizeGitHubLogin(record[0])
+		if login == "" {
+			return nil, errors.Errorf("line %d: invalid GitHub login %q", line, record[0])
+		}
+
+		user := strings.TrimSpace(record[1])
+		if user == "" {
+			return nil, errors.Errorf("line %d: missing Mattermost user for %s", line, login)
+		}
+
+		rows = append(rows, &UserMappingRow{Login: login, User: user})
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("no user mappings found")
+	}
+
+	return rows, nil
+}
+
+// resolveMattermostUser returns the ID of the Mattermost user with the given email address or
+// username, or an empty string if there is none.
+func (p *Plugin) resolveMattermostUser(user string) string {
+	if strings.Contains(strings.TrimPrefix(user, "@"), "@") {
+		if mmUser, err := p.client.User.GetByEmail(user); err == nil {
+			return mmUser.Id
+		}
+		return ""
+	}
+
+	if mmUser, err := p.client.User.GetByUsername(strings.TrimPrefix(user, "@")); err == nil {
+		return mmUser.Id
+	}
+	return ""
+}
+
+// ImportUserMappings maps the GitHub users of the given rows to their Mattermost users. Existing
+// mappings of these GitHub users are replaced.
+func (p *Plugin) ImportUserMappings(rows []*UserMappingRow) (*UserMappingImport, error) {
+	result := &UserMappingImport{Unresolved: []*UserMappingRow{}}
+
+	resolved := map[string]string{}
+	for _, row := range rows {
+		userID := p.resolveMattermostUser(row.User)
+		if userID == "" {
+			result.Unresolved = append(result.Unresolved, row)
+			continue
+		}
+		resolved[row.Login] = userID
+	}
+
+	if len(resolved) > 0 {
+		err := p.updateUserMappings(func(mappings map[string]string) {
+			for login, userID := range resolved {
+				mappings[login] = userID
+			}
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to store user mappings")
+		}
+	}
+
+	result.Mapped = len(resolved)
+	return result, nil
+}
+
+// MatchUserMappingsByEmail maps the members of a GitHub organization to the Mattermost users with
+// one of their email addresses. Members which are mapped already are left alone. It returns the
+// number of newly mapped members.
+func (p *Plugin) MatchUserMappingsByEmail(members []*graphql.OrganizationMember) (int, error) {
+	resolved := map[string]string{}
+	for _, member := range members {
+		login := normalizeGitHubLogin(member.Login)
+		if login == "" {
+			continue
+		}
+
+		for _, email := range member.Emails {
+			if mmUser, err := p.client.User.GetByEmail(email); err == nil {
+				resolved[login] = mmUser.Id
+				break
+			}
+		}
+	}
+
+	matched := 0
+	err := p.updateUserMappings(func(mappings map[string]string) {
+		matched = 0
+		for login, userID := range resolved {
+			if _, ok := mappings[login]; ok {
+				continue
+			}
+			mappings[login] = userID
+			matched++
+		}
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to store user mappings")
+	}
+
+	return matched, nil
+}
+
+// describeUserMappingRows returns the rows as a Markdown list.
+func describeUserMappingRows(rows []*UserMappingRow) string {
+	txt := ""
+	for _, row := range rows {
+		txt += fmt.Sprintf("* `%s`: %s\n", row.Login, row.User)
+	}
+
+	return txt
+}
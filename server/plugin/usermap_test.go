@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-github/server/plugin/graphql"
+)
+
+func TestNormalizeGitHubLogin(t *testing.T) {
+	assert.Equal(t, "octocat", normalizeGitHubLogin("OctoCat"))
+	assert.Equal(t, "octocat", normalizeGitHubLogin(" @octocat "))
+	assert.Equal(t, "", normalizeGitHubLogin("@"))
+	assert.Equal(t, "", normalizeGitHubLogin("mattermost/core"))
+}
+
+func TestParseUserMappingRows(t *testing.T) {
+	for name, tc := range map[string]struct {
+		Data        string
+		Expected    []*UserMappingRow
+		ExpectedErr string
+	}{
+		"rows with header": {
+			Data: "github_login,mattermost_user\nOctoCat,@jane\n\n# comment\nhubot, john@example.com\n",
+			Expected: []*UserMappingRow{
+				{Login: "octocat", User: "@jane"},
+				{Login: "hubot", User: "john@example.com"},
+			},
+		},
+		"rows without header": {
+			Data:     "octocat,jane",
+			Expected: []*UserMappingRow{{Login: "octocat", User: "jane"}},
+		},
+		"missing user": {
+			Data:        "octocat",
+			ExpectedErr: "line 1: expected a GitHub login and a Mattermost user",
+		},
+		"invalid login": {
+			Data:        "mattermost/core,jane",
+			ExpectedErr: `line 1: invalid GitHub login "mattermost/core"`,
+		},
+		"empty": {
+			Data:        "login,user\n",
+			ExpectedErr: "no user mappings found",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rows, err := parseUserMappingRows([]byte(tc.Data))
+			if tc.ExpectedErr != "" {
+				assert.EqualError(t, err, tc.ExpectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, rows)
+		})
+	}
+}
+
+func TestImportUserMappings(t *testing.T) {
+	existing, err := json.Marshal(map[string]string{"hubot": "user0"})
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("KVGet", userMappingsKey).Return(existing, nil)
+	api.On("KVSetWithOptions", userMappingsKey, mock.Anything, mock.Anything).Return(true, nil)
+	api.On("GetUserByUsername", "jane").Return(&model.User{Id: "user1"}, nil)
+	api.On("GetUserByEmail", "john@example.com").Return(&model.User{Id: "user2"}, nil)
+	api.On("GetUserByUsername", "nobody").Return(nil, &model.AppError{Message: "not found"})
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	result, err := p.ImportUserMappings([]*UserMappingRow{
+		{Login: "octocat", User: "@jane"},
+		{Login: "monalisa", User: "john@example.com"},
+		{Login: "ghost", User: "nobody"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Mapped)
+	assert.Equal(t, []*UserMappingRow{{Login: "ghost", User: "nobody"}}, result.Unresolved)
+
+	api.AssertCalled(t, "KVSetWithOptions", userMappingsKey, mock.MatchedBy(func(data []byte) bool {
+		var mappings map[string]string
+		return json.Unmarshal(data, &mappings) == nil && assert.ObjectsAreEqual(map[string]string{
+			"hubot":    "user0",
+			"octocat":  "user1",
+			"monalisa": "user2",
+		}, mappings)
+	}), mock.Anything)
+}
+
+func TestMatchUserMappingsByEmail(t *testing.T) {
+	existing, err := json.Marshal(map[string]string{"hubot": "user0"})
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("KVGet", userMappingsKey).Return(existing, nil)
+	api.On("KVSetWithOptions", userMappingsKey, mock.Anything, mock.Anything).Return(true, nil)
+	api.On("GetUserByEmail", "jane@example.com").Return(&model.User{Id: "user1"}, nil)
+	api.On("GetUserByEmail", "hubot@example.com").Return(&model.User{Id: "user2"}, nil)
+	api.On("GetUserByEmail", mock.AnythingOfType("string")).Return(nil, &model.AppError{Message: "not found"})
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	matched, err := p.MatchUserMappingsByEmail([]*graphql.OrganizationMember{
+		{Login: "OctoCat", Emails: []string{"octocat@github.com", "jane@example.com"}},
+		{Login: "hubot", Emails: []string{"hubot@example.com"}},
+		{Login: "ghost"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, matched)
+
+	api.AssertCalled(t, "KVSetWithOptions", userMappingsKey, mock.MatchedBy(func(data []byte) bool {
+		var mappings map[string]string
+		return json.Unmarshal(data, &mappings) == nil && assert.ObjectsAreEqual(map[string]string{
+			"hubot":   "user0",
+			"octocat": "user1",
+		}, mappings)
+	}), mock.Anything)
+}
+
+func TestGetGitHubToUsernameMappingImported(t *testing.T) {
+	mappings, err := json.Marshal(map[string]string{"octocat": "user1"})
+	require.NoError(t, err)
+
+	api := &plugintest.API{}
+	api.On("KVGet", "OctoCat"+githubUsernameKey).Return(nil, nil)
+	api.On("KVGet", "ghost"+githubUsernameKey).Return(nil, nil)
+	api.On("KVGet", userMappingsKey).Return(mappings, nil)
+	api.On("GetUser", "user1").Return(&model.User{Id: "user1", Username: "jane"}, nil)
+	p := NewPlugin()
+	p.SetAPI(api)
+	p.client = pluginapi.NewClient(p.API, p.Driver)
+
+	assert.Equal(t, "jane", p.getGitHubToUsernameMapping("OctoCat"))
+	assert.Equal(t, "", p.getGitHubToUsernameMapping("ghost"))
+
+	// Imported mappings never make a GitHub user a connected one.
+	assert.Equal(t, "", p.getGitHubToUserIDMapping("OctoCat"))
+}
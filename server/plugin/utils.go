@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/url"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -132,10 +133,30 @@ func parseOwnerAndRepo(full, baseURL string) (string, string) {
 	return owner, repo
 }
 
+// gitHubTeamMentionRegex matches mentions of GitHub teams, e.g. @mattermost/core. The first group
+// is the character preceding the mention.
+var gitHubTeamMentionRegex = regexp.MustCompile(`(^|[^\w@/-])@([a-zA-Z0-9][a-zA-Z0-9-]*/[a-zA-Z0-9][a-zA-Z0-9_-]*)`)
+
+// parseGitHubTeamsFromText returns the normalized names of the GitHub teams mentioned in text.
+func parseGitHubTeamsFromText(text string) []string {
+	teams := []string{}
+	for _, match := range gitHubTeamMentionRegex.FindAllStringSubmatch(text, -1) {
+		team := normalizeTeamName(match[2])
+		if !containsValue(teams, team) {
+			teams = append(teams, team)
+		}
+	}
+
+	return teams
+}
+
 func parseGitHubUsernamesFromText(text string) []string {
 	usernameMap := map[string]bool{}
 	usernames := []string{}
 
+	// The organization of a team mention is not mentioned itself.
+	text = gitHubTeamMentionRegex.ReplaceAllString(text, "$1")
+
 	for _, word := range strings.FieldsFunc(text, func(c rune) bool {
 		return !(c == '-' || c == '@' || unicode.IsLetter(c) || unicode.IsNumber(c))
 	}) {
@@ -175,6 +196,29 @@ func fullNameFromOwnerAndRepo(owner, repo string) string {
 	return fmt.Sprintf("%s/%s", owner, repo)
 }
 
+// isRepositoryPattern reports whether the repository name contains wildcard characters.
+func isRepositoryPattern(repo string) bool {
+	return strings.ContainsAny(repo, "*?[")
+}
+
+// validateRepositoryPattern checks that a repository pattern can be used to match repository names.
+func validateRepositoryPattern(pattern string) error {
+	if strings.Contains(pattern, "/") {
+		return errors.Errorf("invalid repository pattern %s", pattern)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errors.Errorf("invalid repository pattern %s", pattern)
+	}
+
+	return nil
+}
+
+// matchRepositoryPattern reports whether the repository name, without its owner, matches the pattern.
+func matchRepositoryPattern(pattern, repo string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(repo))
+	return err == nil && matched
+}
+
 func isFlag(text string) bool {
 	return strings.HasPrefix(text, "--")
 }
@@ -213,7 +257,7 @@ func filterLines(s string, start, end int) (string, error) {
 
 // getLineNumbers return the start and end lines from an anchor tag
 // of a github permalink.
-func getLineNumbers(s string) (start, end int) {
+func getLineNumbers(s string, lineContext int) (start, end int) {
 	// split till -
 	parts := strings.Split(s, "-")
 
@@ -228,10 +272,10 @@ func getLineNumbers(s string) (start, end int) {
 		if l == -1 {
 			return -1, -1
 		}
-		if l < permalinkLineContext {
-			return 0, l + permalinkLineContext
+		if l < lineContext {
+			return 0, l + lineContext
 		}
-		return l - permalinkLineContext, l + permalinkLineContext
+		return l - lineContext, l + lineContext
 	case 2:
 		// a line range
 		start := getLine(parts[0])
@@ -295,15 +339,74 @@ func isInsideLink(msg string, index int) bool {
 	return false
 }
 
+// codeLanguagesByExtension maps file extensions to the language names used to highlight code
+// blocks, when they differ.
+var codeLanguagesByExtension = map[string]string{
+	"cc":    "cpp",
+	"cjs":   "javascript",
+	"clj":   "clojure",
+	"cs":    "csharp",
+	"cxx":   "cpp",
+	"ex":    "elixir",
+	"exs":   "elixir",
+	"h":     "c",
+	"hpp":   "cpp",
+	"hs":    "haskell",
+	"htm":   "html",
+	"js":    "javascript",
+	"jsx":   "javascript",
+	"kt":    "kotlin",
+	"kts":   "kotlin",
+	"m":     "objectivec",
+	"md":    "markdown",
+	"mjs":   "javascript",
+	"pl":    "perl",
+	"ps1":   "powershell",
+	"py":    "python",
+	"rb":    "ruby",
+	"rs":    "rust",
+	"sh":    "bash",
+	"tf":    "hcl",
+	"ts":    "typescript",
+	"tsx":   "typescript",
+	"yml":   "yaml",
+	"zsh":   "bash",
+	"proto": "protobuf",
+}
+
+// codeLanguagesByFilename maps well-known file names without a meaningful extension to a language.
+var codeLanguagesByFilename = map[string]string{
+	"dockerfile":  "dockerfile",
+	"makefile":    "makefile",
+	"gemfile":     "ruby",
+	"rakefile":    "ruby",
+	"jenkinsfile": "groovy",
+}
+
+// getCodeLanguage returns the language used to highlight a code block showing the given file.
+// Unknown extensions are used as is.
+func getCodeLanguage(repoPath string) string {
+	name := strings.ToLower(path.Base(repoPath))
+	if language, ok := codeLanguagesByFilename[name]; ok {
+		return language
+	}
+
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	if language, ok := codeLanguagesByExtension[ext]; ok {
+		return language
+	}
+
+	return ext
+}
+
 // getCodeMarkdown returns the constructed markdown for a permalink.
-func getCodeMarkdown(user, repo, repoPath, word, lines string, isTruncated bool) string {
-	final := fmt.Sprintf("\n[%s/%s/%s](%s)\n", user, repo, repoPath, word)
-	ext := path.Ext(repoPath)
-	// remove the preceding dot
-	if len(ext) > 1 {
-		ext = strings.TrimPrefix(ext, ".")
-	}
-	final += "```" + ext + "\n"
+func getCodeMarkdown(user, repo, repoPath, word, label, lines string, isTruncated bool) string {
+	final := fmt.Sprintf("\n[%s/%s/%s](%s)", user, repo, repoPath, word)
+	if label != "" {
+		final += " - " + label
+	}
+	final += "\n"
+	final += "```" + getCodeLanguage(repoPath) + "\n"
 	final += lines
 	if isTruncated { // add an ellipsis if lines were cut off
 		final += "...\n"
@@ -28,6 +28,7 @@ func TestParseGitHubUsernameFromText(t *testing.T) {
 		{Text: "@jwilander2 @jwilander", Expected: []string{"jwilander2", "jwilander"}},
 		{Text: "Hey @jwilander and @jwilander2!", Expected: []string{"jwilander", "jwilander2"}},
 		{Text: "@jwilander @jwilan--der2", Expected: []string{"jwilander"}},
+		{Text: "@mattermost/core and @jwilander", Expected: []string{"jwilander"}},
 	}
 
 	for _, tc := range tcs {
@@ -35,6 +36,25 @@ func TestParseGitHubUsernameFromText(t *testing.T) {
 	}
 }
 
+func TestParseGitHubTeamsFromText(t *testing.T) {
+	tcs := []struct {
+		Text     string
+		Expected []string
+	}{
+		{Text: "@mattermost/core", Expected: []string{"mattermost/core"}},
+		{Text: "cc @Mattermost/Core.", Expected: []string{"mattermost/core"}},
+		{Text: "(@mattermost/core, @mattermost/web_team)", Expected: []string{"mattermost/core", "mattermost/web_team"}},
+		{Text: "@mattermost/core @mattermost/core", Expected: []string{"mattermost/core"}},
+		{Text: "@jwilander", Expected: []string{}},
+		{Text: "user@mattermost/core", Expected: []string{}},
+		{Text: "https://github.com/@mattermost/core", Expected: []string{}},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.Expected, parseGitHubTeamsFromText(tc.Text))
+	}
+}
+
 func TestFixGithubNotificationSubjectURL(t *testing.T) {
 	tcs := []struct {
 		Text     string
@@ -101,6 +121,33 @@ func TestIsFlag(t *testing.T) {
 	}
 }
 
+func TestMatchRepositoryPattern(t *testing.T) {
+	tcs := []struct {
+		Pattern  string
+		Repo     string
+		Expected bool
+	}{
+		{Pattern: "service-*", Repo: "service-auth", Expected: true},
+		{Pattern: "service-*", Repo: "Service-Auth", Expected: true},
+		{Pattern: "service-*", Repo: "auth-service", Expected: false},
+		{Pattern: "*-infra", Repo: "core-infra", Expected: true},
+		{Pattern: "*-infra", Repo: "core-infra-old", Expected: false},
+		{Pattern: "*", Repo: "anything", Expected: true},
+		{Pattern: "[", Repo: "[", Expected: false},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.Expected, matchRepositoryPattern(tc.Pattern, tc.Repo), "%s ~ %s", tc.Pattern, tc.Repo)
+	}
+}
+
+func TestValidateRepositoryPattern(t *testing.T) {
+	assert.NoError(t, validateRepositoryPattern("service-*"))
+	assert.NoError(t, validateRepositoryPattern("*-infra"))
+	assert.Error(t, validateRepositoryPattern("service-["))
+	assert.Error(t, validateRepositoryPattern("service/*"))
+}
+
 func TestParseFlag(t *testing.T) {
 	tcs := []struct {
 		Text     string
@@ -132,6 +179,26 @@ func TestContainsValue(t *testing.T) {
 	}
 }
 
+func TestGetCodeLanguage(t *testing.T) {
+	tcs := []struct {
+		Path     string
+		Expected string
+	}{
+		{Path: "server/plugin/plugin.go", Expected: "go"},
+		{Path: "scripts/build.py", Expected: "python"},
+		{Path: "webapp/src/index.tsx", Expected: "typescript"},
+		{Path: ".github/workflows/ci.yml", Expected: "yaml"},
+		{Path: "build/Dockerfile", Expected: "dockerfile"},
+		{Path: "Makefile", Expected: "makefile"},
+		{Path: "README", Expected: ""},
+		{Path: "data.unknown", Expected: "unknown"},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.Expected, getCodeLanguage(tc.Path), tc.Path)
+	}
+}
+
 func TestGetLineNumbers(t *testing.T) {
 	tcs := []struct {
 		input      string
@@ -168,7 +235,7 @@ func TestGetLineNumbers(t *testing.T) {
 		},
 	}
 	for _, tc := range tcs {
-		start, end := getLineNumbers(tc.input)
+		start, end := getLineNumbers(tc.input, permalinkLineContext)
 		assert.Equalf(t, tc.start, start, "unexpected start index for getLineNumbers(%q)", tc.input)
 		assert.Equalf(t, tc.end, end, "unexpected end index for getLineNumbers(%q)", tc.input)
 	}
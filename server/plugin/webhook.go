@@ -25,6 +25,8 @@ const (
 	actionSubmitted            = "submitted"
 	actionLabeled              = "labeled"
 	actionAssigned             = "assigned"
+	actionSynchronize          = "synchronize"
+	actionCompleted            = "completed"
 
 	actionCreated = "created"
 	actionDeleted = "deleted"
@@ -203,7 +205,7 @@ func (p *Plugin) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	event, err := github.ParseWebHook(github.WebHookType(r), body)
+	event, err := parseWebhook(github.WebHookType(r), body)
 	if err != nil {
 		p.client.Log.Debug("GitHub webhook content type should be set to \"application/json\"", "error", err.Error())
 		http.Error(w, "wrong mime-type. should be \"application/json\"", http.StatusBadRequest)
@@ -234,6 +236,7 @@ func (p *Plugin) handleWebhook(w http.ResponseWriter, r *http.Request) {
 			p.postPullRequestEvent(event)
 			p.handlePullRequestNotification(event)
 			p.handlePRDescriptionMentionNotification(event)
+			p.trackPullRequestHead(event)
 		}
 	case *github.IssuesEvent:
 		repo = event.GetRepo()
@@ -254,6 +257,7 @@ func (p *Plugin) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		handler = func() {
 			p.postPullRequestReviewEvent(event)
 			p.handlePullRequestReviewNotification(event)
+			p.handleReadyToMergeReview(event)
 		}
 	case *github.PullRequestReviewCommentEvent:
 		repo = event.GetRepo()
@@ -280,6 +284,92 @@ func (p *Plugin) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		handler = func() {
 			p.postStarEvent(event)
 		}
+	case *github.RepositoryEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.handleRepositoryEvent(event)
+			p.postRepositoryAdminEvent(event)
+		}
+	case *MemberEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postRepoAdminEvent(event, "repositoryMemberChanged")
+		}
+	case *github.BranchProtectionRuleEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postRepoAdminEvent(event, "branchProtectionRuleChanged")
+		}
+	case *github.PublicEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postRepoAdminEvent(event, "repositoryPublicized")
+		}
+	case *github.ForkEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postRepoAdminEvent(event, "repositoryForked")
+		}
+	case *github.StatusEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.handleStatusEvent(event)
+		}
+	case *github.CheckRunEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.handleCheckRunEvent(event)
+		}
+	case *github.WorkflowRunEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.handleWorkflowRunEvent(event)
+		}
+	case *DiscussionEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postDiscussionEvent(event)
+		}
+	case *DiscussionCommentEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postDiscussionCommentEvent(event)
+			p.handleDiscussionCommentMentionNotification(event)
+		}
+	case *github.DeploymentEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postDeploymentEvent(event)
+		}
+	case *github.DeploymentStatusEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postDeploymentStatusEvent(event)
+		}
+	case *MilestoneEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postMilestoneEvent(event)
+		}
+	case *ProjectsV2ItemEvent:
+		handler = func() {
+			p.postProjectsV2ItemEvent(event)
+		}
+	case *DependabotAlertEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postSecurityAlertEvent(event, "dependabotAlert")
+		}
+	case *CodeScanningAlertEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postSecurityAlertEvent(event, "codeScanningAlert")
+		}
+	case *SecretScanningAlertEvent:
+		repo = event.GetRepo()
+		handler = func() {
+			p.postSecurityAlertEvent(event, "secretScanningAlert")
+		}
 	}
 
 	if handler == nil {
@@ -293,6 +383,18 @@ func (p *Plugin) handleWebhook(w http.ResponseWriter, r *http.Request) {
 	handler()
 }
 
+// handleRepositoryEvent keeps the cached topics of a repository up to date, so that topic
+// subscriptions follow changes without waiting for the cache to expire.
+func (p *Plugin) handleRepositoryEvent(event *github.RepositoryEvent) {
+	repo := event.GetRepo()
+	if event.GetAction() == actionDeleted || repo.Topics == nil {
+		p.removeRepositoryTopics(repo.GetID())
+		return
+	}
+
+	p.storeRepositoryTopics(repo.GetID(), repo.Topics)
+}
+
 func (p *Plugin) permissionToRepo(userID string, ownerAndRepo string) bool {
 	if userID == "" {
 		return false
@@ -326,6 +428,18 @@ func (p *Plugin) permissionToRepo(userID string, ownerAndRepo string) bool {
 	return true
 }
 
+// getSubscriptionCreatorClient returns a GitHub client authenticated as the creator of sub, or nil
+// if the creator is not connected to GitHub.
+func (p *Plugin) getSubscriptionCreatorClient(sub *Subscription) *github.Client {
+	info, err := p.getGitHubUserInfo(sub.CreatorID)
+	if err != nil {
+		p.client.Log.Debug("Failed to get GitHub account of subscription creator", "user_id", sub.CreatorID, "error", err.Message)
+		return nil
+	}
+
+	return p.githubConnectUser(context.Background(), info)
+}
+
 func (p *Plugin) excludeConfigOrgMember(user *github.User, subscription *Subscription) bool {
 	if !subscription.ExcludeOrgMembers() {
 		return false
@@ -363,12 +477,6 @@ func (p *Plugin) postPullRequestEvent(event *github.PullRequestEvent) {
 		labels[i] = v.GetName()
 	}
 
-	closedPRMessage, err := renderTemplate("closedPR", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
 		UserId: p.BotUserID,
 		Type:   "custom_git_pr",
@@ -387,6 +495,7 @@ func (p *Plugin) postPullRequestEvent(event *github.PullRequestEvent) {
 			continue
 		}
 
+		locale := p.getSubscriptionLocale(sub)
 		label := sub.Label()
 
 		contained := false
@@ -409,7 +518,7 @@ func (p *Plugin) postPullRequestEvent(event *github.PullRequestEvent) {
 
 		if action == actionLabeled {
 			if label != "" && label == eventLabel {
-				pullRequestLabelledMessage, err := renderTemplate("pullRequestLabelled", event)
+				pullRequestLabelledMessage, err := renderLocalizedTemplate(locale, "pullRequestLabelled", event)
 				if err != nil {
 					p.client.Log.Warn("Failed to render template", "error", err.Error())
 					return
@@ -426,37 +535,41 @@ func (p *Plugin) postPullRequestEvent(event *github.PullRequestEvent) {
 			if isPRInDraftState {
 				prNotificationType = "newDraftPR"
 			}
-			newPRMessage, err := renderTemplate(prNotificationType, GetEventWithRenderConfig(event, sub))
+			newPRMessage, err := renderLocalizedTemplate(locale, prNotificationType, GetEventWithRenderConfig(event, sub))
 			if err != nil {
 				p.client.Log.Warn("Failed to render template", "error", err.Error())
 				return
 			}
 
-			post.Message = p.sanitizeDescription(newPRMessage)
+			post.Message = sanitizeDescription(newPRMessage)
 		}
 
 		if action == actionMarkedReadyForReview {
-			markedReadyToReviewPRMessage, err := renderTemplate("markedReadyToReviewPR", GetEventWithRenderConfig(event, sub))
+			markedReadyToReviewPRMessage, err := renderLocalizedTemplate(locale, "markedReadyToReviewPR", GetEventWithRenderConfig(event, sub))
 			if err != nil {
 				p.client.Log.Warn("Failed to render template", "error", err.Error())
 				return
 			}
 
-			post.Message = p.sanitizeDescription(markedReadyToReviewPRMessage)
+			post.Message = sanitizeDescription(markedReadyToReviewPRMessage)
 		}
 
 		if action == actionClosed {
+			closedPRMessage, err := renderLocalizedTemplate(locale, "closedPR", event)
+			if err != nil {
+				p.client.Log.Warn("Failed to render template", "error", err.Error())
+				return
+			}
+
 			post.Message = closedPRMessage
 		}
 
 		post.ChannelId = sub.ChannelID
-		if err := p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
-		}
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
-func (p *Plugin) sanitizeDescription(description string) string {
+func sanitizeDescription(description string) string {
 	var policy = bluemonday.StrictPolicy()
 	policy.SkipElementsContent("details")
 	return strings.TrimSpace(policy.Sanitize(description))
@@ -470,28 +583,31 @@ func (p *Plugin) handlePRDescriptionMentionNotification(event *github.PullReques
 
 	body := event.GetPullRequest().GetBody()
 
-	mentionedUsernames := parseGitHubUsernamesFromText(body)
+	p.notifyMentionedTeams(body, event.GetRepo(), event.GetPullRequest().Labels, "pullRequestTeamMentionNotification", func(team string) interface{} {
+		return &PullRequestTeamMention{Team: team, PullRequestEvent: event}
+	})
 
-	message, err := renderTemplate("pullRequestMentionNotification", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
+	// Notifications for pull request authors are handled separately
+	skip := []string{event.GetPullRequest().GetUser().GetLogin()}
+	p.notifyMentionedUsers(body, event.GetRepo(), event.GetPullRequest().Labels, event.GetSender(), skip, "pullRequestMentionNotification", event)
+}
 
+// notifyMentionedUsers sends a direct message, rendered from templateName with data, to each
+// connected user mentioned in text. The sender, the users in skip and the users who can't see the
+// repository or turned off or muted mention notifications aren't notified.
+func (p *Plugin) notifyMentionedUsers(text string, repo *github.Repository, labels []*github.Label, sender *github.User, skip []string, templateName string, data interface{}) {
 	post := &model.Post{
-		UserId:  p.BotUserID,
-		Message: message,
-		Type:    "custom_git_mention",
+		UserId: p.BotUserID,
+		Type:   "custom_git_mention",
 	}
 
-	for _, username := range mentionedUsernames {
+	for _, username := range parseGitHubUsernamesFromText(text) {
 		// Don't notify user of their own comment
-		if username == event.GetSender().GetLogin() {
+		if username == sender.GetLogin() {
 			continue
 		}
 
-		// Notifications for pull request authors are handled separately
-		if username == event.GetPullRequest().GetUser().GetLogin() {
+		if containsValue(skip, username) {
 			continue
 		}
 
@@ -500,7 +616,15 @@ func (p *Plugin) handlePRDescriptionMentionNotification(event *github.PullReques
 			continue
 		}
 
-		if event.GetRepo().GetPrivate() && !p.permissionToRepo(userID, event.GetRepo().GetFullName()) {
+		if repo.GetPrivate() && !p.permissionToRepo(userID, repo.GetFullName()) {
+			continue
+		}
+
+		if !p.isNotificationEnabled(userID, notificationTypeMentions) {
+			continue
+		}
+
+		if p.notificationMutedByReceiver(userID, repo.GetFullName(), labels) {
 			continue
 		}
 
@@ -509,10 +633,16 @@ func (p *Plugin) handlePRDescriptionMentionNotification(event *github.PullReques
 			continue
 		}
 
-		post.ChannelId = channel.Id
+		message, err := p.renderUserTemplate(userID, templateName, data)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
 
+		post.Message = message
+		post.ChannelId = channel.Id
 		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+			p.client.Log.Warn("Error creating mention post", "error", err.Error())
 		}
 
 		p.sendRefreshEvent(userID)
@@ -572,12 +702,12 @@ func (p *Plugin) postIssueEvent(event *github.IssuesEvent) {
 			continue
 		}
 
-		renderedMessage, err := renderTemplate(issueTemplate, GetEventWithRenderConfig(event, sub))
+		renderedMessage, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), issueTemplate, GetEventWithRenderConfig(event, sub))
 		if err != nil {
 			p.client.Log.Warn("Failed to render template", "error", err.Error())
 			return
 		}
-		renderedMessage = p.sanitizeDescription(renderedMessage)
+		renderedMessage = sanitizeDescription(renderedMessage)
 
 		post := &model.Post{
 			UserId:  p.BotUserID,
@@ -612,9 +742,7 @@ func (p *Plugin) postIssueEvent(event *github.IssuesEvent) {
 		}
 
 		post.ChannelId = sub.ChannelID
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
-		}
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
@@ -632,18 +760,14 @@ func (p *Plugin) postPushEvent(event *github.PushEvent) {
 		return
 	}
 
-	pushedCommitsMessage, err := renderTemplate("pushedCommits", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
-		UserId:  p.BotUserID,
-		Type:    "custom_git_push",
-		Message: pushedCommitsMessage,
+		UserId: p.BotUserID,
+		Type:   "custom_git_push",
 	}
 
+	// The details are the same for every subscription, so they are only fetched once.
+	var detail *PushDetail
+
 	for _, sub := range subs {
 		if !sub.Pushes() {
 			continue
@@ -653,10 +777,24 @@ func (p *Plugin) postPushEvent(event *github.PushEvent) {
 			continue
 		}
 
-		post.ChannelId = sub.ChannelID
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+		var message string
+		var err error
+		if sub.PushDetail() {
+			if detail == nil {
+				detail = p.getSubscriptionsPushDetail(event, subs)
+			}
+			message, err = renderLocalizedTemplate(p.getSubscriptionLocale(sub), "pushedCommitsDetail", detail)
+		} else {
+			message, err = renderLocalizedTemplate(p.getSubscriptionLocale(sub), "pushedCommits", event)
 		}
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
+		post.Message = message
+		post.ChannelId = sub.ChannelID
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
@@ -673,16 +811,9 @@ func (p *Plugin) postCreateEvent(event *github.CreateEvent) {
 		return
 	}
 
-	newCreateMessage, err := renderTemplate("newCreateMessage", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
-		UserId:  p.BotUserID,
-		Type:    "custom_git_create",
-		Message: newCreateMessage,
+		UserId: p.BotUserID,
+		Type:   "custom_git_create",
 	}
 
 	for _, sub := range subs {
@@ -694,10 +825,15 @@ func (p *Plugin) postCreateEvent(event *github.CreateEvent) {
 			continue
 		}
 
-		post.ChannelId = sub.ChannelID
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "newCreateMessage", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
 		}
+
+		post.Message = message
+		post.ChannelId = sub.ChannelID
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
@@ -716,16 +852,9 @@ func (p *Plugin) postDeleteEvent(event *github.DeleteEvent) {
 		return
 	}
 
-	newDeleteMessage, err := renderTemplate("newDeleteMessage", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
-		UserId:  p.BotUserID,
-		Type:    "custom_git_delete",
-		Message: newDeleteMessage,
+		UserId: p.BotUserID,
+		Type:   "custom_git_delete",
 	}
 
 	for _, sub := range subs {
@@ -737,10 +866,15 @@ func (p *Plugin) postDeleteEvent(event *github.DeleteEvent) {
 			continue
 		}
 
-		post.ChannelId = sub.ChannelID
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "newDeleteMessage", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
 		}
+
+		post.Message = message
+		post.ChannelId = sub.ChannelID
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
@@ -757,12 +891,6 @@ func (p *Plugin) postIssueCommentEvent(event *github.IssueCommentEvent) {
 		return
 	}
 
-	message, err := renderTemplate("issueComment", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
 		UserId: p.BotUserID,
 		Type:   "custom_git_comment",
@@ -802,28 +930,37 @@ func (p *Plugin) postIssueCommentEvent(event *github.IssueCommentEvent) {
 			continue
 		}
 
-		if event.GetAction() == actionCreated {
-			post.Message = message
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "issueComment", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
 		}
 
+		post.Message = message
 		post.ChannelId = sub.ChannelID
-
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
-		}
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
 func (p *Plugin) senderMutedByReceiver(userID string, sender string) bool {
-	var mutedUsernameBytes []byte
-	err := p.client.KV.Get(userID+"-muted-users", &mutedUsernameBytes)
+	mutes, err := p.getMutes(userID)
 	if err != nil {
-		p.client.Log.Warn("Failed to get muted users", "userID", userID)
+		p.client.Log.Warn("Failed to get muted users", "userID", userID, "error", err.Error())
 		return false
 	}
 
-	mutedUsernames := string(mutedUsernameBytes)
-	return strings.Contains(mutedUsernames, sender)
+	return containsFold(mutes.Users, sender)
+}
+
+// isNotificationEnabled reports whether the user wants notifications of the given type.
+func (p *Plugin) isNotificationEnabled(userID, notificationType string) bool {
+	info, apiErr := p.getGitHubUserInfo(userID)
+	if apiErr != nil {
+		p.client.Log.Debug("Failed to get notification settings", "userID", userID, "error", apiErr.Message)
+		return true
+	}
+
+	return info.Settings == nil || info.Settings.IsNotificationEnabled(notificationType)
 }
 
 func (p *Plugin) postPullRequestReviewEvent(event *github.PullRequestReviewEvent) {
@@ -848,16 +985,9 @@ func (p *Plugin) postPullRequestReviewEvent(event *github.PullRequestReviewEvent
 		return
 	}
 
-	newReviewMessage, err := renderTemplate("pullRequestReviewEvent", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
-		UserId:  p.BotUserID,
-		Type:    "custom_git_pull_review",
-		Message: newReviewMessage,
+		UserId: p.BotUserID,
+		Type:   "custom_git_pull_review",
 	}
 
 	labels := make([]string, len(event.GetPullRequest().Labels))
@@ -887,10 +1017,15 @@ func (p *Plugin) postPullRequestReviewEvent(event *github.PullRequestReviewEvent
 			continue
 		}
 
-		post.ChannelId = sub.ChannelID
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "pullRequestReviewEvent", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
 		}
+
+		post.Message = message
+		post.ChannelId = sub.ChannelID
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
@@ -902,16 +1037,9 @@ func (p *Plugin) postPullRequestReviewCommentEvent(event *github.PullRequestRevi
 		return
 	}
 
-	newReviewMessage, err := renderTemplate("newReviewComment", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
-		UserId:  p.BotUserID,
-		Type:    "custom_git_pull_review_comment",
-		Message: newReviewMessage,
+		UserId: p.BotUserID,
+		Type:   "custom_git_pull_review_comment",
 	}
 
 	repoName := strings.ToLower(repo.GetFullName())
@@ -948,10 +1076,15 @@ func (p *Plugin) postPullRequestReviewCommentEvent(event *github.PullRequestRevi
 			continue
 		}
 
-		post.ChannelId = sub.ChannelID
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "newReviewComment", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
 		}
+
+		post.Message = message
+		post.ChannelId = sub.ChannelID
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
 
@@ -968,67 +1101,17 @@ func (p *Plugin) handleCommentMentionNotification(event *github.IssueCommentEven
 		body = strings.Split(body, "\n\nOn")[0]
 	}
 
-	mentionedUsernames := parseGitHubUsernamesFromText(body)
-
-	message, err := renderTemplate("commentMentionNotification", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
-	post := &model.Post{
-		UserId:  p.BotUserID,
-		Message: message,
-		Type:    "custom_git_mention",
-	}
-
-	assignees := event.GetIssue().Assignees
-
-	for _, username := range mentionedUsernames {
-		assigneeMentioned := false
-		for _, assignee := range assignees {
-			if username == *assignee.Login {
-				assigneeMentioned = true
-				break
-			}
-		}
-
-		// This has been handled in "handleCommentAssigneeNotification" function
-		if assigneeMentioned {
-			continue
-		}
-
-		// Don't notify user of their own comment
-		if username == event.GetSender().GetLogin() {
-			continue
-		}
-
-		// Notifications for issue authors are handled separately
-		if username == event.GetIssue().GetUser().GetLogin() {
-			continue
-		}
-
-		userID := p.getGitHubToUserIDMapping(username)
-		if userID == "" {
-			continue
-		}
-
-		if event.GetRepo().GetPrivate() && !p.permissionToRepo(userID, event.GetRepo().GetFullName()) {
-			continue
-		}
-
-		channel, err := p.client.Channel.GetDirect(userID, p.BotUserID)
-		if err != nil {
-			continue
-		}
-
-		post.ChannelId = channel.Id
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error creating mention post", "error", err.Error())
-		}
+	p.notifyMentionedTeams(body, event.GetRepo(), event.GetIssue().Labels, "commentTeamMentionNotification", func(team string) interface{} {
+		return &CommentTeamMention{Team: team, IssueCommentEvent: event}
+	})
 
-		p.sendRefreshEvent(userID)
+	// Notifications for issue authors and assignees are handled separately, the latter in
+	// handleCommentAssigneeNotification
+	skip := []string{event.GetIssue().GetUser().GetLogin()}
+	for _, assignee := range event.GetIssue().Assignees {
+		skip = append(skip, assignee.GetLogin())
 	}
+	p.notifyMentionedUsers(body, event.GetRepo(), event.GetIssue().Labels, event.GetSender(), skip, "commentMentionNotification", event)
 }
 
 func (p *Plugin) handleCommentAuthorNotification(event *github.IssueCommentEvent) {
@@ -1072,7 +1155,15 @@ func (p *Plugin) handleCommentAuthorNotification(event *github.IssueCommentEvent
 		return
 	}
 
-	message, err := renderTemplate(templateName, event)
+	if !p.isNotificationEnabled(authorUserID, notificationTypeComments) {
+		return
+	}
+
+	if p.notificationMutedByReceiver(authorUserID, event.GetRepo().GetFullName(), event.GetIssue().Labels) {
+		return
+	}
+
+	message, err := p.renderUserTemplate(authorUserID, templateName, event)
 	if err != nil {
 		p.client.Log.Warn("Failed to render template", "error", err.Error())
 		return
@@ -1151,7 +1242,19 @@ func (p *Plugin) handleCommentAssigneeNotification(event *github.IssueCommentEve
 			continue
 		}
 
-		message, err := renderTemplate(template, event)
+		notificationType := notificationTypeComments
+		if usernameMentioned {
+			notificationType = notificationTypeMentions
+		}
+		if !p.isNotificationEnabled(assigneeID, notificationType) {
+			continue
+		}
+
+		if p.notificationMutedByReceiver(assigneeID, repoName, event.GetIssue().Labels) {
+			continue
+		}
+
+		message, err := p.renderUserTemplate(assigneeID, template, event)
 		if err != nil {
 			p.client.Log.Warn("Failed to render template", "error", err.Error())
 			continue
@@ -1174,6 +1277,11 @@ func (p *Plugin) handlePullRequestNotification(event *github.PullRequestEvent) {
 
 	switch event.GetAction() {
 	case "review_requested":
+		if event.GetRequestedTeam() != nil {
+			p.handleTeamReviewRequest(event)
+			return
+		}
+
 		requestedReviewer = event.GetRequestedReviewer().GetLogin()
 		if requestedReviewer == sender {
 			return
@@ -1182,6 +1290,9 @@ func (p *Plugin) handlePullRequestNotification(event *github.PullRequestEvent) {
 		if isPrivate && !p.permissionToRepo(requestedUserID, repoName) {
 			requestedUserID = ""
 		}
+		if requestedUserID != "" && !p.isNotificationEnabled(requestedUserID, notificationTypeReviewRequests) {
+			requestedUserID = ""
+		}
 	case actionClosed:
 		if author == sender {
 			return
@@ -1207,23 +1318,31 @@ func (p *Plugin) handlePullRequestNotification(event *github.PullRequestEvent) {
 		if isPrivate && !p.permissionToRepo(assigneeUserID, repoName) {
 			assigneeUserID = ""
 		}
+		if assigneeUserID != "" && !p.isNotificationEnabled(assigneeUserID, notificationTypeAssignments) {
+			assigneeUserID = ""
+		}
 	default:
 		p.client.Log.Debug("Unhandled event action", "action", event.GetAction())
 		return
 	}
 
-	message, err := renderTemplate("pullRequestNotification", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
+	labels := event.GetPullRequest().Labels
+	requestedUserID = p.filterMutedReceiver(requestedUserID, repoName, labels)
+	authorUserID = p.filterMutedReceiver(authorUserID, repoName, labels)
+	assigneeUserID = p.filterMutedReceiver(assigneeUserID, repoName, labels)
 
 	if len(requestedUserID) > 0 {
+		message, err := p.renderUserTemplate(requestedUserID, "pullRequestNotification", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
 		p.CreateBotDMPost(requestedUserID, message, "custom_git_review_request")
 		p.sendRefreshEvent(requestedUserID)
 	}
 
-	p.postIssueNotification(message, authorUserID, assigneeUserID)
+	p.postIssueNotification("pullRequestNotification", event, authorUserID, assigneeUserID)
 }
 
 func (p *Plugin) handleIssueNotification(event *github.IssuesEvent) {
@@ -1235,7 +1354,6 @@ func (p *Plugin) handleIssueNotification(event *github.IssuesEvent) {
 	repoName := event.GetRepo().GetFullName()
 	isPrivate := event.GetRepo().GetPrivate()
 
-	message := ""
 	authorUserID := ""
 	assigneeUserID := ""
 
@@ -1259,32 +1377,52 @@ func (p *Plugin) handleIssueNotification(event *github.IssuesEvent) {
 		if isPrivate && !p.permissionToRepo(assigneeUserID, repoName) {
 			assigneeUserID = ""
 		}
+		if assigneeUserID != "" && !p.isNotificationEnabled(assigneeUserID, notificationTypeAssignments) {
+			assigneeUserID = ""
+		}
 	default:
 		p.client.Log.Debug("Unhandled event action", "action", event.GetAction())
 		return
 	}
 
-	message, err := renderTemplate("issueNotification", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
+	labels := event.GetIssue().Labels
+	authorUserID = p.filterMutedReceiver(authorUserID, repoName, labels)
+	assigneeUserID = p.filterMutedReceiver(assigneeUserID, repoName, labels)
 
-	p.postIssueNotification(message, authorUserID, assigneeUserID)
+	p.postIssueNotification("issueNotification", event, authorUserID, assigneeUserID)
 }
 
-func (p *Plugin) postIssueNotification(message, authorUserID, assigneeUserID string) {
+// postIssueNotification renders the given notification template for the author and the assignee
+// in their language and sends it to them.
+func (p *Plugin) postIssueNotification(templateName string, event interface{}, authorUserID, assigneeUserID string) {
 	if len(authorUserID) > 0 {
+		message, err := p.renderUserTemplate(authorUserID, templateName, event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
 		p.CreateBotDMPost(authorUserID, message, "custom_git_author")
 		p.sendRefreshEvent(authorUserID)
 	}
 
 	if len(assigneeUserID) > 0 {
+		message, err := p.renderUserTemplate(assigneeUserID, templateName, event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
+		}
+
 		p.CreateBotDMPost(assigneeUserID, message, "custom_git_assigned")
 		p.sendRefreshEvent(assigneeUserID)
 	}
 }
 
+// renderUserTemplate renders a notification template in the language of the given user.
+func (p *Plugin) renderUserTemplate(userID, name string, data interface{}) (string, error) {
+	return renderLocalizedTemplate(p.getUserLocale(userID), name, data)
+}
+
 func (p *Plugin) handlePullRequestReviewNotification(event *github.PullRequestReviewEvent) {
 	author := event.GetPullRequest().GetUser().GetLogin()
 	if author == event.GetSender().GetLogin() {
@@ -1304,7 +1442,15 @@ func (p *Plugin) handlePullRequestReviewNotification(event *github.PullRequestRe
 		return
 	}
 
-	message, err := renderTemplate("pullRequestReviewNotification", event)
+	if !p.isNotificationEnabled(authorUserID, notificationTypeReviewResults) {
+		return
+	}
+
+	if p.notificationMutedByReceiver(authorUserID, event.GetRepo().GetFullName(), event.GetPullRequest().Labels) {
+		return
+	}
+
+	message, err := p.renderUserTemplate(authorUserID, "pullRequestReviewNotification", event)
 	if err != nil {
 		p.client.Log.Warn("Failed to render template", "error", err.Error())
 		return
@@ -1323,16 +1469,9 @@ func (p *Plugin) postStarEvent(event *github.StarEvent) {
 		return
 	}
 
-	newStarMessage, err := renderTemplate("newRepoStar", event)
-	if err != nil {
-		p.client.Log.Warn("Failed to render template", "error", err.Error())
-		return
-	}
-
 	post := &model.Post{
-		UserId:  p.BotUserID,
-		Type:    "custom_git_star",
-		Message: newStarMessage,
+		UserId: p.BotUserID,
+		Type:   "custom_git_star",
 	}
 
 	for _, sub := range subs {
@@ -1344,9 +1483,14 @@ func (p *Plugin) postStarEvent(event *github.StarEvent) {
 			continue
 		}
 
-		post.ChannelId = sub.ChannelID
-		if err = p.client.Post.CreatePost(post); err != nil {
-			p.client.Log.Warn("Error webhook post", "post", post, "error", err.Error())
+		message, err := renderLocalizedTemplate(p.getSubscriptionLocale(sub), "newRepoStar", event)
+		if err != nil {
+			p.client.Log.Warn("Failed to render template", "error", err.Error())
+			return
 		}
+
+		post.Message = message
+		post.ChannelId = sub.ChannelID
+		p.createSubscriptionPost(sub, post, event)
 	}
 }
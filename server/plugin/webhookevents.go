@@ -0,0 +1,38 @@
+package plugin
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// webhookEventTypes creates the events of the webhook event types unknown to the GitHub client
+// library, or only known without fields used here, keyed by the value of their X-GitHub-Event
+// header.
+var webhookEventTypes = map[string]func() interface{}{
+	"discussion":            func() interface{} { return &DiscussionEvent{} },
+	"discussion_comment":    func() interface{} { return &DiscussionCommentEvent{} },
+	"dependabot_alert":      func() interface{} { return &DependabotAlertEvent{} },
+	"code_scanning_alert":   func() interface{} { return &CodeScanningAlertEvent{} },
+	"secret_scanning_alert": func() interface{} { return &SecretScanningAlertEvent{} },
+	"milestone":             func() interface{} { return &MilestoneEvent{} },
+	"projects_v2_item":      func() interface{} { return &ProjectsV2ItemEvent{} },
+	"member":                func() interface{} { return &MemberEvent{} },
+}
+
+// parseWebhook parses the payload of a webhook event of the given type, like github.ParseWebHook,
+// but also knows the event types listed in webhookEventTypes.
+func parseWebhook(eventType string, payload []byte) (interface{}, error) {
+	newEvent, ok := webhookEventTypes[eventType]
+	if !ok {
+		return github.ParseWebHook(eventType, payload)
+	}
+
+	event := newEvent()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s event", eventType)
+	}
+
+	return event, nil
+}